@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fieldAlias maps a deprecated JSON key to the field that replaced it
+type fieldAlias struct {
+	Old string
+	New string
+}
+
+// fieldAliases keeps configs written with old key names loading. The old
+// key is read into the new field, and the next save writes the new name.
+var fieldAliases = []fieldAlias{
+	{Old: "monitoring.check_interval", New: "monitoring.check_interval_minutes"},
+}
+
+// warnedAliases remembers the deprecation warnings already printed, so a
+// config read several times in one run warns once
+var warnedAliases = map[string]bool{}
+
+// migrateAliases rewrites deprecated keys in a config document to their
+// current names. Documents without any deprecated key are returned as is.
+func migrateAliases(path string, data []byte) ([]byte, error) {
+	found := false
+	for _, alias := range fieldAliases {
+		parts := strings.Split(alias.Old, ".")
+		if bytes.Contains(data, []byte(`"`+parts[len(parts)-1]+`"`)) {
+			found = true
+		}
+	}
+	if !found {
+		return data, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	raw := map[string]interface{}{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if !applyFieldAliases(path, raw) {
+		return data, nil
+	}
+	return json.Marshal(raw)
+}
+
+// applyFieldAliases moves deprecated keys in raw to their current names and
+// reports whether anything changed. When both names are set the current
+// one wins.
+func applyFieldAliases(path string, raw map[string]interface{}) bool {
+	changed := false
+	for _, alias := range fieldAliases {
+		oldParts, newParts := strings.Split(alias.Old, "."), strings.Split(alias.New, ".")
+		oldParent, ok := rawParent(raw, oldParts)
+		if !ok {
+			continue
+		}
+		value, ok := oldParent[oldParts[len(oldParts)-1]]
+		if !ok {
+			continue
+		}
+		delete(oldParent, oldParts[len(oldParts)-1])
+		changed = true
+
+		newParent, _ := rawParent(raw, newParts)
+		if _, exists := newParent[newParts[len(newParts)-1]]; exists {
+			warnAlias(path, alias, fmt.Sprintf("%s is deprecated and ignored because %s is also set", alias.Old, alias.New))
+			continue
+		}
+		if newParent == nil {
+			setRawValue(raw, alias.New, value)
+		} else {
+			newParent[newParts[len(newParts)-1]] = value
+		}
+		warnAlias(path, alias, fmt.Sprintf("%s is deprecated, use %s (it is renamed on the next save)", alias.Old, alias.New))
+	}
+	return changed
+}
+
+// rawParent returns the object holding the last part of a dotted key
+func rawParent(raw map[string]interface{}, parts []string) (map[string]interface{}, bool) {
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := raw[part].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		raw = next
+	}
+	return raw, true
+}
+
+// currentKey maps a deprecated key typed on the command line to the field
+// that replaced it, warning once per run as loading a config does, and
+// reports whether it was deprecated
+func currentKey(key string) (string, bool) {
+	for _, alias := range fieldAliases {
+		if normalizeKey(key) != normalizeKey(alias.Old) {
+			continue
+		}
+		if !warnedAliases["\x00"+alias.Old] {
+			warnedAliases["\x00"+alias.Old] = true
+			fmt.Fprintf(os.Stderr, "⚠️  %s is deprecated, use %s\n", alias.Old, alias.New)
+		}
+		return alias.New, true
+	}
+	return key, false
+}
+
+func warnAlias(path string, alias fieldAlias, message string) {
+	if path == "" || warnedAliases[path+"\x00"+alias.Old] {
+		return
+	}
+	warnedAliases[path+"\x00"+alias.Old] = true
+	fmt.Fprintf(os.Stderr, "⚠️  %s: %s\n", path, message)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeprecatedKeyLoadsAndWarns(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.editConfig(func(config map[string]interface{}) {
+		monitoring := section(config, "monitoring")
+		delete(monitoring, "check_interval_minutes")
+		monitoring["check_interval"] = 15
+	})
+
+	out := e.mustRun("get", "monitoring.check_interval_minutes")
+	assertContains(t, out, "15", "monitoring.check_interval is deprecated, use monitoring.check_interval_minutes")
+	if n := strings.Count(out, "is deprecated"); n != 1 {
+		t.Errorf("warned %d times, want once:\n%s", n, out)
+	}
+
+	// the next save writes the current name
+	e.mustRun("set", "agent.name", "renamed-agent")
+	saved := e.read(e.configPath())
+	assertContains(t, saved, `"check_interval_minutes": 15`)
+	assertNotContains(t, saved, `"check_interval":`)
+	assertNotContains(t, e.mustRun("get", "monitoring.check_interval_minutes"), "deprecated")
+}
+
+func TestApplyFieldAliases(t *testing.T) {
+	raw := map[string]interface{}{"monitoring": map[string]interface{}{"check_interval": 15}}
+	if !applyFieldAliases("", raw) {
+		t.Fatal("no alias applied")
+	}
+	monitoring := raw["monitoring"].(map[string]interface{})
+	if _, ok := monitoring["check_interval"]; ok || monitoring["check_interval_minutes"] != 15 {
+		t.Errorf("monitoring = %v", monitoring)
+	}
+
+	// the current name wins when both are set
+	raw = map[string]interface{}{"monitoring": map[string]interface{}{"check_interval": 15, "check_interval_minutes": 5}}
+	applyFieldAliases("", raw)
+	monitoring = raw["monitoring"].(map[string]interface{})
+	if _, ok := monitoring["check_interval"]; ok || monitoring["check_interval_minutes"] != 5 {
+		t.Errorf("monitoring = %v", monitoring)
+	}
+
+	if applyFieldAliases("", map[string]interface{}{"monitoring": map[string]interface{}{"check_interval_minutes": 5}}) {
+		t.Error("a current config reported a change")
+	}
+}
+
+func TestDeprecatedKeyOnCommandLine(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	warning := "monitoring.check_interval is deprecated, use monitoring.check_interval_minutes"
+
+	out := e.mustRun("set", "monitoring.check_interval", "10")
+	assertContains(t, out, warning, "Set monitoring.check_interval_minutes")
+	if strings.Count(out, "deprecated") != 1 {
+		t.Errorf("warned more than once:\n%s", out)
+	}
+	out = e.mustRun("get", "monitoring.check_interval")
+	assertContains(t, out, warning, "10")
+	assertNotContains(t, out, "Unknown key", "💡")
+	assertContains(t, e.mustRun("get", "monitoring.check_interval", "--exists", "--print"), "true")
+	assertNotContains(t, e.read(e.configPath()), `"check_interval":`)
+}
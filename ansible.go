@@ -0,0 +1,112 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// ansibleVarName names a config key as an Ansible variable: agent_ plus the
+// key with dots as underscores (agent.name is agent_name, not agent_agent_name)
+func ansibleVarName(key string) string {
+	return "agent_" + strings.ReplaceAll(strings.TrimPrefix(key, "agent."), ".", "_")
+}
+
+// exportAnsibleVars renders a vars file for include_vars. With
+// --vault-password-file the secrets go to a separate file encrypted with
+// Ansible Vault instead.
+func exportAnsibleVars(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	var password []byte
+	if opts.VaultPasswordFile != "" {
+		data, err := os.ReadFile(expandPath(opts.VaultPasswordFile))
+		if err != nil {
+			return nil, fmt.Errorf("reading vault password: %v", err)
+		}
+		// ansible-vault strips surrounding whitespace from password files too
+		password = []byte(strings.TrimSpace(string(data)))
+		if len(password) == 0 {
+			return nil, fmt.Errorf("vault password file %s is empty", opts.VaultPasswordFile)
+		}
+	}
+
+	var vars, secrets strings.Builder
+	header := fmt.Sprintf("# Generated by agent-config-manager v%s\n---\n", version)
+	vars.WriteString(header)
+	secrets.WriteString("---\n")
+	for _, leaf := range exportLeaves(&config) {
+		line := fmt.Sprintf("%s: %s\n", ansibleVarName(leaf.Key), ansibleValue(leaf.Value))
+		if password != nil && isSecretKey(leaf.Key) {
+			secrets.WriteString(line)
+			continue
+		}
+		vars.WriteString(line)
+	}
+
+	files := []exportFile{{Name: "ansible-vars.yml", Data: []byte(vars.String())}}
+	if password != nil {
+		vault, err := ansibleVaultEncrypt([]byte(secrets.String()), password)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, exportFile{Name: "ansible-vault.yml", Data: vault, Volatile: true})
+	}
+	return files, nil
+}
+
+// ansibleValue renders a leaf as YAML. Lists and maps use JSON, which YAML
+// reads as flow collections.
+func ansibleValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return yamlQuote(v.String())
+	default:
+		return leafString(v)
+	}
+}
+
+// ansibleVaultEncrypt encrypts data in the Ansible Vault 1.1 AES256 format:
+// PBKDF2-SHA256 derives the AES-256-CTR key, HMAC-SHA256 key and IV from the
+// password and a random salt
+func ansibleVaultEncrypt(data, password []byte) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	derived := pbkdf2.Key(password, salt, 10000, 80, sha256.New)
+	cipherKey, hmacKey, iv := derived[:32], derived[32:64], derived[64:]
+
+	// Vault pads with PKCS#7 even though CTR doesn't need it
+	pad := aes.BlockSize - len(data)%aes.BlockSize
+	padded := append(append([]byte{}, data...), strings.Repeat(string(rune(pad)), pad)...)
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, padded)
+
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(ciphertext)
+
+	body := hex.EncodeToString(salt) + "\n" + hex.EncodeToString(mac.Sum(nil)) + "\n" + hex.EncodeToString(ciphertext)
+	encoded := hex.EncodeToString([]byte(body))
+
+	var out strings.Builder
+	out.WriteString("$ANSIBLE_VAULT;1.1;AES256\n")
+	for len(encoded) > 80 {
+		out.WriteString(encoded[:80] + "\n")
+		encoded = encoded[80:]
+	}
+	out.WriteString(encoded + "\n")
+	return []byte(out.String()), nil
+}
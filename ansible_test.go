@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/pbkdf2"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAnsibleVarName(t *testing.T) {
+	for key, want := range map[string]string{
+		"agent.name":                "agent_name",
+		"wallet.address":            "agent_wallet_address",
+		"api_keys.etherscan":        "agent_api_keys_etherscan",
+		"monitoring.rate_limit.rps": "agent_monitoring_rate_limit_rps",
+	} {
+		if got := ansibleVarName(key); got != want {
+			t.Errorf("ansibleVarName(%s) = %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestExportAnsibleVarsPlain(t *testing.T) {
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-key-abcd"
+	files, err := exportAnsibleVars(config, exportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "ansible-vars.yml" {
+		t.Fatalf("files = %v", files)
+	}
+	data := string(files[0].Data)
+	assertNotContains(t, data, "$ANSIBLE_VAULT")
+
+	vars := map[string]interface{}{}
+	if err := yaml.Unmarshal(files[0].Data, &vars); err != nil {
+		t.Fatalf("not YAML: %v\n%s", err, data)
+	}
+	for name, want := range map[string]interface{}{
+		"agent_name":               config.Agent.Name,
+		"agent_wallet_address":     config.Wallet.Address,
+		"agent_wallet_daily_limit": 0.5,
+		"agent_api_keys_etherscan": "etherscan-secret-key-abcd",
+	} {
+		if vars[name] != want {
+			t.Errorf("%s = %#v, want %#v", name, vars[name], want)
+		}
+	}
+	for name := range vars {
+		if !strings.HasPrefix(name, "agent_") {
+			t.Errorf("variable %s is not agent_ prefixed", name)
+		}
+	}
+}
+
+// ansibleVaultDecrypt reverses ansibleVaultEncrypt, checking the HMAC
+func ansibleVaultDecrypt(t *testing.T, vault, password []byte) []byte {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(string(vault)), "\n")
+	if lines[0] != "$ANSIBLE_VAULT;1.1;AES256" {
+		t.Fatalf("vault header = %q", lines[0])
+	}
+	body, err := hex.DecodeString(strings.Join(lines[1:], ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(string(body), "\n")
+	salt, _ := hex.DecodeString(parts[0])
+	sum, _ := hex.DecodeString(parts[1])
+	ciphertext, _ := hex.DecodeString(parts[2])
+
+	derived := pbkdf2.Key(password, salt, 10000, 80, sha256.New)
+	mac := hmac.New(sha256.New, derived[32:64])
+	mac.Write(ciphertext)
+	if !hmac.Equal(mac.Sum(nil), sum) {
+		t.Fatal("vault HMAC doesn't match")
+	}
+	block, _ := aes.NewCipher(derived[:32])
+	plain := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, derived[64:]).XORKeyStream(plain, ciphertext)
+	return plain[:len(plain)-int(plain[len(plain)-1])]
+}
+
+func TestExportAnsibleVault(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "vault-pass")
+	if err := os.WriteFile(passwordFile, []byte("correct horse\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-key-abcd"
+
+	files, err := exportAnsibleVars(config, exportOptions{VaultPasswordFile: passwordFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[1].Name != "ansible-vault.yml" {
+		t.Fatalf("files = %v", files)
+	}
+	assertNotContains(t, string(files[0].Data), "agent_api_keys_etherscan", "etherscan-secret-key-abcd")
+	assertNotContains(t, string(files[1].Data), "etherscan-secret-key-abcd")
+
+	secrets := string(ansibleVaultDecrypt(t, files[1].Data, []byte("correct horse")))
+	assertContains(t, secrets, "---\n", `agent_api_keys_etherscan: "etherscan-secret-key-abcd"`)
+
+	os.WriteFile(passwordFile, []byte("  \n"), 0600)
+	if _, err := exportAnsibleVars(config, exportOptions{VaultPasswordFile: passwordFile}); err == nil {
+		t.Error("exported with an empty vault password")
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultMaxBackups is how many good backups are kept; ACM_MAX_BACKUPS
+// changes it
+const defaultMaxBackups = 20
+
+func maxBackups() int {
+	return int(envLimit("ACM_MAX_BACKUPS", defaultMaxBackups, parseCount))
+}
+
+// backupFile is one backup of the config
+type backupFile struct {
+	Name string
+	Path string
+}
+
+var warnedBackups = map[string]bool{}
+
+// listBackups returns the backups that still parse as a config, newest
+// first. A truncated or corrupt backup is skipped with a warning, so it is
+// neither offered for restore nor counted toward rotation.
+func listBackups() []backupFile {
+	entries, _ := os.ReadDir(backupDir())
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "config-") {
+			continue
+		}
+		path := filepath.Join(backupDir(), name)
+		if err := checkBackup(path); err != nil {
+			if !warnedBackups[name] {
+				warnedBackups[name] = true
+				fmt.Fprintf(os.Stderr, "⚠️  Skipping corrupt backup %s: %v\n", name, err)
+			}
+			continue
+		}
+		backups = append(backups, backupFile{Name: name, Path: path})
+	}
+	// Timestamped names sort chronologically
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Name > backups[j].Name })
+	return backups
+}
+
+// checkBackup reports whether the backup at path holds a config that loads
+func checkBackup(path string) error {
+	data, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	_, err = parseConfig(path, data)
+	return err
+}
+
+// rotateBackups removes the oldest good backups beyond maxBackups
+func rotateBackups() {
+	backups := listBackups()
+	for _, b := range backups[min(len(backups), maxBackups()):] {
+		os.Remove(b.Path)
+	}
+}
+
+// verifyBackup re-reads a freshly written backup and checks it matches data
+func verifyBackup(path string, data []byte) error {
+	written, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(written, data) {
+		os.Remove(path)
+		return fmt.Errorf("backup %s doesn't match the config it copies", path)
+	}
+	return nil
+}
+
+// restoreCommand handles `acm restore [backup]`: it replaces the config with
+// the named backup, or the newest good one, after checking it loads. The
+// current config is backed up first.
+func restoreCommand(args []string) {
+	positional := parseFlags(flag.NewFlagSet("restore", flag.ExitOnError), args)
+	if len(positional) > 1 {
+		fmt.Println("Usage: acm restore [backup]")
+		os.Exit(1)
+	}
+
+	var chosen backupFile
+	if len(positional) == 1 {
+		name := filepath.Base(positional[0])
+		chosen = backupFile{Name: name, Path: filepath.Join(backupDir(), name)}
+		if _, err := os.Stat(chosen.Path); err != nil {
+			fmt.Printf("❌ Backup %s not found (list them with 'acm backups')\n", name)
+			os.Exit(1)
+		}
+		if err := checkBackup(chosen.Path); err != nil {
+			fmt.Printf("❌ Backup %s is corrupt and can't be restored: %v\n", name, err)
+			os.Exit(1)
+		}
+	} else {
+		backups := listBackups()
+		if len(backups) == 0 {
+			fmt.Println("❌ No usable backups")
+			os.Exit(1)
+		}
+		chosen = backups[0]
+	}
+	data, err := os.ReadFile(chosen.Path)
+	if err != nil {
+		fmt.Printf("❌ Failed to read backup: %v\n", err)
+		os.Exit(1)
+	}
+	confirmOrExit(fmt.Sprintf("Replace the config with backup %s?", chosen.Name))
+
+	if backup, err := backupConfig(); err != nil {
+		fmt.Printf("❌ Failed to back up the current config: %v\n", err)
+		os.Exit(1)
+	} else if backup != "" {
+		fmt.Printf("💾 Backed up current config to %s\n", backup)
+	}
+	if err := writeFileAtomic(getConfigPath(), data, 0600); err != nil {
+		fmt.Printf("❌ Failed to write config: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Restored backup %s\n", chosen.Name)
+}
+
+// backupsCommand lists the good backups, newest first
+func backupsCommand() {
+	backups := listBackups()
+	if len(backups) == 0 {
+		fmt.Println("No backups yet. acm takes one before replacing the config.")
+		return
+	}
+	for _, b := range backups {
+		fmt.Printf("  %s\n", b.Name)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeBackup places a backup with the given timestamp and contents
+func (e *testEnv) writeBackup(stamp, content string) string {
+	e.t.Helper()
+	name := "config-" + stamp + ".json"
+	e.write(filepath.Join(e.stateDir(), "backups", name), content)
+	return name
+}
+
+func TestRestoreSkipsTruncatedBackup(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "agent.name", "backed-up")
+	good := e.writeBackup("20260101T000000.000Z", e.read(e.configPath()))
+	full := e.read(e.configPath())
+	truncated := e.writeBackup("20260102T000000.000Z", full[:len(full)/2])
+	e.mustRun("set", "agent.name", "current")
+
+	out := e.mustRun("backups")
+	assertContains(t, out, good, "Skipping corrupt backup "+truncated)
+	if strings.Contains(strings.ReplaceAll(out, "Skipping corrupt backup "+truncated, ""), truncated) {
+		t.Errorf("the truncated backup is offered:\n%s", out)
+	}
+
+	// the newest good backup is restored, not the newer truncated one
+	out = e.mustRun("--yes", "restore")
+	assertContains(t, out, "Restored backup "+good, "Backed up current config to ")
+	assertContains(t, e.mustRun("get", "agent.name"), "backed-up")
+
+	out = e.mustFail("--yes", "restore", truncated)
+	assertContains(t, out, "Backup "+truncated+" is corrupt and can't be restored")
+	assertContains(t, e.mustRun("get", "agent.name"), "backed-up")
+	assertContains(t, e.mustFail("--yes", "restore", "config-nope.json"), "Backup config-nope.json not found")
+}
+
+func TestRestoreWithoutGoodBackups(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustRun("backups"), "No backups yet")
+	e.writeBackup("20260102T000000.000Z", `{"version": "0.1.0", "agent": {`)
+	assertContains(t, e.mustFail("--yes", "restore"), "No usable backups")
+}
+
+func TestBackupRotation(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.setenv("ACM_MAX_BACKUPS", "2")
+	config := e.read(e.configPath())
+	oldest := e.writeBackup("20200101T000000.000Z", config)
+	e.writeBackup("20200102T000000.000Z", config)
+	corrupt := e.writeBackup("20200103T000000.000Z", "{")
+
+	// restore takes a backup of the current config, which rotates
+	e.mustRun("--yes", "restore")
+	var kept []string
+	for _, line := range strings.Split(e.mustRun("backups"), "\n") {
+		if strings.HasPrefix(line, "  config-") {
+			kept = append(kept, strings.TrimSpace(line))
+		}
+	}
+	if len(kept) != 2 || strings.Contains(strings.Join(kept, " "), oldest) {
+		t.Errorf("kept backups %v, want the newest 2 good ones", kept)
+	}
+	// a corrupt backup doesn't count toward the limit and is left for inspection
+	files, _ := filepath.Glob(filepath.Join(e.stateDir(), "backups", corrupt))
+	if len(files) != 1 {
+		t.Errorf("corrupt backup removed by rotation")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// baselineIssue is one accepted finding in a `validate --baseline` file.
+// Message is informational; only Rule and Field identify the issue.
+type baselineIssue struct {
+	Rule    string `json:"rule"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// baselineFile records the findings a team has accepted
+type baselineFile struct {
+	Issues []baselineIssue `json:"issues"`
+}
+
+// issueID identifies a finding across runs: its rule and field, or its rule
+// and message for findings about no particular field (such as a hook
+// failure)
+func issueID(rule, field, message string) string {
+	if field == "" {
+		return rule + "\x00\x00" + message
+	}
+	return rule + "\x00" + field
+}
+
+func readBaseline(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline baselineFile
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("%s is not a baseline file: %v", path, err)
+	}
+	known := map[string]bool{}
+	for _, issue := range baseline.Issues {
+		known[issueID(issue.Rule, issue.Field, issue.Message)] = true
+	}
+	return known, nil
+}
+
+// writeBaseline records findings as accepted, sorted so the file diffs
+// cleanly when regenerated
+func writeBaseline(path string, findings []finding) error {
+	baseline := baselineFile{Issues: []baselineIssue{}}
+	for _, f := range findings {
+		baseline.Issues = append(baseline.Issues, baselineIssue{Rule: f.Rule, Field: f.Field, Message: f.Message})
+	}
+	sort.SliceStable(baseline.Issues, func(i, j int) bool {
+		a, b := baseline.Issues[i], baseline.Issues[j]
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Field < b.Field
+	})
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'), 0644)
+}
+
+// newFindings drops the findings known to the baseline and returns the rest
+// with the number suppressed
+func newFindings(findings []finding, known map[string]bool) ([]finding, int) {
+	fresh := []finding{}
+	for _, f := range findings {
+		if !known[issueID(f.Rule, f.Field, f.Message)] {
+			fresh = append(fresh, f)
+		}
+	}
+	return fresh, len(findings) - len(fresh)
+}
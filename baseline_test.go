@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateBaseline(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	baseline := filepath.Join(e.home, "baseline.json")
+
+	assertContains(t, e.mustRun("validate", "--baseline", baseline, "--update-baseline"), "Recorded 2 issue(s) in "+baseline)
+	var recorded baselineFile
+	if err := json.Unmarshal([]byte(e.read(baseline)), &recorded); err != nil || len(recorded.Issues) != 2 {
+		t.Fatalf("baseline = %s, %v", e.read(baseline), err)
+	}
+	if issue := recorded.Issues[0]; issue.Rule != "api-keys" || issue.Field != "api_keys.basescan" {
+		t.Errorf("first issue = %+v, want the basescan key sorted first", issue)
+	}
+
+	// accepted issues are suppressed
+	out := e.mustRun("validate", "--baseline", baseline)
+	assertContains(t, out, "2 known issue(s) in "+baseline+" not shown")
+	assertNotContains(t, out, "Etherscan API key not set")
+
+	// a new one surfaces and fails the run, even as a warning
+	e.mustRun("set", "monitoring.webhook_url", "https://hooks.example.com/alert")
+	out = e.mustFail("validate", "--baseline", baseline)
+	assertContains(t, out, "alerts to the webhook are unsigned", "2 known issue(s)")
+	assertNotContains(t, out, "Basescan API key not set")
+
+	out = e.mustFail("validate", "--baseline", baseline, "--json")
+	var report validationReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, out)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Rule != "monitoring" || report.Suppressed != 2 {
+		t.Errorf("report = %+v", report)
+	}
+
+	assertContains(t, e.mustFail("validate", "--update-baseline"), "--update-baseline needs --baseline")
+	assertContains(t, e.mustFail("validate", "--baseline", filepath.Join(e.home, "missing.json")), "Failed to read baseline")
+}
+
+func TestNewFindingsIdentity(t *testing.T) {
+	known := map[string]bool{
+		issueID("api-keys", "api_keys.etherscan", "Etherscan API key not set"): true,
+		issueID("hooks", "", "hook ./check.sh failed"):                         true,
+	}
+	findings := []finding{
+		// the same rule and field with a reworded message is still known
+		warnf("api-keys", "Etherscan key missing").on("api_keys.etherscan"),
+		warnf("api-keys", "Etherscan API key not set").on("api_keys.basescan"),
+		errorf("hooks", "hook ./check.sh failed"),
+		errorf("hooks", "hook ./other.sh failed"),
+	}
+	fresh, suppressed := newFindings(findings, known)
+	if suppressed != 2 || len(fresh) != 2 || fresh[0].Field != "api_keys.basescan" || fresh[1].Message != "hook ./other.sh failed" {
+		t.Errorf("fresh = %+v, suppressed %d", fresh, suppressed)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// caddyHostPattern accepts a hostname, optionally a wildcard or with a port
+var caddyHostPattern = regexp.MustCompile(`^(\*\.)?[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?(:[0-9]+)?$`)
+
+// exportCaddy renders a Caddyfile site block proxying --host to the local
+// dashboard. Caddy obtains the TLS certificate for the host itself.
+func exportCaddy(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	m := config.Monitoring
+	if !m.DashboardEnabled {
+		return nil, errors.New("the dashboard is disabled (monitoring.dashboard_enabled is false)")
+	}
+	if m.DashboardPort < 1 || m.DashboardPort > 65535 {
+		return nil, fmt.Errorf("invalid dashboard port %d", m.DashboardPort)
+	}
+	if opts.Host == "" {
+		return nil, errors.New("--host is required, e.g. --host dashboard.example.com")
+	}
+	if !caddyHostPattern.MatchString(opts.Host) {
+		return nil, fmt.Errorf("invalid --host %q", opts.Host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by agent-config-manager v%s\n", version)
+	fmt.Fprintf(&b, "# Dashboard for %s\n", strings.ReplaceAll(config.Agent.Name, "\n", " "))
+	fmt.Fprintf(&b, "%s {\n", opts.Host)
+	fmt.Fprintf(&b, "\treverse_proxy 127.0.0.1:%d\n", m.DashboardPort)
+	b.WriteString("}\n")
+
+	return []exportFile{{Name: "Caddyfile.dashboard", Data: []byte(b.String())}}, nil
+}
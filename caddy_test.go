@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportCaddy(t *testing.T) {
+	config := defaultConfig()
+	config.Monitoring.DashboardEnabled = true
+	config.Monitoring.DashboardPort = 9123
+	files, err := exportCaddy(config, exportOptions{Host: "dashboard.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files[0].Name != "Caddyfile.dashboard" {
+		t.Errorf("file name = %s", files[0].Name)
+	}
+	assertContains(t, string(files[0].Data),
+		"# Dashboard for Arithmos",
+		"dashboard.example.com {\n\treverse_proxy 127.0.0.1:9123\n}\n",
+	)
+}
+
+func TestExportCaddyErrors(t *testing.T) {
+	config := defaultConfig()
+	config.Monitoring.DashboardEnabled = true
+	for _, host := range []string{"", "example.com {", "exa mple.com", "-bad.example.com"} {
+		if _, err := exportCaddy(config, exportOptions{Host: host}); err == nil {
+			t.Errorf("exported a Caddyfile for host %q", host)
+		}
+	}
+	for _, host := range []string{"*.example.com", "localhost:8443"} {
+		if _, err := exportCaddy(config, exportOptions{Host: host}); err != nil {
+			t.Errorf("host %q: %v", host, err)
+		}
+	}
+
+	config.Monitoring.DashboardEnabled = false
+	if _, err := exportCaddy(config, exportOptions{Host: "dashboard.example.com"}); err == nil {
+		t.Error("exported a Caddyfile for a disabled dashboard")
+	}
+}
+
+func TestExportCaddyCommand(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.dashboard_enabled", "true")
+	assertContains(t, e.mustFail("export", "--format", "caddy"), "--host is required")
+
+	e.mustRun("export", "--format", "caddy", "--host", "agent.example.com")
+	port := strings.TrimSpace(e.mustRun("get", "monitoring.dashboard_port"))
+	assertContains(t, e.read(filepath.Join(e.exportDir(), "Caddyfile.dashboard")), "agent.example.com {", "reverse_proxy 127.0.0.1:"+port)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultCheckWorkers bounds how many network checks run at once
+const defaultCheckWorkers = 4
+
+// networkCheck is one independent probe of an external service
+type networkCheck struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// checkResult is the outcome of a networkCheck. Err is nil on success.
+type checkResult struct {
+	Name     string
+	Err      error
+	Skipped  bool // not run because --fail-fast stopped the batch
+	Duration time.Duration
+}
+
+// runNetworkChecks runs checks on a pool of workers sharing ctx and returns
+// the results in the order the checks were given, however they finish. A
+// failing check doesn't affect the others unless failFast is set, in which
+// case the remaining checks are cancelled or skipped.
+func runNetworkChecks(ctx context.Context, checks []networkCheck, workers int, failFast bool) []checkResult {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]checkResult, len(checks))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runNetworkCheck(ctx, checks[i])
+				if failFast && results[i].Err != nil && !results[i].Skipped {
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := range checks {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func runNetworkCheck(ctx context.Context, check networkCheck) checkResult {
+	result := checkResult{Name: check.Name}
+	if ctx.Err() != nil {
+		// Running out of the shared timeout is a failure; being cancelled
+		// by --fail-fast is not
+		result.Err = ctx.Err()
+		result.Skipped = errors.Is(ctx.Err(), context.Canceled)
+		return result
+	}
+	start := time.Now()
+	result.Err = check.Run(ctx)
+	result.Duration = time.Since(start)
+	// A check cut short by --fail-fast didn't fail on its own account
+	if result.Err != nil && errors.Is(ctx.Err(), context.Canceled) && errors.Is(result.Err, context.Canceled) {
+		result.Skipped = true
+	}
+	return result
+}
+
+// printCheckResults prints one line per result and reports whether all passed
+func printCheckResults(results []checkResult, timeout time.Duration) bool {
+	ok := true
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			fmt.Printf("  ⏭️  %s: skipped\n", r.Name)
+		case r.Err != nil:
+			fmt.Printf("  ❌ %s: %s\n", r.Name, describeNetworkError(r.Err, timeout))
+			ok = false
+		default:
+			fmt.Printf("  ✅ %s (%s)\n", r.Name, r.Duration.Round(time.Millisecond))
+		}
+	}
+	return ok
+}
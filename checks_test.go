@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// httpCheck is a network check that GETs url and fails on a non-200
+func httpCheck(name, url string) networkCheck {
+	client := newHTTPClient(time.Minute)
+	return networkCheck{Name: name, Run: func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}}
+}
+
+func TestRunNetworkChecksConcurrentAndOrdered(t *testing.T) {
+	const delay = 300 * time.Millisecond
+	// later checks finish first
+	var checks []networkCheck
+	for i := 4; i > 0; i-- {
+		checks = append(checks, httpCheck(fmt.Sprintf("check-%d", 5-i), slowServer(t, time.Duration(i)*delay/2).URL))
+	}
+
+	start := time.Now()
+	results := runNetworkChecks(context.Background(), checks, 4, false)
+	elapsed := time.Since(start)
+	// one after another they take 5 delays; in parallel, the slowest's 2
+	if elapsed > 4*delay {
+		t.Errorf("checks took %s, want them to run concurrently", elapsed)
+	}
+	for i, result := range results {
+		if want := fmt.Sprintf("check-%d", i+1); result.Name != want || result.Err != nil {
+			t.Errorf("results[%d] = %+v, want %s passing", i, result, want)
+		}
+	}
+}
+
+func TestRunNetworkChecksFailureIsolated(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	checks := []networkCheck{
+		httpCheck("fails", failing.URL),
+		httpCheck("slow", slowServer(t, 200*time.Millisecond).URL),
+		httpCheck("fast", slowServer(t, 0).URL),
+	}
+
+	results := runNetworkChecks(context.Background(), checks, 2, false)
+	if results[0].Err == nil {
+		t.Error("failing check passed")
+	}
+	for _, result := range results[1:] {
+		if result.Err != nil || result.Skipped {
+			t.Errorf("%s was affected by another failure: %+v", result.Name, result)
+		}
+	}
+
+	// with --fail-fast and one worker, the checks after the failure are skipped
+	results = runNetworkChecks(context.Background(), checks, 1, true)
+	if results[0].Err == nil || results[0].Skipped {
+		t.Errorf("first result = %+v, want a failure", results[0])
+	}
+	for _, result := range results[1:] {
+		if !result.Skipped || !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("%s = %+v, want skipped", result.Name, result)
+		}
+	}
+}
+
+func TestRunNetworkChecksSharedTimeout(t *testing.T) {
+	checks := []networkCheck{
+		httpCheck("a", slowServer(t, 5*time.Second).URL),
+		httpCheck("b", slowServer(t, 5*time.Second).URL),
+	}
+	ctx, cancel := networkContext(100 * time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := runNetworkChecks(ctx, checks, 1, false)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("checks took %s past the shared timeout", elapsed)
+	}
+	for _, result := range results {
+		if !errors.Is(result.Err, context.DeadlineExceeded) || result.Skipped {
+			t.Errorf("%s = %+v, want a timeout failure", result.Name, result)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cloneProfileCommand handles `acm clone-profile <src> <dst>`: it copies the
+// source profile's own config file to a new profile, so a profile that
+// extends another still does. Local overrides are machine-specific and not
+// copied.
+func cloneProfileCommand(args []string) {
+	fs := flag.NewFlagSet("clone-profile", flag.ExitOnError)
+	noSecrets := fs.Bool("no-secrets", false, "leave API keys and the webhook secret out of the clone")
+	newIdentity := fs.Bool("new-identity", false, "clear agent.id, agent.erc8004_id and wallet.address in the clone")
+	description := fs.String("description", "", "what the new profile is for")
+	positional := parseFlags(fs, args)
+	if len(positional) != 2 {
+		fmt.Println("Usage: acm clone-profile <src> <dst> [--no-secrets] [--new-identity] [--description <text>]")
+		os.Exit(1)
+	}
+
+	src, dst := positional[0], positional[1]
+	validateProfileName(src)
+	validateProfileName(dst)
+	if !profileExists(src) {
+		fmt.Printf("❌ Profile %s not found\n", src)
+		os.Exit(1)
+	}
+	if profileExists(dst) {
+		fmt.Printf("❌ Profile %s already exists\n", dst)
+		os.Exit(1)
+	}
+
+	srcPath := profileConfigPath(src)
+	data, err := readConfigFile(srcPath)
+	if err == nil {
+		_, err = parseConfig(srcPath, data)
+	}
+	var raw map[string]interface{}
+	if err == nil {
+		raw, err = rawConfigMap(srcPath, data)
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to read profile %s: %v\n", src, err)
+		os.Exit(1)
+	}
+
+	if *noSecrets {
+		for _, field := range configFields {
+			if field.Secret {
+				deleteRawValue(raw, field.Key)
+			}
+		}
+		delete(raw, "api_keys_meta")
+		delete(raw, "inherit_secrets")
+	}
+	var identity []string
+	for _, field := range uniqueProfileFields {
+		identity = append(identity, field.Key)
+		if *newIdentity {
+			zero, _ := lookupField(&AgentConfig{}, field.Key)
+			setRawValue(raw, field.Key, zero.Interface())
+		}
+	}
+	rebaseIncludes(raw, filepath.Dir(srcPath))
+
+	// A complete config is written back in field order; a partial one as is
+	path := profileConfigPath(dst)
+	var out []byte
+	_, hasExtends := raw["extends"]
+	if _, hasIncludes := raw["include"]; hasExtends || hasIncludes {
+		out, err = json.MarshalIndent(raw, "", "  ")
+	} else {
+		var config AgentConfig
+		if config, err = decodeRawConfig(raw); err == nil {
+			out, err = json.MarshalIndent(config, "", "  ")
+		}
+	}
+	if err == nil {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err = writeFileAtomic(path, out, 0600)
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to write config: %v\n", err)
+		os.Exit(1)
+	}
+
+	index := loadProfileIndex()
+	meta := ProfileMeta{Description: *description, CreatedAt: time.Now().UTC().Format(time.RFC3339)}
+	if meta.Description == "" {
+		meta.Description = index.Profiles[src].Description
+	}
+	index.Profiles[dst] = meta
+	saveProfileIndex(index)
+
+	fmt.Printf("✅ Cloned profile %s to %s at %s\n", src, dst, path)
+	if *noSecrets {
+		fmt.Println("   Secrets were left out; set them with: acm --profile " + dst + " set api_keys.<name> <key>")
+	}
+	if *newIdentity {
+		fmt.Printf("   Set a new identity before using it: %s\n", strings.Join(identity, ", "))
+	} else {
+		fmt.Printf("⚠️  %s are shared with %s; change them or clone with --new-identity\n", strings.Join(identity, ", "), src)
+	}
+}
+
+func deleteRawValue(raw map[string]interface{}, key string) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := raw[part].(map[string]interface{})
+		if !ok {
+			return
+		}
+		raw = next
+	}
+	delete(raw, parts[len(parts)-1])
+}
+
+// rebaseIncludes makes relative include paths absolute against dir, so a
+// copied config still finds its fragments
+func rebaseIncludes(raw map[string]interface{}, dir string) {
+	items, _ := raw["include"].([]interface{})
+	for i, item := range items {
+		name, ok := item.(string)
+		if !ok {
+			continue
+		}
+		if !filepath.IsAbs(name) && !strings.HasPrefix(name, "~") {
+			items[i] = includePath(name, dir)
+		}
+	}
+}
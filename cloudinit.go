@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// cloudInitConfigDir is where the tool configs are placed on the VM
+const cloudInitConfigDir = "/etc/agent"
+
+// exportCloudInit renders a #cloud-config that writes each tool config to
+// /etc/agent/<tool>.json (0600) and starts the tools' systemd units. With
+// --secrets=ref (the default) secrets are ${VAR} placeholders for the
+// provisioning pipeline to fill in; inline writes the values.
+func exportCloudInit(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	if opts.Secrets != "ref" && opts.Secrets != "inline" {
+		return nil, fmt.Errorf("unknown --secrets mode %q (use ref or inline)", opts.Secrets)
+	}
+	if opts.Secrets == "ref" {
+		for _, leaf := range configLeaves(&config) {
+			if isSecretKey(leaf.Key) {
+				leaf.Value.SetString("${" + envVarName(leaf.Key) + "}")
+			}
+		}
+	}
+
+	tools, err := exportTools(config, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("#cloud-config\n")
+	fmt.Fprintf(&b, "# Generated by agent-config-manager v%s\n", version)
+	b.WriteString("write_files:\n")
+	for _, file := range tools {
+		fmt.Fprintf(&b, "  - path: %s\n", path.Join(cloudInitConfigDir, file.Name))
+		b.WriteString("    owner: root:root\n")
+		b.WriteString("    permissions: '0600'\n")
+		b.WriteString("    content: |\n")
+		for _, line := range strings.Split(strings.TrimRight(string(file.Data), "\n"), "\n") {
+			fmt.Fprintf(&b, "      %s\n", line)
+		}
+	}
+	b.WriteString("runcmd:\n")
+	for _, service := range toolServices {
+		fmt.Fprintf(&b, "  - [systemctl, enable, --now, %s]\n", service.Name)
+	}
+	return []exportFile{{Name: "cloud-init.yml", Data: []byte(b.String())}}, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type cloudConfig struct {
+	WriteFiles []struct {
+		Path        string `yaml:"path"`
+		Owner       string `yaml:"owner"`
+		Permissions string `yaml:"permissions"`
+		Content     string `yaml:"content"`
+	} `yaml:"write_files"`
+	RunCmd [][]string `yaml:"runcmd"`
+}
+
+// cloudInit runs the cloud-init exporter and parses its YAML
+func cloudInit(t *testing.T, config AgentConfig, secrets string) (cloudConfig, string) {
+	t.Helper()
+	files, err := exportCloudInit(config, exportOptions{Secrets: secrets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := string(files[0].Data)
+	if !strings.HasPrefix(data, "#cloud-config\n") {
+		t.Errorf("doesn't start with #cloud-config:\n%s", data)
+	}
+	var cc cloudConfig
+	if err := yaml.Unmarshal(files[0].Data, &cc); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, data)
+	}
+	return cc, data
+}
+
+func TestExportCloudInitStructure(t *testing.T) {
+	cc, _ := cloudInit(t, defaultConfig(), "ref")
+	if len(cc.WriteFiles) != 3 {
+		t.Fatalf("write_files has %d entries, want 3", len(cc.WriteFiles))
+	}
+	for _, file := range cc.WriteFiles {
+		if !strings.HasPrefix(file.Path, "/etc/agent/") || file.Permissions != "0600" || file.Owner != "root:root" {
+			t.Errorf("file %s: owner %s permissions %q", file.Path, file.Owner, file.Permissions)
+		}
+		var tool map[string]interface{}
+		if err := json.Unmarshal([]byte(file.Content), &tool); err != nil {
+			t.Errorf("%s content isn't the tool JSON: %v", file.Path, err)
+		}
+	}
+	if len(cc.RunCmd) != len(toolServices) {
+		t.Fatalf("runcmd = %v", cc.RunCmd)
+	}
+	for i, service := range toolServices {
+		if strings.Join(cc.RunCmd[i], " ") != "systemctl enable --now "+service.Name {
+			t.Errorf("runcmd[%d] = %v", i, cc.RunCmd[i])
+		}
+	}
+}
+
+func TestExportCloudInitSecrets(t *testing.T) {
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-value-123"
+	config.Monitoring.WebhookSecret = "whsec-value-9876543210"
+
+	_, ref := cloudInit(t, config, "ref")
+	assertContains(t, ref, `"etherscan_key": "${ETHERSCAN_API_KEY}"`, `"webhook_secret": "${MONITORING_WEBHOOK_SECRET}"`)
+	assertNotContains(t, ref, "etherscan-secret-value-123", "whsec-value-9876543210")
+
+	_, inline := cloudInit(t, config, "inline")
+	assertContains(t, inline, `"etherscan_key": "etherscan-secret-value-123"`, "whsec-value-9876543210")
+
+	if _, err := exportCloudInit(config, exportOptions{Secrets: "vault"}); err == nil {
+		t.Error("unknown --secrets mode accepted")
+	}
+}
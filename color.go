@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// colorMode is set by the global --color flag: auto (the default), always
+// or never
+var colorMode string
+
+var colorModes = []string{"auto", "always", "never"}
+
+const (
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiReset  = "\033[0m"
+)
+
+// useColor reports whether output should be colored. In auto mode that's
+// when stdout is a terminal and neither NO_COLOR nor TERM=dumb is set.
+func useColor() bool {
+	mode := colorMode
+	if mode == "" {
+		mode = "auto"
+	}
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	case "auto":
+		return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(os.Stdout)
+	}
+	fmt.Printf("❌ Unknown color mode: %s (use %s)\n", mode, strings.Join(colorModes, ", "))
+	os.Exit(1)
+	return false
+}
+
+// colorize wraps s in an ANSI color when output is colored
+func colorize(color, s string) string {
+	if !useColor() {
+		return s
+	}
+	return color + s + ansiReset
+}
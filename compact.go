@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// orderedObject is a JSON object that keeps its keys in insertion order
+type orderedObject []orderedField
+
+type orderedField struct {
+	Key   string
+	Value interface{}
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// compactConfig writes a minimal, shareable template of the config: secrets
+// are removed entirely, along with zero-valued omitempty fields and empty
+// slices and maps. Fields without omitempty are kept even when zero.
+func compactConfig(out string) {
+	config := loadConfig()
+
+	compacted := compactStruct(reflect.ValueOf(config), "")
+	data, err := json.MarshalIndent(compacted, "", "  ")
+	if err != nil {
+		fmt.Printf("❌ Failed to marshal config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(out, append(data, '\n'), 0644); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", out, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Wrote compact template to %s (secrets stripped)\n", out)
+}
+
+func compactStruct(v reflect.Value, prefix string) orderedObject {
+	obj := orderedObject{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if isSecretKey(key) {
+			continue
+		}
+
+		field := v.Field(i)
+		omitEmpty := strings.Contains(t.Field(i).Tag.Get("json"), ",omitempty")
+
+		switch field.Kind() {
+		case reflect.Struct:
+			if nested := compactStruct(field, key); len(nested) > 0 {
+				obj = append(obj, orderedField{name, nested})
+			}
+		case reflect.Slice, reflect.Map:
+			if field.Len() > 0 {
+				obj = append(obj, orderedField{name, field.Interface()})
+			}
+		default:
+			if omitEmpty && field.IsZero() {
+				continue
+			}
+			obj = append(obj, orderedField{name, field.Interface()})
+		}
+	}
+	return obj
+}
+
+func isSecretKey(key string) bool {
+	for _, field := range configFields {
+		if field.Key == key {
+			return field.Secret
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompactStructDropsEmptyOptionals(t *testing.T) {
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-value-123"
+	config.Monitoring.WebhookURL = ""
+	config.Security.WhitelistedAddresses = nil
+
+	data, err := json.Marshal(compactStruct(reflect.ValueOf(config), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	json.Unmarshal(data, &got)
+
+	for _, key := range []string{"extends", "include", "networks", "api_keys", "api_keys_meta", "validation"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("compact kept empty %s", key)
+		}
+	}
+	security := got["security"].(map[string]interface{})
+	if _, ok := security["whitelisted_addresses"]; ok {
+		t.Error("compact kept the empty whitelist")
+	}
+	monitoring := got["monitoring"].(map[string]interface{})
+	if _, ok := monitoring["webhook_url"]; ok {
+		t.Error("compact kept the empty monitoring.webhook_url")
+	}
+
+	// Fields without omitempty stay, even when false or zero
+	if got["version"] != "0.1.0" {
+		t.Errorf("version = %v", got["version"])
+	}
+	if _, ok := monitoring["dashboard_port"]; !ok {
+		t.Error("compact dropped monitoring.dashboard_port")
+	}
+	config.Security.HoneypotEnabled = false
+	data, _ = json.Marshal(compactStruct(reflect.ValueOf(config), ""))
+	json.Unmarshal(data, &got)
+	if v, ok := got["security"].(map[string]interface{})["honeypot_enabled"]; !ok || v != false {
+		t.Errorf("security.honeypot_enabled = %v, %v; want false kept", v, ok)
+	}
+}
+
+func TestCompactCommandStripsSecrets(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	out := filepath.Join(e.home, "template.json")
+	e.mustRun("compact", out)
+
+	data := e.read(out)
+	assertNotContains(t, data, "etherscan-secret-value-123", "api_keys")
+	assertContains(t, data, `"address": "0x120e011fB8a12bfcB61e5c1d751C26A5D33Aae91"`, `"daily_limit": 0.5`)
+	// The template still loads as a config
+	var config AgentConfig
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Agent.Name != "Arithmos" {
+		t.Errorf("agent.name = %q", config.Agent.Name)
+	}
+}
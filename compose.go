@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// toolServices lists the config keys (or derivedExportKeys) each tool reads,
+// as environment variables named by envVarName
+var toolServices = []struct {
+	Name string
+	Keys []string
+}{
+	{"wallet-monitor", []string{
+		"wallet.address",
+		"api_keys.etherscan",
+		"api_keys.basescan",
+		"monitoring.check_interval_minutes",
+		"monitoring.check_interval_seconds",
+		"wallet.daily_limit",
+		"wallet.daily_limit_wei",
+		"wallet.alert_threshold",
+		"wallet.alert_threshold_wei",
+		"monitoring.webhook_url",
+		"monitoring.webhook_secret",
+		"monitoring.rate_limit.rps",
+		"monitoring.rate_limit.burst",
+		"monitoring.rate_limit.backoff",
+		"observability.otlp_endpoint",
+		"observability.sample_rate",
+		"observability.log_level",
+	}},
+	{"reputation-scanner", []string{
+		"wallet.address",
+		"api_keys.etherscan",
+		"api_keys.basescan",
+		"monitoring.rate_limit.rps",
+		"monitoring.rate_limit.burst",
+		"monitoring.rate_limit.backoff",
+		"observability.otlp_endpoint",
+		"observability.sample_rate",
+		"observability.log_level",
+	}},
+	{"security-dashboard", []string{
+		"monitoring.dashboard_port",
+		"observability.otlp_endpoint",
+		"observability.sample_rate",
+		"observability.log_level",
+	}},
+}
+
+// exportDockerCompose renders a docker-compose.override.yml setting each
+// tool service's environment. With --secrets=ref (the default) secrets are
+// emitted as ${VAR} references to be filled from an external .env file.
+func exportDockerCompose(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	if opts.Secrets != "ref" && opts.Secrets != "inline" {
+		return nil, fmt.Errorf("unknown --secrets mode %q (use ref or inline)", opts.Secrets)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by agent-config-manager v%s\n", version)
+	b.WriteString("services:\n")
+	for _, service := range toolServices {
+		fmt.Fprintf(&b, "  %s:\n", service.Name)
+		b.WriteString("    environment:\n")
+		for _, key := range service.Keys {
+			plain, err := exportValue(config, key)
+			if err != nil {
+				return nil, err
+			}
+			name := envVarName(key)
+
+			var value string
+			if isSecretKey(key) && opts.Secrets == "ref" {
+				value = "${" + name + "}"
+			} else {
+				// Compose interpolates $ in values, so escape it as $$
+				value = strings.ReplaceAll(plain, "$", "$$")
+			}
+			fmt.Fprintf(&b, "      %s: %s\n", name, yamlQuote(value))
+		}
+	}
+	return []exportFile{{Name: "docker-compose.override.yml", Data: []byte(b.String())}}, nil
+}
+
+// yamlQuote double-quotes s; a JSON string is a valid YAML double-quoted scalar
+func yamlQuote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
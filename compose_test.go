@@ -0,0 +1,87 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type composeFile struct {
+	Services map[string]struct {
+		Environment map[string]string `yaml:"environment"`
+	} `yaml:"services"`
+}
+
+// composeEnv runs the docker-compose exporter and parses its YAML
+func composeEnv(t *testing.T, config AgentConfig, secrets string) composeFile {
+	t.Helper()
+	files, err := exportDockerCompose(config, exportOptions{Secrets: secrets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files[0].Name != "docker-compose.override.yml" {
+		t.Errorf("file name = %s", files[0].Name)
+	}
+	var compose composeFile
+	if err := yaml.Unmarshal(files[0].Data, &compose); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, files[0].Data)
+	}
+	return compose
+}
+
+func TestExportDockerComposeStructure(t *testing.T) {
+	config := defaultConfig()
+	config.Monitoring.WebhookURL = "https://hooks.example.com/$alert"
+	compose := composeEnv(t, config, "ref")
+
+	for _, service := range toolServices {
+		env := compose.Services[service.Name].Environment
+		if len(env) != len(service.Keys) {
+			t.Errorf("%s has %d variables, want %d", service.Name, len(env), len(service.Keys))
+		}
+	}
+	monitor := compose.Services["wallet-monitor"].Environment
+	for name, want := range map[string]string{
+		"WALLET_ADDRESS":            "0x120e011fB8a12bfcB61e5c1d751C26A5D33Aae91",
+		"WALLET_DAILY_LIMIT":        "0.5",
+		"WALLET_DAILY_LIMIT_WEI":    "500000000000000000",
+		"MONITORING_WEBHOOK_URL":    "https://hooks.example.com/$$alert",
+		"MONITORING_RATE_LIMIT_RPS": "5",
+	} {
+		if monitor[name] != want {
+			t.Errorf("%s = %q, want %q", name, monitor[name], want)
+		}
+	}
+	if port := compose.Services["security-dashboard"].Environment["MONITORING_DASHBOARD_PORT"]; port != "8080" {
+		t.Errorf("dashboard port = %q", port)
+	}
+}
+
+func TestExportDockerComposeSecrets(t *testing.T) {
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-value-123"
+
+	env := composeEnv(t, config, "ref").Services["wallet-monitor"].Environment
+	if env["ETHERSCAN_API_KEY"] != "${ETHERSCAN_API_KEY}" {
+		t.Errorf("ref secret = %q", env["ETHERSCAN_API_KEY"])
+	}
+	env = composeEnv(t, config, "inline").Services["wallet-monitor"].Environment
+	if env["ETHERSCAN_API_KEY"] != "etherscan-secret-value-123" {
+		t.Errorf("inline secret = %q", env["ETHERSCAN_API_KEY"])
+	}
+
+	if _, err := exportDockerCompose(config, exportOptions{Secrets: "plain"}); err == nil {
+		t.Error("unknown --secrets mode accepted")
+	}
+}
+
+func TestExportDockerComposeCommand(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.mustRun("export", "--format", "docker-compose")
+	out := e.read(filepath.Join(e.exportDir(), "docker-compose.override.yml"))
+	assertContains(t, out, "ETHERSCAN_API_KEY: \"${ETHERSCAN_API_KEY}\"")
+	assertNotContains(t, out, "etherscan-secret-value-123")
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// configTestCommand dry-loads a config file: it must decode without unknown
+// fields and pass validation. Like a compiler it reports every problem in
+// one pass: fields that don't fit the schema are set aside so the rest can
+// still be validated. Nothing is written, and the file's validation hooks
+// only run with --run-hooks, since the file may not be trusted yet.
+func configTestCommand(args []string) {
+	fs := flag.NewFlagSet("config-test", flag.ExitOnError)
+	runHooks := fs.Bool("run-hooks", false, "also run the file's validation hooks")
+	args = parseFlags(fs, args)
+	if len(args) != 1 {
+		fmt.Println("Usage: acm config-test <file> [--run-hooks]")
+		os.Exit(1)
+	}
+	path := expandPath(args[0])
+
+	fmt.Printf("🔍 Testing %s...\n", path)
+	fmt.Println()
+
+	data, err := readConfigFile(path)
+	if err != nil {
+		fmt.Printf("❌ Failed to read %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	raw, err := rawConfigMap(path, data)
+	if err != nil {
+		fmt.Printf("❌ Invalid config: %v\n", err)
+		os.Exit(1)
+	}
+	findings := []finding{}
+	unknown := unknownSectionNames(raw)
+	for _, name := range unknown {
+		findings = append(findings, warnf("schema", "%s: unknown section, preserved as is on save", name).on(name))
+	}
+	for _, problem := range schemaProblems(reflect.TypeOf(AgentConfig{}), raw, "") {
+		findings = append(findings, errorf("schema", "%s", problem))
+	}
+	if len(findings) > 0 {
+		// Load what's left
+		if data, err = json.Marshal(raw); err != nil {
+			fmt.Printf("❌ Invalid config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	config, err := parseConfig(path, data)
+	if err == nil && (config.Extends != "" || len(config.Include) > 0) {
+		config, err = resolveInheritance(path, data)
+	}
+	if err != nil {
+		fmt.Printf("❌ Invalid config: %v\n", err)
+		os.Exit(1)
+	}
+
+	rules := serveValidationRules()
+	if *runHooks {
+		rules = validationRules
+	}
+	findings = append(findings, runValidation(config, validationContext{Dir: filepath.Dir(path)}, rules)...)
+	printFindings(findings)
+	if hasErrors(findings) {
+		os.Exit(1)
+	}
+}
+
+// checkUnknownFields rejects keys AgentConfig doesn't define below the top
+// level, which a normal load silently drops. Unknown top-level sections are
+// kept in Unknown.
+func checkUnknownFields(path string, data []byte) error {
+	data, err := normalizeEncoding(path, data)
+	if err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	if data, err = configJSON(path, data); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	data, err = migrateAliases(path, data)
+	if err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	for name := range sections {
+		if !knownSections[name] {
+			delete(sections, name)
+		}
+	}
+	if data, err = json.Marshal(sections); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var config plainAgentConfig
+	if err := dec.Decode(&config); err != nil {
+		return fmt.Errorf("invalid config: %v", err)
+	}
+	return nil
+}
+
+// schemaProblems lists every field in raw that t can't hold, unknown or of
+// the wrong type, by dotted path. The offending fields are removed from raw
+// so what remains decodes.
+func schemaProblems(t reflect.Type, raw map[string]interface{}, prefix string) []string {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			fields[name] = t.Field(i)
+		}
+	}
+
+	var problems []string
+	for _, name := range sortedKeys(raw) {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		field, ok := fields[name]
+		if !ok {
+			problems = append(problems, key+": unknown field")
+			delete(raw, name)
+			continue
+		}
+		p, fits := valueProblems(field.Type, raw[name], key)
+		problems = append(problems, p...)
+		if !fits {
+			delete(raw, name)
+		}
+	}
+	return problems
+}
+
+// valueProblems checks one JSON value against t. It reports false when the
+// value can't be decoded into t at all; problems nested inside an object are
+// removed in place instead.
+func valueProblems(t reflect.Type, v interface{}, key string) ([]string, bool) {
+	if v == nil {
+		return nil, true
+	}
+	mismatch := func(want string) ([]string, bool) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", key, want, jsonKind(v))}, false
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return mismatch("an object")
+		}
+		return schemaProblems(t, obj, key), true
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return mismatch("an object")
+		}
+		var problems []string
+		for _, name := range sortedKeys(obj) {
+			p, fits := valueProblems(t.Elem(), obj[name], key+"."+name)
+			problems = append(problems, p...)
+			if !fits {
+				delete(obj, name)
+			}
+		}
+		return problems, true
+	case reflect.Slice:
+		list, ok := v.([]interface{})
+		if !ok {
+			return mismatch("a list")
+		}
+		var problems []string
+		for i, item := range list {
+			p, _ := valueProblems(t.Elem(), item, fmt.Sprintf("%s[%d]", key, i))
+			problems = append(problems, p...)
+		}
+		return problems, len(problems) == 0
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			return mismatch("a string")
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			return mismatch("true or false")
+		}
+	case reflect.Int, reflect.Int64:
+		if n, ok := v.(float64); !ok || n != math.Trunc(n) {
+			return mismatch("a whole number")
+		}
+	case reflect.Float64:
+		if _, ok := v.(float64); !ok {
+			return mismatch("a number")
+		}
+	}
+	return nil, true
+}
+
+// jsonKind names the JSON type of a decoded value for error messages
+func jsonKind(v interface{}) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	case string:
+		return fmt.Sprintf("the string %q", v)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	case float64:
+		return fmt.Sprintf("the number %g", v)
+	}
+	return "null"
+}
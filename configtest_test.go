@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// candidate writes a config for config-test outside the live config dir
+func (e *testEnv) candidate(edit func(config map[string]interface{})) string {
+	e.t.Helper()
+	data, _ := json.Marshal(defaultConfig())
+	config := map[string]interface{}{}
+	json.Unmarshal(data, &config)
+	if edit != nil {
+		edit(config)
+	}
+	data, _ = json.MarshalIndent(config, "", "  ")
+	path := filepath.Join(e.home, "candidate.json")
+	e.write(path, string(data))
+	return path
+}
+
+func TestConfigTestValidFile(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	live := e.read(e.configPath())
+	path := e.candidate(func(config map[string]interface{}) {
+		section(config, "wallet")["daily_limit"] = 2
+	})
+
+	assertContains(t, e.mustRun("config-test", path), "Testing "+path)
+	if e.read(e.configPath()) != live {
+		t.Error("config-test changed the live config")
+	}
+}
+
+func TestConfigTestUnknownFields(t *testing.T) {
+	e := newTestEnv(t)
+	path := e.candidate(func(config map[string]interface{}) {
+		section(config, "wallet")["dailylimit"] = 2
+		section(config, "monitoring")["dashboard_port"] = "8080"
+		config["plugins"] = map[string]interface{}{}
+	})
+
+	out := e.mustFail("config-test", path)
+	assertContains(t, out,
+		"wallet.dailylimit: unknown field",
+		`monitoring.dashboard_port: expected a whole number, got the string "8080"`,
+		"plugins: unknown section",
+	)
+}
+
+func TestConfigTestInvalidFile(t *testing.T) {
+	e := newTestEnv(t)
+	path := e.candidate(func(config map[string]interface{}) {
+		section(config, "wallet")["address"] = "0x1234"
+	})
+	assertContains(t, e.mustFail("config-test", path), "wallet.address")
+
+	e.write(path, `{"version": "0.1.0",`)
+	assertContains(t, e.mustFail("config-test", path), "Invalid config")
+	assertContains(t, e.mustFail("config-test", filepath.Join(e.home, "missing.json")), "Failed to read")
+}
+
+func TestConfigTestHooksNeedFlag(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	e := newTestEnv(t)
+	marker := filepath.Join(e.home, "hook-ran")
+	e.write(filepath.Join(e.home, "hook.sh"), "#!/bin/sh\ntouch "+marker+"\n")
+	os.Chmod(filepath.Join(e.home, "hook.sh"), 0700)
+	path := e.candidate(func(config map[string]interface{}) {
+		section(config, "validation")["hooks"] = []interface{}{"./hook.sh"}
+	})
+
+	e.mustRun("config-test", path)
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("config-test ran a hook without --run-hooks")
+	}
+	e.mustRun("config-test", path, "--run-hooks")
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("--run-hooks didn't run the hook: %v", err)
+	}
+}
+
+func TestConfigTestReportsEveryProblem(t *testing.T) {
+	e := newTestEnv(t)
+	path := e.candidate(func(config map[string]interface{}) {
+		section(config, "wallet")["address"] = "0x1234"
+		section(config, "wallet")["daily_limt"] = 2
+		section(config, "monitoring")["dashboard_enabled"] = "yes"
+		section(config, "monitoring")["check_interval_minutes"] = 2.5
+		section(config, "networks")["base"] = map[string]interface{}{"rpc_url": 8453}
+	})
+
+	// schema problems don't hide the validation errors behind them
+	out := e.mustFail("config-test", path)
+	assertContains(t, out,
+		"wallet.daily_limt: unknown field",
+		`monitoring.dashboard_enabled: expected true or false, got the string "yes"`,
+		"monitoring.check_interval_minutes: expected a whole number, got the number 2.5",
+		"networks.base.rpc_url: expected a string, got the number 8453",
+		"wallet.address",
+	)
+}
+
+func TestSchemaProblemsDropsBadFields(t *testing.T) {
+	raw := map[string]interface{}{
+		"wallet":     map[string]interface{}{"daily_limit": "lots", "address": testAddrA},
+		"security":   map[string]interface{}{"whitelisted_addresses": []interface{}{testAddrB, 7.0}},
+		"monitoring": "on",
+	}
+	problems := schemaProblems(reflect.TypeOf(AgentConfig{}), raw, "")
+	want := []string{
+		"monitoring: expected an object, got the string \"on\"",
+		"security.whitelisted_addresses[1]: expected a string, got the number 7",
+		"wallet.daily_limit: expected a number, got the string \"lots\"",
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %q, want %q", problems, want)
+	}
+
+	// what's left decodes
+	data, _ := json.Marshal(raw)
+	var config AgentConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("remaining fields don't decode: %v\n%s", err, data)
+	}
+	if config.Wallet.Address != testAddrA {
+		t.Errorf("wallet.address = %q, want it kept", config.Wallet.Address)
+	}
+}
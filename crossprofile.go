@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// profileConfig is one profile's loaded config, or the error loading it
+type profileConfig struct {
+	Name   string
+	Path   string
+	Config AgentConfig
+	Err    error
+}
+
+// loadAllProfiles reads every profile's config with the same view validate
+// uses for the active one: references expanded, env secrets applied
+func loadAllProfiles() []profileConfig {
+	var profiles []profileConfig
+	for _, name := range listProfiles() {
+		p := profileConfig{Name: name, Path: profileConfigPath(name)}
+		p.Config, p.Err = readConfig(p.Path)
+		if p.Err == nil {
+			p.Err = expandConfigEnvRefs(&p.Config)
+		}
+		if p.Err == nil {
+			resolveSecrets(&p.Config)
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// uniqueProfileFields are the values no two profiles may share: agents
+// registered under the same identity or wallet confuse the ERC-8004 registry
+var uniqueProfileFields = []struct {
+	Key   string
+	Value func(AgentConfig) string
+}{
+	{"agent.id", func(c AgentConfig) string { return c.Agent.ID }},
+	{"agent.erc8004_id", func(c AgentConfig) string {
+		if c.Agent.ERC8004ID == 0 {
+			return ""
+		}
+		return strconv.Itoa(c.Agent.ERC8004ID)
+	}},
+	// Addresses differ only in checksum case
+	{"wallet.address", func(c AgentConfig) string { return strings.ToLower(c.Wallet.Address) }},
+}
+
+// checkProfileCollisions reports values in uniqueProfileFields shared by
+// more than one profile. Profiles that failed to load are ignored.
+func checkProfileCollisions(profiles []profileConfig) []finding {
+	findings := []finding{}
+	for _, field := range uniqueProfileFields {
+		owners := map[string][]string{}
+		var order []string
+		for _, p := range profiles {
+			if p.Err != nil {
+				continue
+			}
+			value := field.Value(p.Config)
+			if value == "" {
+				continue
+			}
+			if owners[value] == nil {
+				order = append(order, value)
+			}
+			owners[value] = append(owners[value], p.Name)
+		}
+		for _, value := range order {
+			if names := owners[value]; len(names) > 1 {
+				findings = append(findings, errorf("unique-ids", "%s %s is used by profiles %s", field.Key, value, strings.Join(names, ", ")).on(field.Key))
+			}
+		}
+	}
+	return findings
+}
+
+// profileReport is the output of `acm validate --all-profiles --json`
+type profileReport struct {
+	Valid    bool                 `json:"valid"`
+	Profiles map[string][]finding `json:"profiles"`
+	Findings []finding            `json:"findings"` // across profiles
+}
+
+// validateAllProfiles runs rules against every profile, then checks that no
+// two profiles share an identity
+func validateAllProfiles(rules []validationRule, asJSON bool) {
+	profiles := loadAllProfiles()
+	if len(profiles) == 0 {
+		fmt.Println("No profiles found. Run 'acm init' to create one.")
+		return
+	}
+
+	report := profileReport{Valid: true, Profiles: map[string][]finding{}}
+	for _, p := range profiles {
+		findings := []finding{}
+		if p.Err != nil {
+			findings = append(findings, errorf("load", "%v", p.Err))
+		} else {
+			findings = runValidation(p.Config, validationContext{Dir: filepath.Dir(p.Path)}, rules)
+		}
+		report.Profiles[p.Name] = findings
+		report.Valid = report.Valid && !hasErrors(findings)
+	}
+	report.Findings = checkProfileCollisions(profiles)
+	report.Valid = report.Valid && !hasErrors(report.Findings)
+
+	if asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("🔍 Validating %d profile(s)...\n", len(profiles))
+	for _, p := range profiles {
+		fmt.Println()
+		fmt.Printf("%s:\n", p.Name)
+		findings := report.Profiles[p.Name]
+		if len(findings) == 0 {
+			fmt.Println("  ✅ Configuration is valid!")
+		}
+		for _, f := range sortFindings(findings) {
+			fmt.Printf("  %s\n", f.colored())
+		}
+	}
+
+	fmt.Println()
+	if len(report.Findings) == 0 {
+		fmt.Println("✅ No identities shared between profiles")
+		return
+	}
+	fmt.Println("Across profiles:")
+	for _, f := range sortFindings(report.Findings) {
+		fmt.Printf("  %s\n", f.colored())
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCheckProfileCollisions(t *testing.T) {
+	a, b, c := defaultConfig(), defaultConfig(), defaultConfig()
+	b.Agent.ERC8004ID = 2000
+	b.Wallet.Address = "0x120E011FB8A12BFCB61E5C1D751C26A5D33AAE91" // same address, other case
+	c.Agent.ID, c.Agent.ERC8004ID, c.Wallet.Address = "other-agent", 0, ""
+	profiles := []profileConfig{{Name: "default", Config: a}, {Name: "mainnet", Config: b}, {Name: "testnet", Config: c}}
+
+	findings := checkProfileCollisions(profiles)
+	var messages []string
+	for _, f := range findings {
+		messages = append(messages, f.Message)
+	}
+	want := []string{
+		"agent.id arithmos-quillsworth is used by profiles default, mainnet",
+		"wallet.address 0x120e011fb8a12bfcb61e5c1d751c26a5d33aae91 is used by profiles default, mainnet",
+	}
+	if len(messages) != len(want) || messages[0] != want[0] || messages[1] != want[1] {
+		t.Errorf("findings = %q, want %q", messages, want)
+	}
+
+	// profiles that don't load are left out
+	profiles[1].Err = ErrParse
+	if findings := checkProfileCollisions(profiles); len(findings) != 0 {
+		t.Errorf("findings with mainnet unloadable = %+v", findings)
+	}
+}
+
+func TestValidateAllProfilesReportsCollisions(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "testnet")
+	e.mustRun("--profile", "testnet", "init")
+	e.mustRun("--profile", "testnet", "set", "wallet.address", testAddrA)
+	e.mustRun("--profile", "testnet", "set", "agent.erc8004_id", "2000")
+
+	out := e.mustRun("validate", "--all-profiles")
+	assertContains(t, out, "Across profiles:", "agent.id arithmos-quillsworth is used by profiles default, testnet")
+	assertNotContains(t, out, "wallet.address 0x")
+
+	out, _ = e.run("validate", "--all-profiles", "--json")
+	var report profileReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, out)
+	}
+	if report.Valid || len(report.Findings) != 1 || report.Findings[0].Rule != "unique-ids" || len(report.Profiles) != 2 {
+		t.Errorf("report = %+v", report)
+	}
+
+	e.mustRun("--profile", "testnet", "set", "agent.id", "arithmos-testnet")
+	assertContains(t, e.mustRun("validate", "--all-profiles"), "No identities shared between profiles")
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// encPrefix marks an API key stored as enc:<base64(nonce || AES-256-GCM
+// ciphertext)>. The key name is authenticated too, so an encrypted value
+// can't be copied onto another key.
+const encPrefix = "enc:"
+
+// encryptedConfigs records which loaded config files had encrypted secrets
+// and the prefix of the scheme used, encPrefix or passPrefix, so saving
+// them encrypts the same way again
+var encryptedConfigs = map[string]string{}
+
+var errNoSecretKey = errors.New("no encryption key: set ACM_SECRET_KEY, restore secret.key or unlock the OS keychain")
+
+// secretKeyPath is the key file used when ACM_SECRET_KEY is not set
+func secretKeyPath() string {
+	return filepath.Join(getBaseDir(), "secret.key")
+}
+
+var cachedSecretKey []byte
+
+// loadSecretKey returns the 32-byte encryption key from ACM_SECRET_KEY
+// (base64), the OS keychain or the key file
+func loadSecretKey() ([]byte, error) {
+	if cachedSecretKey != nil {
+		return cachedSecretKey, nil
+	}
+	encoded := os.Getenv("ACM_SECRET_KEY")
+	source := "ACM_SECRET_KEY"
+	if encoded == "" {
+		encoded, source = keychainSecretKey(), "the OS keychain entry"
+	}
+	if encoded == "" {
+		data, err := os.ReadFile(secretKeyPath())
+		if os.IsNotExist(err) {
+			return nil, errNoSecretKey
+		}
+		if err != nil {
+			return nil, err
+		}
+		encoded, source = string(data), secretKeyPath()
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s is not a base64-encoded 32-byte key", source)
+	}
+	cachedSecretKey = key
+	return key, nil
+}
+
+// createSecretKey generates a new key file, refusing to replace one
+func createSecretKey() error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	os.MkdirAll(filepath.Dir(secretKeyPath()), 0700)
+	f, err := os.OpenFile(secretKeyPath(), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(f, base64.StdEncoding.EncodeToString(key))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptSecret(name, value string) (string, error) {
+	key, err := loadSecretKey()
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), []byte(name))
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// encryptWith encrypts value under the scheme scheme names
+func encryptWith(scheme, name, value string) (string, error) {
+	if scheme == passPrefix {
+		return encryptWithPassphrase(name, value)
+	}
+	return encryptSecret(name, value)
+}
+
+func decryptSecret(name, value string) (string, error) {
+	if strings.HasPrefix(value, passPrefix) {
+		return decryptWithPassphrase(name, value)
+	}
+	key, err := loadSecretKey()
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(name))
+	if err != nil {
+		return "", errors.New("wrong key or corrupted value")
+	}
+	return string(plain), nil
+}
+
+// decryptConfigSecrets decrypts every encrypted API key in place and
+// returns the prefix of the scheme they used, or "" when there were none
+func decryptConfigSecrets(config *AgentConfig) (string, error) {
+	scheme := ""
+	for _, env := range apiKeyEnvVars {
+		value := apiKeyValue(*config, env.Key)
+		if !isEncryptedSecret(value) {
+			continue
+		}
+		plain, err := decryptSecret(env.Key, value)
+		if err != nil {
+			return "", fmt.Errorf("cannot decrypt %s: %v", env.Key, err)
+		}
+		env.Set(&config.APIKeys, plain)
+		scheme = encPrefix
+		if strings.HasPrefix(value, passPrefix) {
+			scheme = passPrefix
+		}
+	}
+	return scheme, nil
+}
+
+// encryptConfigSecrets encrypts every non-empty API key in place
+func encryptConfigSecrets(config *AgentConfig, scheme string) error {
+	for _, env := range apiKeyEnvVars {
+		value := apiKeyValue(*config, env.Key)
+		if value == "" || isEncryptedSecret(value) {
+			continue
+		}
+		sealed, err := encryptWith(scheme, env.Key, value)
+		if err != nil {
+			return err
+		}
+		env.Set(&config.APIKeys, sealed)
+	}
+	return nil
+}
+
+// encryptRawSecrets encrypts the plaintext API keys of an on-disk JSON
+// object, for configs saved through an inheritance patch
+func encryptRawSecrets(raw map[string]interface{}, scheme string) error {
+	keys, _ := raw["api_keys"].(map[string]interface{})
+	for name, v := range keys {
+		value, ok := v.(string)
+		if !ok || value == "" || isEncryptedSecret(value) {
+			continue
+		}
+		sealed, err := encryptWith(scheme, "api_keys."+name, value)
+		if err != nil {
+			return err
+		}
+		keys[name] = sealed
+	}
+	return nil
+}
+
+// resealLayers decrypts the API keys kept as written in the layered config
+// at path and in the files it includes, and marks those files for writing.
+// A layered config only saves what changed, so without this its keys would
+// keep their old encryption when the scheme or passphrase changes.
+func resealLayers(path string) error {
+	layer, ok := inheritedLayers[path]
+	if !ok {
+		return nil
+	}
+	if err := decryptRawSecrets(path, layer.raw); err != nil {
+		return err
+	}
+	for _, fragment := range layer.fragments {
+		keys, _ := fragment.raw["api_keys"].(map[string]interface{})
+		if len(keys) > 0 {
+			fragment.dirty = true
+		}
+		if err := decryptRawSecrets(fragment.path, fragment.raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decryptRawSecrets(path string, raw map[string]interface{}) error {
+	keys, _ := raw["api_keys"].(map[string]interface{})
+	for name, v := range keys {
+		value, ok := v.(string)
+		if !ok || !isEncryptedSecret(value) {
+			continue
+		}
+		plain, err := decryptSecret("api_keys."+name, value)
+		if err != nil {
+			return fmt.Errorf("%s: cannot decrypt api_keys.%s: %v", path, name, err)
+		}
+		keys[name] = plain
+	}
+	return nil
+}
+
+// encryptKeysCommand switches the config to encrypted API keys, creating
+// the key file on first use. A config encrypted with a passphrase is only
+// switched to the key file after confirmation.
+func encryptKeysCommand() {
+	config := loadConfig()
+	if encryptedConfigs[getConfigPath()] == passPrefix {
+		fmt.Println("⚠️  API keys are encrypted with a passphrase; this switches them to the key file")
+		confirmOrExit("Encrypt API keys with the key file instead?")
+	}
+	if err := resealLayers(getConfigPath()); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if _, err := loadSecretKey(); errors.Is(err, errNoSecretKey) {
+		if err := createSecretKey(); err != nil {
+			fmt.Printf("❌ Failed to create encryption key: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🔑 Created encryption key at %s\n", secretKeyPath())
+		fmt.Println("   Back it up: without it the encrypted keys can't be recovered")
+	} else if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	count := 0
+	for _, env := range apiKeyEnvVars {
+		if apiKeyValue(config, env.Key) != "" {
+			count++
+		}
+	}
+
+	encryptedConfigs[getConfigPath()] = encPrefix
+	saveConfig(config)
+	fmt.Printf("✅ Encrypted %d API key(s); keys set later are encrypted too\n", count)
+}
+
+// decryptKeysCommand stores API keys encrypted with the key file in
+// plaintext again; `acm decrypt` does the same for a passphrase
+func decryptKeysCommand() {
+	decryptSchemeCommand(encPrefix)
+}
+
+func decryptSchemeCommand(scheme string) {
+	config := loadConfig()
+	switch encryptedConfigs[getConfigPath()] {
+	case "":
+		fmt.Println("⚠️  API keys are not encrypted")
+		return
+	case passPrefix:
+		if scheme != passPrefix {
+			fmt.Println("❌ API keys are encrypted with a passphrase; use acm decrypt")
+			os.Exit(1)
+		}
+	case encPrefix:
+		if scheme != encPrefix {
+			fmt.Println("❌ API keys are encrypted with the key file; use acm keys decrypt")
+			os.Exit(1)
+		}
+	}
+	confirmOrExit("Store API keys in plaintext?")
+	if err := resealLayers(getConfigPath()); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	delete(encryptedConfigs, getConfigPath())
+	saveConfig(config)
+	fmt.Println("✅ Decrypted API keys")
+}
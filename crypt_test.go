@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTestSecretKey sets ACM_SECRET_KEY for in-process encryption
+func useTestSecretKey(t *testing.T) {
+	t.Helper()
+	t.Setenv("ACM_SECRET_KEY", base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	saved := cachedSecretKey
+	cachedSecretKey = nil
+	t.Cleanup(func() { cachedSecretKey = saved })
+}
+
+func TestEncryptSecretRoundTrip(t *testing.T) {
+	useTestSecretKey(t)
+	sealed, err := encryptSecret("api_keys.etherscan", "etherscan-secret-value-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, encPrefix) || strings.Contains(sealed, "etherscan-secret-value-123") {
+		t.Fatalf("sealed = %s", sealed)
+	}
+	if plain, err := decryptSecret("api_keys.etherscan", sealed); err != nil || plain != "etherscan-secret-value-123" {
+		t.Errorf("decrypted %q, %v", plain, err)
+	}
+	// the key name is authenticated, so a value can't be moved to another key
+	if _, err := decryptSecret("api_keys.openai", sealed); err == nil {
+		t.Error("decrypted a value under another key name")
+	}
+	if _, err := decryptSecret("api_keys.etherscan", encPrefix+"!!!"); err == nil {
+		t.Error("decrypted a malformed value")
+	}
+}
+
+func TestKeysEncryptOnlySecrets(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.mustRun("set", "api_keys.openai", "sk-openai-abcdefghijklmnop")
+	e.mustRun("keys", "encrypt")
+	e.mustRun("set", "api_keys.basescan", "basescan-secret-value-123")
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(e.read(e.configPath())), &raw); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range section(raw, "api_keys") {
+		if s, _ := value.(string); !strings.HasPrefix(s, encPrefix) {
+			t.Errorf("api_keys.%s = %v, want it encrypted", name, value)
+		}
+	}
+	if section(raw, "agent")["name"] != "Arithmos" || section(raw, "wallet")["daily_limit"] != 0.5 {
+		t.Errorf("non-secret fields changed: agent %v wallet %v", raw["agent"], raw["wallet"])
+	}
+	if info, err := os.Stat(filepath.Join(e.home, ".config", "agent", "secret.key")); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("secret.key: %v", err)
+	}
+
+	// loading decrypts transparently
+	e.mustRun("export")
+	tool := e.read(filepath.Join(e.exportDir(), "wallet-monitor.json"))
+	assertContains(t, tool, "etherscan-secret-value-123", "basescan-secret-value-123")
+
+	e.mustRun("--yes", "keys", "decrypt")
+	assertContains(t, e.read(e.configPath()), `"etherscan": "etherscan-secret-value-123"`)
+}
+
+func TestEncryptedConfigWithoutKey(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.mustRun("keys", "encrypt")
+	os.Remove(filepath.Join(e.home, ".config", "agent", "secret.key"))
+
+	assertContains(t, e.mustFail("show"), "cannot decrypt api_keys.etherscan", "restore secret.key")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// fieldDiff is one leaf whose value differs between two configs
+type fieldDiff struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// diffCommand compares the local config with a file or remote URL. Like
+// diff(1) it exits 1 when the configs differ and 2 when one can't be read.
+func diffCommand(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	remote := fs.String("against-remote", "", "compare against the config served at this URL")
+	timeout := timeoutFlag(fs)
+	format := fs.String("format", "fields", "output format: fields or unified")
+	positional := parseFlags(fs, args)
+	if *format != "fields" && *format != "unified" {
+		fmt.Printf("❌ Unknown diff format: %s (use fields or unified)\n", *format)
+		os.Exit(2)
+	}
+
+	local := loadConfig()
+
+	var other AgentConfig
+	var label string
+	switch {
+	case *remote != "":
+		config, err := fetchRemoteConfig(*remote, *timeout)
+		if err != nil {
+			fmt.Printf("❌ Failed to fetch remote config: %v\n", err)
+			os.Exit(2)
+		}
+		other, label = config, *remote
+	case len(positional) == 1:
+		positional[0] = expandPath(positional[0])
+		data, err := readConfigFile(positional[0])
+		if err != nil {
+			fmt.Printf("❌ Failed to read %s: %v\n", positional[0], err)
+			os.Exit(2)
+		}
+		config, err := parseConfig(positional[0], data)
+		if err != nil {
+			fmt.Printf("❌ Invalid config in %s: %v\n", positional[0], err)
+			os.Exit(2)
+		}
+		other, label = config, positional[0]
+	default:
+		fmt.Println("Usage: acm diff <file> | acm diff --against-remote <url>")
+		os.Exit(2)
+	}
+
+	if *format == "unified" {
+		left, right := maskedConfigJSON(local, other)
+		patch := unifiedDiff("local", label, left, right)
+		if patch == "" {
+			fmt.Printf("✅ No differences from %s\n", label)
+			return
+		}
+		fmt.Print(patch)
+		os.Exit(1)
+	}
+
+	diffs := diffConfigs(&local, &other)
+	if len(diffs) == 0 {
+		fmt.Printf("✅ No differences from %s\n", label)
+		return
+	}
+
+	fmt.Printf("🔍 Differences from %s (local → other):\n", label)
+	printFieldDiffs(diffs)
+	os.Exit(1)
+}
+
+// diffAgainstDefaults lists how the config on disk has drifted from
+// defaultConfig, secrets masked as in `acm diff`
+func diffAgainstDefaults(asJSON bool) {
+	defaults, config := defaultConfig(), loadConfig()
+	diffs := diffConfigs(&defaults, &config)
+
+	if asJSON {
+		if diffs == nil {
+			diffs = []fieldDiff{}
+		}
+		data, _ := json.MarshalIndent(diffs, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(diffs) == 0 {
+		fmt.Println("✅ Config matches the defaults")
+		return
+	}
+	fmt.Println("🔍 Changed from the defaults (default → yours):")
+	printFieldDiffs(diffs)
+}
+
+func printFieldDiffs(diffs []fieldDiff) {
+	fmt.Println()
+	for _, d := range diffs {
+		fmt.Printf("  ~ %s: %s → %s\n", d.Key, d.Old, d.New)
+	}
+	fmt.Println()
+	fmt.Printf("Found %d difference(s)\n", len(diffs))
+}
+
+// diffConfigs compares every leaf of a and b. Secret values are never shown;
+// a changed secret is reported as set/unset/changed.
+func diffConfigs(a, b *AgentConfig) []fieldDiff {
+	right := map[string]string{}
+	for _, leaf := range configLeaves(b) {
+		right[leaf.Key] = leafString(leaf.Value)
+	}
+
+	var diffs []fieldDiff
+	for _, leaf := range configLeaves(a) {
+		oldValue := leafString(leaf.Value)
+		newValue := right[leaf.Key]
+		if oldValue == newValue {
+			continue
+		}
+		if isSecretKey(leaf.Key) {
+			oldValue, newValue = maskSecretPair(oldValue, newValue)
+		}
+		diffs = append(diffs, fieldDiff{Key: leaf.Key, Old: oldValue, New: newValue})
+	}
+	return diffs
+}
+
+func secretDiffState(secret string) string {
+	if secret == "" {
+		return "(unset)"
+	}
+	return maskSecret(secret)
+}
+
+// fetchRemoteConfig downloads a canonical config, authenticating with
+// ACM_REMOTE_TOKEN as a bearer token when it is set
+func fetchRemoteConfig(url string, timeout time.Duration) (AgentConfig, error) {
+	ctx, cancel := networkContext(timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	if token := os.Getenv("ACM_REMOTE_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := newHTTPClient(timeout).Do(req)
+	if err != nil {
+		return AgentConfig{}, errors.New(describeNetworkError(err, timeout))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return AgentConfig{}, fmt.Errorf("server responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	// Read one byte past the limit so parseConfig rejects an oversized body
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxConfigBytes()+1))
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	return parseConfig(url, data)
+}
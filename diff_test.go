@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+// remoteConfig serves config as JSON, checking the bearer token when one is
+// expected
+func remoteConfig(t *testing.T, config AgentConfig, token string) *httptest.Server {
+	t.Helper()
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiffAgainstRemoteNoDrift(t *testing.T) {
+	server := remoteConfig(t, defaultConfig(), "")
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustRun("diff", "--against-remote", server.URL), "No differences from "+server.URL)
+}
+
+func TestDiffAgainstRemoteDetectsDrift(t *testing.T) {
+	remote := defaultConfig()
+	remote.Wallet.DailyLimit = 0.8
+	remote.Security.HoneypotEnabled = false
+	remote.APIKeys.Etherscan = "remote-etherscan-key-123456"
+	server := remoteConfig(t, remote, "s3cret")
+
+	e := newTestEnv(t)
+	e.init()
+	e.setenv("ACM_REMOTE_TOKEN", "s3cret")
+	out, code := e.run("diff", "--against-remote", server.URL)
+	if code != 1 {
+		t.Fatalf("exit code %d, want 1 for drift:\n%s", code, out)
+	}
+	assertContains(t, out,
+		"~ wallet.daily_limit: 0.5 → 0.8",
+		"~ security.honeypot_enabled: true → false",
+		"~ api_keys.etherscan: (unset) →",
+		"Found 3 difference(s)",
+	)
+	assertNotContains(t, out, "remote-etherscan-key-123456")
+}
+
+func TestDiffAgainstRemoteErrors(t *testing.T) {
+	server := remoteConfig(t, defaultConfig(), "s3cret")
+	e := newTestEnv(t)
+	e.init()
+	out, code := e.run("diff", "--against-remote", server.URL)
+	if code != 2 {
+		t.Errorf("exit code %d, want 2 when the remote can't be read", code)
+	}
+	assertContains(t, out, "401 Unauthorized")
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer broken.Close()
+	if _, code := e.run("diff", "--against-remote", broken.URL); code != 2 {
+		t.Errorf("exit code %d, want 2 for an invalid remote config", code)
+	}
+}
+
+func TestDiffConfigsMasksSecrets(t *testing.T) {
+	a, b := defaultConfig(), defaultConfig()
+	a.APIKeys.OpenAI = "sk-openai-old-abcdefghijkl"
+	b.APIKeys.OpenAI = "sk-openai-new-abcdefghijkl"
+	diffs := diffConfigs(&a, &b)
+	if len(diffs) != 1 || diffs[0].Key != "api_keys.openai" {
+		t.Fatalf("diffs = %+v", diffs)
+	}
+	for _, value := range []string{diffs[0].Old, diffs[0].New} {
+		if value == a.APIKeys.OpenAI || value == b.APIKeys.OpenAI {
+			t.Errorf("diff shows a secret: %+v", diffs[0])
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15"}
+	b := append(append([]string{}, a...), "16")
+	b[1] = "two"
+	want := `--- a
++++ b
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -13,3 +13,4 @@
+ 13
+ 14
+ 15
++16
+`
+	if got := unifiedDiff("a", "b", a, b); got != want {
+		t.Errorf("unified diff:\n%s\nwant:\n%s", got, want)
+	}
+	if got := unifiedDiff("a", "b", a, a); got != "" {
+		t.Errorf("equal inputs gave:\n%s", got)
+	}
+}
+
+func TestDiffUnifiedFormat(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-local-key-123456")
+	other := defaultConfig()
+	other.Wallet.DailyLimit = 0.8
+	other.APIKeys.Etherscan = "etherscan-other-key-123456"
+	data, _ := json.Marshal(other)
+	path := filepath.Join(e.home, "other.json")
+	e.write(path, string(data))
+
+	out, code := e.run("diff", path, "--format", "unified")
+	if code != 1 {
+		t.Fatalf("exit code %d, want 1:\n%s", code, out)
+	}
+	assertContains(t, out, "--- local\n+++ "+path+"\n", "@@ -", `-    "daily_limit": 0.5,`, `+    "daily_limit": 0.8,`)
+	assertNotContains(t, out, "etherscan-local-key-123456", "etherscan-other-key-123456")
+
+	// secrets are masked the same way as in the field diff
+	local := defaultConfig()
+	local.APIKeys.Etherscan = "etherscan-local-key-123456"
+	oldValue, newValue := maskSecretPair(local.APIKeys.Etherscan, other.APIKeys.Etherscan)
+	assertContains(t, out, `-    "etherscan": "`+oldValue+`"`, `+    "etherscan": "`+newValue+`"`)
+	fields, _ := e.run("diff", path)
+	assertContains(t, fields, "~ api_keys.etherscan: "+oldValue+" → "+newValue)
+
+	e.write(path, e.read(e.configPath()))
+	assertContains(t, e.mustRun("diff", path, "--format", "unified"), "No differences")
+	assertContains(t, e.mustFail("diff", path, "--format", "side-by-side"), "Unknown diff format")
+}
+
+func TestValidateDiffDefaults(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustRun("validate", "--diff-defaults"), "Config matches the defaults")
+
+	e.mustRun("set", "wallet.daily_limit", "0.8")
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-key-123456")
+	out := e.mustRun("validate", "--diff-defaults")
+	assertContains(t, out, "~ wallet.daily_limit: 0.5 → 0.8", "~ api_keys.etherscan: ", "Found 2 difference(s)")
+	assertNotContains(t, out, "etherscan-secret-key-123456", "wallet.alert_threshold", "agent.name", "monitoring.")
+
+	var diffs []fieldDiff
+	out = e.mustRun("validate", "--diff-defaults", "--json")
+	if err := json.Unmarshal([]byte(out), &diffs); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	keys := map[string]bool{}
+	for _, d := range diffs {
+		keys[d.Key] = true
+	}
+	if len(diffs) != 2 || !keys["wallet.daily_limit"] || !keys["api_keys.etherscan"] {
+		t.Errorf("diffs = %+v", diffs)
+	}
+}
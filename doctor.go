@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// doctorCommand checks that the config loads and validates, then runs every
+// network check (API keys, RPC endpoints) concurrently. With the global
+// --fix flag it first repairs what doctorFixes can.
+func doctorCommand(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	timeout, workers, failFast := checkFlags(fs)
+	parseFlags(fs, args)
+
+	path := getConfigPath()
+	fmt.Println("🩺 Checking agent configuration...")
+	fmt.Println()
+
+	config, err := readConfig(path)
+	if err != nil {
+		fmt.Printf("  ❌ Config: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	fmt.Printf("  ✅ Config loads (%s)\n", path)
+
+	healthy := true
+	if fixes := doctorFixes(path, config); len(fixes) > 0 {
+		if fixMode {
+			var ok bool
+			if config, ok = applyDoctorFixes(path, config, fixes); !ok {
+				healthy = false
+			}
+		} else {
+			healthy = false
+			for _, fix := range fixes {
+				fmt.Printf("  ⚠️  %s (fixable with 'acm doctor --fix')\n", fix.Problem)
+			}
+		}
+	}
+
+	if err := expandConfigEnvRefs(&config); err != nil {
+		fmt.Printf("  ❌ Config: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	resolveSecrets(&config)
+
+	findings := runValidation(config, validationContext{Dir: filepath.Dir(path)}, validationRules)
+	if hasErrors(findings) {
+		healthy = false
+		fmt.Println("  ❌ Validation failed:")
+	} else {
+		fmt.Println("  ✅ Validation passed")
+	}
+	for _, f := range sortFindings(findings) {
+		fmt.Printf("     %s\n", f.colored())
+	}
+
+	checks := append(keyChecks(config, *timeout), rpcChecks(config, *timeout)...)
+	if len(checks) > 0 && (healthy || !*failFast) {
+		ctx, cancel := networkContext(*timeout)
+		defer cancel()
+		if !printCheckResults(runNetworkChecks(ctx, checks, *workers, *failFast), *timeout) {
+			healthy = false
+		}
+	}
+
+	fmt.Println()
+	if !healthy {
+		fmt.Println("❌ Problems found")
+		os.Exit(1)
+	}
+	fmt.Println("✅ Everything looks good")
+}
+
+// rpcChecks builds a check for every networks.<name>.rpc_url, confirming
+// the endpoint answers eth_chainId with the chain the name implies
+func rpcChecks(config AgentConfig, timeout time.Duration) []networkCheck {
+	client := newHTTPClient(timeout)
+	var checks []networkCheck
+	for _, name := range sortedKeys(config.Networks) {
+		name, rpcURL := name, config.Networks[name].RPCURL
+		if rpcURL == "" {
+			continue
+		}
+		checks = append(checks, networkCheck{Name: "networks." + name + ".rpc_url", Run: func(ctx context.Context) error {
+			chainID, err := fetchChainID(ctx, client, rpcURL)
+			if err != nil {
+				return err
+			}
+			if known, ok := knownNetworks[name]; ok && known.ChainID != chainID {
+				return fmt.Errorf("endpoint is on chain %d, expected %d for %s", chainID, known.ChainID, name)
+			}
+			return nil
+		}})
+	}
+	return checks
+}
+
+// fetchChainID calls eth_chainId on a JSON-RPC endpoint
+func fetchChainID(ctx context.Context, client *http.Client, rpcURL string) (int64, error) {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("server responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var reply struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&reply); err != nil {
+		return 0, fmt.Errorf("not a JSON-RPC response: %v", err)
+	}
+	if reply.Error != nil {
+		return 0, fmt.Errorf("rpc error: %s", reply.Error.Message)
+	}
+	return strconv.ParseInt(strings.TrimPrefix(reply.Result, "0x"), 16, 64)
+}
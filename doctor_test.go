@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDoctorFix(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.editConfig(func(config map[string]interface{}) {
+		section(config, "wallet")["networks"] = []string{"ethereum", "base", "ethereum"}
+		monitoring := section(config, "monitoring")
+		monitoring["dashboard_enabled"] = true
+		monitoring["dashboard_port"] = 0
+	})
+	os.Chmod(e.configPath(), 0644)
+	before := e.read(e.configPath())
+
+	out := e.mustFail("doctor")
+	assertContains(t, out,
+		"Config is mode 0644, readable by others (fixable with 'acm doctor --fix')",
+		"exports does not exist (fixable with 'acm doctor --fix')",
+		"wallet.networks lists a network more than once (fixable",
+		"Dashboard is enabled but dashboard_port is not set (fixable",
+	)
+	if e.read(e.configPath()) != before {
+		t.Error("doctor changed the config without --fix")
+	}
+
+	out = e.mustRun("doctor", "--fix")
+	assertContains(t, out,
+		"🔧 Fixed: chmod 0600 "+e.configPath()+" (was 0644)",
+		"🔧 Fixed: created "+e.exportDir(),
+		"🔧 Fixed: removed duplicate wallet.networks entries",
+		"🔧 Fixed: set monitoring.dashboard_port to 8080",
+		"💾 Backed up config to ",
+		"Everything looks good",
+	)
+	if info, err := os.Stat(e.configPath()); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("config mode after --fix: %v %v", info.Mode(), err)
+	}
+	if info, err := os.Stat(e.exportDir()); err != nil || !info.IsDir() {
+		t.Errorf("export dir after --fix: %v", err)
+	}
+	assertContains(t, e.mustRun("get", "wallet.networks"), `["ethereum","base"]`)
+	assertContains(t, e.mustRun("get", "monitoring.dashboard_port"), "8080")
+
+	// the config edits can be undone from the backup
+	backups, _ := filepath.Glob(filepath.Join(e.stateDir(), "backups", "*.json"))
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v", backups)
+	}
+	if got := e.read(backups[0]); got != before {
+		t.Errorf("backup holds:\n%s\nwant the config before the fixes:\n%s", got, before)
+	}
+
+	assertNotContains(t, e.mustRun("doctor"), "fixable", "Fixed:")
+}
+
+func TestDoctorFixOnlyFilesystem(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	out := e.mustRun("doctor", "--fix")
+	assertContains(t, out, "🔧 Fixed: created "+e.exportDir())
+	assertNotContains(t, out, "Backed up")
+	if _, err := os.Stat(filepath.Join(e.stateDir(), "backups")); !os.IsNotExist(err) {
+		t.Errorf("a fix that doesn't edit the config made a backup: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// doctorFix is a problem `acm doctor --fix` can repair safely. Fixes that
+// edit the config are applied together, after a backup.
+type doctorFix struct {
+	Problem string
+	Fix     string
+	// EditsConfig fixes change config, which is then saved; the others
+	// act on the file system directly
+	EditsConfig bool
+	Apply       func(config *AgentConfig) error
+}
+
+// doctorFixes finds the fixable problems with the config at path. config
+// must be as read from disk, without env references or secrets resolved,
+// since it is saved back.
+func doctorFixes(path string, config AgentConfig) []doctorFix {
+	var fixes []doctorFix
+
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0600 {
+		mode := info.Mode().Perm()
+		fixes = append(fixes, doctorFix{
+			Problem: fmt.Sprintf("Config is mode %04o, readable by others", mode),
+			Fix:     fmt.Sprintf("chmod 0600 %s (was %04o)", path, mode),
+			Apply:   func(*AgentConfig) error { return os.Chmod(path, 0600) },
+		})
+	}
+
+	if dir := getExportDir(); !dirExists(dir) {
+		fixes = append(fixes, doctorFix{
+			Problem: fmt.Sprintf("Export directory %s does not exist", dir),
+			Fix:     "created " + dir,
+			Apply:   func(*AgentConfig) error { return os.MkdirAll(dir, 0700) },
+		})
+	}
+
+	if deduped := dedupeStrings(config.Wallet.Networks); len(deduped) != len(config.Wallet.Networks) {
+		fixes = append(fixes, doctorFix{
+			Problem:     "wallet.networks lists a network more than once",
+			Fix:         "removed duplicate wallet.networks entries",
+			EditsConfig: true,
+			Apply: func(c *AgentConfig) error {
+				c.Wallet.Networks = dedupeStrings(c.Wallet.Networks)
+				return nil
+			},
+		})
+	}
+
+	if m := config.Monitoring; m.DashboardEnabled && m.DashboardPort == 0 {
+		port := defaultConfig().Monitoring.DashboardPort
+		fixes = append(fixes, doctorFix{
+			Problem:     "Dashboard is enabled but dashboard_port is not set",
+			Fix:         fmt.Sprintf("set monitoring.dashboard_port to %d", port),
+			EditsConfig: true,
+			Apply: func(c *AgentConfig) error {
+				c.Monitoring.DashboardPort = port
+				return nil
+			},
+		})
+	}
+	return fixes
+}
+
+// applyDoctorFixes applies fixes to the config at path, backing it up first
+// when any of them edits it, and reports each one. It returns the config as
+// saved and whether every fix succeeded.
+func applyDoctorFixes(path string, config AgentConfig, fixes []doctorFix) (AgentConfig, bool) {
+	ok := true
+	edited := false
+	for _, fix := range fixes {
+		if fix.EditsConfig && !edited {
+			backup, err := backupConfig()
+			if err != nil {
+				fmt.Printf("  ❌ Not editing the config, backup failed: %v\n", err)
+				return config, false
+			}
+			fmt.Printf("  💾 Backed up config to %s\n", backup)
+			edited = true
+		}
+		if err := fix.Apply(&config); err != nil {
+			fmt.Printf("  ❌ %s: %v\n", fix.Problem, err)
+			ok = false
+			continue
+		}
+		fmt.Printf("  🔧 Fixed: %s\n", fix.Fix)
+	}
+	if edited {
+		if err := writeConfig(path, config); err != nil {
+			fmt.Printf("  ❌ Failed to save fixes: %v\n", err)
+			return config, false
+		}
+	}
+	return config, ok
+}
+
+func dedupeStrings(list []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// strictMode is set by the global --strict flag: problems that are only
+// warnings by default, like duplicate keys, become errors
+var strictMode bool
+
+// duplicateKey is a key that appears more than once in the same object
+type duplicateKey struct {
+	Key  string // dotted path
+	Line int
+}
+
+// findDuplicateKeys scans JSON for objects that repeat a key. encoding/json
+// silently keeps the last value, which usually hides a botched manual merge.
+func findDuplicateKeys(data []byte) []duplicateKey {
+	var dups []duplicateKey
+	seen := map[string]bool{}
+	walkJSON(data, func(t jsonToken) {
+		if !t.IsKey {
+			return
+		}
+		if seen[t.Path] {
+			line := 1 + bytes.Count(data[:t.Start], []byte("\n"))
+			dups = append(dups, duplicateKey{Key: t.Path, Line: line})
+		}
+		seen[t.Path] = true
+	})
+	return dups
+}
+
+// checkDuplicateKeys warns about duplicate keys in a config file, or fails
+// under --strict
+func checkDuplicateKeys(path string, data []byte) error {
+	dups := findDuplicateKeys(data)
+	if len(dups) == 0 {
+		return nil
+	}
+	if strictMode {
+		msgs := make([]string, len(dups))
+		for i, d := range dups {
+			msgs[i] = fmt.Sprintf("%s (line %d)", d.Key, d.Line)
+		}
+		return fmt.Errorf("duplicate keys: %s", strings.Join(msgs, ", "))
+	}
+	name := path
+	if name == "" {
+		name = "config"
+	}
+	for _, d := range dups {
+		fmt.Fprintf(os.Stderr, "⚠️  %s:%d: duplicate key %s, the last value wins\n", name, d.Line, d.Key)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+const duplicatedLimit = `{
+  "version": "0.1.0",
+  "wallet": {
+    "daily_limit": 0.5,
+    "daily_limit": 5
+  },
+  "monitoring": {"dashboard_port": 8080}
+}`
+
+func TestFindDuplicateKeys(t *testing.T) {
+	dups := findDuplicateKeys([]byte(duplicatedLimit))
+	if len(dups) != 1 || dups[0] != (duplicateKey{Key: "wallet.daily_limit", Line: 5}) {
+		t.Errorf("duplicates = %+v, want wallet.daily_limit on line 5", dups)
+	}
+
+	// the same name in different objects is fine
+	if dups := findDuplicateKeys([]byte(`{"a": {"name": 1}, "b": {"name": 2}, "name": 3}`)); len(dups) != 0 {
+		t.Errorf("duplicates = %+v, want none", dups)
+	}
+	if dups := findDuplicateKeys([]byte(`{"version": "1", "version": "2", "x": {"y": {"z": 1, "z": 2}}}`)); len(dups) != 2 || dups[1].Key != "x.y.z" {
+		t.Errorf("duplicates = %+v, want version and x.y.z", dups)
+	}
+}
+
+func TestDuplicateKeyWarning(t *testing.T) {
+	e := newTestEnv(t)
+	e.write(e.configPath(), duplicatedLimit)
+
+	out := e.mustRun("get", "wallet.daily_limit")
+	assertContains(t, out, e.configPath()+":5: duplicate key wallet.daily_limit, the last value wins", "5")
+
+	out = e.mustFail("--strict", "get", "wallet.daily_limit")
+	assertContains(t, out, "duplicate keys: wallet.daily_limit (line 5)")
+}
+
+func TestDuplicateKeyInYAML(t *testing.T) {
+	e := newTestEnv(t)
+	path := e.configPath()[:len(e.configPath())-len(".json")] + ".yaml"
+	e.write(path, "version: 0.1.0\nwallet:\n  daily_limit: 0.5\n  daily_limit: 5\n")
+	e.setenv("ACM_CONFIG", path)
+
+	// the warning points at the line in the YAML, not the converted JSON
+	assertContains(t, e.mustRun("get", "wallet.daily_limit"), path+":4: duplicate key wallet.daily_limit")
+}
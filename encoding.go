@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// fixMode is set by the global --fix flag: a config saved with a byte
+// order mark or CRLF line endings is rewritten as plain UTF-8 with LF, and
+// `acm doctor` applies its safe fixes
+var fixMode bool
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// warnedBOM remembers the configs already warned about, like warnedAliases
+var warnedBOM = map[string]bool{}
+
+// normalizeEncoding strips a UTF-8 byte order mark, which editors on Windows
+// like to add and encoding/json rejects, warning once per file. UTF-16 can't
+// be decoded as JSON at all, so it gets a clear error instead of a parse error
+// on the first byte.
+func normalizeEncoding(path string, data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, utf16LEBOM) || bytes.HasPrefix(data, utf16BEBOM) {
+		return nil, fmt.Errorf("file is UTF-16 encoded; save it as UTF-8")
+	}
+	if !bytes.HasPrefix(data, utf8BOM) {
+		return data, nil
+	}
+	if !warnedBOM[path] {
+		warnedBOM[path] = true
+		name := path
+		if name == "" {
+			name = "config"
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  %s: ignoring UTF-8 byte order mark (run with --fix to remove it)\n", name)
+	}
+	return data[len(utf8BOM):], nil
+}
+
+// repairEncoding rewrites the config at path without a byte order mark and
+// with LF line endings when --fix is given, returning the new contents
+func repairEncoding(path string, data []byte) ([]byte, error) {
+	if !fixMode {
+		return data, nil
+	}
+	fixed := bytes.TrimPrefix(data, utf8BOM)
+	fixed = bytes.ReplaceAll(fixed, []byte("\r\n"), []byte("\n"))
+	fixed = bytes.TrimSuffix(fixed, []byte("\r"))
+	if bytes.Equal(fixed, data) {
+		return data, nil
+	}
+	if err := writeFileAtomic(path, fixed, 0600); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "🔧 Rewrote %s as UTF-8 with LF line endings\n", path)
+	return fixed, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigWithBOM(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "agent.name", "bom-agent")
+	original := e.read(e.configPath())
+	crlf := strings.ReplaceAll(original, "\n", "\r\n")
+	e.write(e.configPath(), "\xEF\xBB\xBF"+crlf)
+
+	out := e.mustRun("get", "agent.name")
+	assertContains(t, out, "bom-agent", "ignoring UTF-8 byte order mark (run with --fix to remove it)")
+	if n := strings.Count(out, "byte order mark"); n != 1 {
+		t.Errorf("warned %d times, want once:\n%s", n, out)
+	}
+	if !strings.HasPrefix(e.read(e.configPath()), "\xEF\xBB\xBF") {
+		t.Error("the file was rewritten without --fix")
+	}
+
+	out = e.mustRun("--fix", "get", "agent.name")
+	assertContains(t, out, "bom-agent", "Rewrote "+e.configPath()+" as UTF-8 with LF line endings")
+	if got := e.read(e.configPath()); got != original {
+		t.Errorf("--fix left:\n%q\nwant:\n%q", got, original)
+	}
+	assertNotContains(t, e.mustRun("get", "agent.name"), "byte order mark")
+}
+
+func TestLoadConfigUTF16(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.write(e.configPath(), "\xFF\xFE{\x00}\x00")
+	assertContains(t, e.mustFail("get", "agent.name"), "file is UTF-16 encoded; save it as UTF-8")
+}
+
+func TestNormalizeEncoding(t *testing.T) {
+	data, err := normalizeEncoding("", []byte("\xEF\xBB\xBF{}"))
+	if err != nil || string(data) != "{}" {
+		t.Errorf("normalizeEncoding = %q, %v", data, err)
+	}
+	if data, err := normalizeEncoding("", []byte("{}")); err != nil || string(data) != "{}" {
+		t.Errorf("normalizeEncoding without a BOM = %q, %v", data, err)
+	}
+	if _, err := normalizeEncoding("", []byte("\xFE\xFF\x00{")); err == nil {
+		t.Error("UTF-16 BE was accepted")
+	}
+}
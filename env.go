@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var envPrefixPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// normalizeEnvPrefix turns a --prefix value such as agent1 into AGENT1_, so
+// several agents' variables can share one environment
+func normalizeEnvPrefix(prefix string) (string, error) {
+	if prefix == "" {
+		return "", nil
+	}
+	if !envPrefixPattern.MatchString(prefix) {
+		return "", fmt.Errorf("invalid env prefix %q: use letters, digits and underscores, not starting with a digit", prefix)
+	}
+	return strings.TrimSuffix(strings.ToUpper(prefix), "_") + "_", nil
+}
+
+// envVarName is the canonical environment variable for a config key. API
+// keys use the names providers document (ETHERSCAN_API_KEY, ...); every
+// other field is its dotted key upper-cased with dots as underscores.
+func envVarName(key string) string {
+	for _, env := range apiKeyEnvVars {
+		if env.Key == key {
+			return env.Names[0]
+		}
+	}
+	if key == "version" {
+		return "AGENT_CONFIG_VERSION"
+	}
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// envMapCommand prints the env var that each config key maps to, with the
+// same --prefix as `acm export --format env`
+func envMapCommand(args []string) {
+	fs := flag.NewFlagSet("env-map", flag.ExitOnError)
+	rawPrefix := fs.String("prefix", "", "prefix every variable, as with export --prefix")
+	parseFlags(fs, args)
+	prefix, err := normalizeEnvPrefix(*rawPrefix)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	var config AgentConfig
+	for _, leaf := range exportLeaves(&config) {
+		fmt.Printf("%s -> %s\n", leaf.Key, prefix+envVarName(leaf.Key))
+	}
+}
+
+// exportEnv renders every leaf as a NAME=value line, using the same names
+// as `acm env-map`, each prefixed with --prefix
+func exportEnv(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	prefix, err := normalizeEnvPrefix(opts.EnvPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by agent-config-manager v%s\n", version)
+	for _, leaf := range exportLeaves(&config) {
+		fmt.Fprintf(&b, "%s%s=%s\n", prefix, envVarName(leaf.Key), envValue(leaf.Value))
+	}
+	for _, derived := range derivedExportKeys {
+		fmt.Fprintf(&b, "%s%s=%s\n", prefix, envVarName(derived.Key), derived.Value(config))
+	}
+	return []exportFile{{Name: "agent.env", Data: []byte(b.String())}}, nil
+}
+
+// envString renders a leaf as an environment value; lists are
+// comma-separated and maps such as networks are JSON, as in consul-kv
+func envString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	case reflect.Map:
+		if v.Len() == 0 {
+			return ""
+		}
+		return leafString(v)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// envValue renders a leaf for a dotenv file, double-quoting values with
+// shell-significant characters
+func envValue(v reflect.Value) string {
+	value := envString(v)
+	if value == "" || !strings.ContainsAny(value, " \t\"'$`\\#\n") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`", "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
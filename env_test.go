@@ -0,0 +1,102 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// envNames returns the variable names in a dotenv file
+func envNames(data string) map[string]bool {
+	names := map[string]bool{}
+	for _, line := range strings.Split(data, "\n") {
+		if name, _, ok := strings.Cut(line, "="); ok && !strings.HasPrefix(line, "#") {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func TestEnvMapMatchesEnvExport(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.mustRun("export", "--format", "env")
+	exported := envNames(e.read(filepath.Join(e.exportDir(), "agent.env")))
+
+	mapped := 0
+	for _, line := range strings.Split(strings.TrimSpace(e.mustRun("env-map")), "\n") {
+		key, name, ok := strings.Cut(line, " -> ")
+		if !ok {
+			t.Fatalf("env-map line %q isn't key -> NAME", line)
+		}
+		if !exported[name] {
+			t.Errorf("env-map maps %s to %s, which the env export doesn't write", key, name)
+		}
+		mapped++
+	}
+	if want := len(exported) - len(derivedExportKeys); mapped != want {
+		t.Errorf("env-map lists %d keys, the export writes %d besides derived ones", mapped, want)
+	}
+}
+
+func TestEnvMapNames(t *testing.T) {
+	out := newTestEnv(t).mustRun("env-map", "--prefix", "agent1")
+	assertContains(t, out,
+		"wallet.daily_limit -> AGENT1_WALLET_DAILY_LIMIT\n",
+		"api_keys.etherscan -> AGENT1_ETHERSCAN_API_KEY\n",
+		"version -> AGENT1_AGENT_CONFIG_VERSION\n",
+	)
+}
+
+func TestNormalizeEnvPrefix(t *testing.T) {
+	for in, want := range map[string]string{"": "", "agent1": "AGENT1_", "AGENT_": "AGENT_"} {
+		if got, err := normalizeEnvPrefix(in); err != nil || got != want {
+			t.Errorf("normalizeEnvPrefix(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"1agent", "my-agent", "a b"} {
+		if _, err := normalizeEnvPrefix(in); err == nil {
+			t.Errorf("normalizeEnvPrefix(%q) accepted", in)
+		}
+	}
+}
+
+func TestEnvExportPrefix(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export", "--format", "env", "--prefix", "agent1")
+	data := e.read(filepath.Join(e.exportDir(), "agent.env"))
+	exported := envNames(data)
+	for name := range exported {
+		if !strings.HasPrefix(name, "AGENT1_") {
+			t.Errorf("%s is not prefixed", name)
+		}
+	}
+	assertContains(t, data, "AGENT1_WALLET_ADDRESS=", "AGENT1_ETHERSCAN_API_KEY=", "AGENT1_MONITORING_CHECK_INTERVAL_SECONDS=300\n")
+
+	for _, line := range strings.Split(strings.TrimSpace(e.mustRun("env-map", "--prefix", "agent1")), "\n") {
+		_, name, _ := strings.Cut(line, " -> ")
+		if !exported[name] {
+			t.Errorf("env-map --prefix names %s, which the prefixed export doesn't write", name)
+		}
+	}
+
+	assertContains(t, e.mustFail("export", "--format", "env", "--prefix", "my-agent"), `invalid env prefix "my-agent"`)
+	assertContains(t, e.mustFail("env-map", "--prefix", "1agent"), `invalid env prefix "1agent"`)
+}
+
+func TestEnvExportNetworks(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export", "--format", "env")
+	assertContains(t, e.read(filepath.Join(e.exportDir(), "agent.env")), "\nNETWORKS=\n")
+
+	e.mustRun("set", "networks.base.daily_limit", "0.2")
+	e.mustRun("export", "--format", "env")
+	data := e.read(filepath.Join(e.exportDir(), "agent.env"))
+	// per-network overrides are JSON, as in the consul-kv export
+	assertContains(t, data, `NETWORKS="{\"base\":{\"daily_limit\":0.2}}"`+"\n")
+	assertNotContains(t, data, "map[")
+	assertContains(t, e.mustRun("env-map"), "networks -> NETWORKS\n")
+}
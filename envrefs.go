@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// envRefPattern matches ${NAME} references to environment variables
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+func hasEnvRefs(value string) bool {
+	return envRefPattern.MatchString(value)
+}
+
+// expandEnvRefs replaces every ${NAME} in value with the variable's value.
+// A variable that isn't defined is an error rather than an empty string, so
+// a missing export can't silently blank a URL or key.
+func expandEnvRefs(value string) (string, error) {
+	var missing []string
+	expanded := envRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := envRefPattern.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("undefined environment variable(s): %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// expandConfigEnvRefs expands the references stored by `acm set --lazy`.
+// Like the secrets overlay, the result is for reading only and must never be
+// saved, or the references would be lost. Map values are copied rather than
+// changed in place since config may share them with a cached copy.
+func expandConfigEnvRefs(config *AgentConfig) error {
+	return expandValueEnvRefs(reflect.ValueOf(config).Elem(), "")
+}
+
+func expandValueEnvRefs(v reflect.Value, key string) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !hasEnvRefs(v.String()) {
+			return nil
+		}
+		expanded, err := expandEnvRefs(v.String())
+		if err != nil {
+			return validationError(key, fmt.Errorf("%s: %v", key, err))
+		}
+		v.SetString(expanded)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := jsonName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			if err := expandValueEnvRefs(v.Field(i), joinKey(key, name)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			if err := expandValueEnvRefs(elem, joinKey(key, iter.Key().String())); err != nil {
+				return err
+			}
+			copied.SetMapIndex(iter.Key(), elem)
+		}
+		v.Set(copied)
+	}
+	return nil
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandEnvRefs(t *testing.T) {
+	t.Setenv("ACM_TEST_HOST", "hooks.example.com")
+	t.Setenv("ACM_TEST_EMPTY", "")
+	if got, err := expandEnvRefs("https://${ACM_TEST_HOST}/a${ACM_TEST_EMPTY}"); err != nil || got != "https://hooks.example.com/a" {
+		t.Errorf("expandEnvRefs = %q, %v", got, err)
+	}
+	if got, _ := expandEnvRefs("$ACM_TEST_HOST and $$"); got != "$ACM_TEST_HOST and $$" {
+		t.Errorf("only ${NAME} is a reference, got %q", got)
+	}
+	_, err := expandEnvRefs("${ACM_TEST_UNDEFINED_A}/${ACM_TEST_UNDEFINED_B}")
+	if err == nil || err.Error() != "undefined environment variable(s): ACM_TEST_UNDEFINED_A, ACM_TEST_UNDEFINED_B" {
+		t.Errorf("undefined variables: %v", err)
+	}
+}
+
+func TestSetExpandEnv(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.setenv("SLACK_URL", "https://hooks.example.com/eager")
+	e.mustRun("set", "--expand-env", "monitoring.webhook_url", "${SLACK_URL}")
+
+	assertContains(t, e.read(e.configPath()), `"webhook_url": "https://hooks.example.com/eager"`)
+}
+
+func TestSetLazy(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.setenv("SLACK_URL", "https://hooks.example.com/lazy")
+	e.mustRun("set", "--lazy", "monitoring.webhook_url", "${SLACK_URL}")
+
+	assertContains(t, e.read(e.configPath()), `"webhook_url": "${SLACK_URL}"`)
+	assertContains(t, e.mustRun("get", "monitoring.webhook_url"), "https://hooks.example.com/lazy")
+
+	// the reference is expanded on every read, so exports follow the variable
+	e.setenv("SLACK_URL", "https://hooks.example.com/changed")
+	e.mustRun("export")
+	assertContains(t, e.read(filepath.Join(e.exportDir(), "wallet-monitor.json")), "https://hooks.example.com/changed")
+	assertContains(t, e.read(e.configPath()), `"webhook_url": "${SLACK_URL}"`)
+
+	assertContains(t, e.mustFail("set", "--lazy", "wallet.daily_limit", "${LIMIT}"), "--lazy only works for text values")
+}
+
+func TestSetUndefinedEnvRef(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	before := e.read(e.configPath())
+
+	assertContains(t, e.mustFail("set", "--expand-env", "monitoring.webhook_url", "${NOT_DEFINED}"), "undefined environment variable(s): NOT_DEFINED")
+	assertContains(t, e.mustFail("set", "--lazy", "monitoring.webhook_url", "${NOT_DEFINED}"), "NOT_DEFINED")
+	assertContains(t, e.mustFail("set", "monitoring.webhook_url", "${SLACK_URL}"), "Use --expand-env")
+	assertContains(t, e.mustFail("set", "--expand-env", "--lazy", "agent.name", "x"), "Usage")
+	if e.read(e.configPath()) != before {
+		t.Error("a failed set changed the config")
+	}
+}
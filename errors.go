@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// ErrorKind classifies config failures so callers can react to the kind
+// instead of matching messages
+type ErrorKind int
+
+const (
+	KindIO ErrorKind = iota + 1
+	KindNotFound
+	KindParse
+	KindValidation
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindIO:
+		return "io"
+	case KindNotFound:
+		return "not found"
+	case KindParse:
+		return "parse"
+	case KindValidation:
+		return "validation"
+	}
+	return "unknown"
+}
+
+// Sentinels for errors.Is; a ConfigError matches the one for its kind
+var (
+	ErrIO         = errors.New("config i/o error")
+	ErrNotFound   = errors.New("config not found")
+	ErrParse      = errors.New("config parse error")
+	ErrValidation = errors.New("invalid config value")
+)
+
+// ConfigError is returned by the config loader, writer and setter
+type ConfigError struct {
+	Kind ErrorKind
+	Path string // the config file, when known
+	Key  string // the dotted key, for validation errors
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Path != "" && e.Kind != KindValidation {
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+func (e *ConfigError) Is(target error) bool {
+	switch target {
+	case ErrIO:
+		return e.Kind == KindIO
+	case ErrNotFound:
+		return e.Kind == KindNotFound
+	case ErrParse:
+		return e.Kind == KindParse
+	case ErrValidation:
+		return e.Kind == KindValidation
+	}
+	return false
+}
+
+// ioError wraps a file system error, classifying a missing file as
+// KindNotFound
+func ioError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	kind := KindIO
+	if errors.Is(err, fs.ErrNotExist) {
+		kind = KindNotFound
+	}
+	return &ConfigError{Kind: kind, Path: path, Err: err}
+}
+
+func parseError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ConfigError{Kind: KindParse, Path: path, Err: err}
+}
+
+func validationError(key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ConfigError{Kind: KindValidation, Key: key, Err: err}
+}
+
+// Exit codes for config failures. Anything else exits 1.
+const (
+	exitNotFound   = 2
+	exitParse      = 3
+	exitValidation = 4
+	exitIO         = 5
+)
+
+// exitCode maps an error to the CLI's exit code contract
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, ErrParse):
+		return exitParse
+	case errors.Is(err, ErrValidation):
+		return exitValidation
+	case errors.Is(err, ErrIO):
+		return exitIO
+	}
+	return 1
+}
+
+// exitWith prints message and exits with the code for err
+func exitWith(err error, format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+	os.Exit(exitCode(err))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigErrorKinds(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.json")
+	os.WriteFile(broken, []byte(`{"wallet": `), 0600)
+	blocker := filepath.Join(dir, "file")
+	os.WriteFile(blocker, []byte("x"), 0600)
+
+	_, missing := readConfig(filepath.Join(dir, "missing.json"))
+	_, parse := readConfig(broken)
+	_, isDir := readConfig(dir)
+	write := writeConfig(filepath.Join(blocker, "config.json"), defaultConfig())
+	config := defaultConfig()
+	invalid := assignValue(&config, "wallet.daily_limit", "abc", false)
+
+	for _, tt := range []struct {
+		name string
+		err  error
+		want error
+		code int
+	}{
+		{"missing file", missing, ErrNotFound, exitNotFound},
+		{"broken JSON", parse, ErrParse, exitParse},
+		{"directory", isDir, ErrIO, exitIO},
+		{"unwritable path", write, ErrIO, exitIO},
+		{"invalid value", invalid, ErrValidation, exitValidation},
+	} {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: %v is not %v", tt.name, tt.err, tt.want)
+		}
+		for _, other := range []error{ErrNotFound, ErrParse, ErrIO, ErrValidation} {
+			if other != tt.want && errors.Is(tt.err, other) {
+				t.Errorf("%s: %v also matches %v", tt.name, tt.err, other)
+			}
+		}
+		if code := exitCode(tt.err); code != tt.code {
+			t.Errorf("%s: exit code %d, want %d", tt.name, code, tt.code)
+		}
+	}
+
+	var configErr *ConfigError
+	if !errors.As(invalid, &configErr) || configErr.Key != "wallet.daily_limit" {
+		t.Errorf("validation error = %#v, want the key", invalid)
+	}
+	if !errors.Is(missing, os.ErrNotExist) {
+		t.Error("not found error doesn't wrap os.ErrNotExist")
+	}
+	if exitCode(errors.New("other")) != 1 {
+		t.Error("unclassified errors should exit 1")
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	e := newTestEnv(t)
+	if _, code := e.run("show"); code != exitNotFound {
+		t.Errorf("show without a config exited %d, want %d", code, exitNotFound)
+	}
+	e.init()
+	if _, code := e.run("set", "wallet.daily_limit", "abc"); code != exitValidation {
+		t.Errorf("invalid set exited %d, want %d", code, exitValidation)
+	}
+	e.write(e.configPath(), `{"wallet": `)
+	if _, code := e.run("show"); code != exitParse {
+		t.Errorf("show of broken JSON exited %d, want %d", code, exitParse)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/sha3"
+)
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// pubkeyAddress derives the Ethereum address of a secp256k1 public key: the
+// last 20 bytes of the Keccak-256 hash of its uncompressed X||Y coordinates
+func pubkeyAddress(pub *secp256k1.PublicKey) string {
+	hash := keccak256(pub.SerializeUncompressed()[1:])
+	return checksumAddress("0x" + hex.EncodeToString(hash[12:]))
+}
+
+// isValidEthereumAddress reports whether address is 0x followed by 40 hex
+// digits in their EIP-55 checksum case. An all-lowercase address fails, since
+// it carries no checksum to catch a typo.
+func isValidEthereumAddress(address string) bool {
+	return hexAddressPattern.MatchString(address) && checksumAddress(address) == address
+}
+
+// checksumAddress applies EIP-55 mixed-case checksumming to a hex address
+func checksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsValidEthereumAddress(t *testing.T) {
+	// the test vectors from EIP-55
+	for _, address := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		if !isValidEthereumAddress(address) {
+			t.Errorf("%s rejected", address)
+		}
+		if got := checksumAddress(strings.ToLower(address)); got != address {
+			t.Errorf("checksumAddress = %s, want %s", got, address)
+		}
+	}
+
+	for _, address := range []string{
+		"",
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAedd",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+	} {
+		if isValidEthereumAddress(address) {
+			t.Errorf("%q accepted", address)
+		}
+	}
+}
+
+func TestValidateReportsBadChecksums(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	lower := strings.ToLower(testAddrB)
+	e.editConfig(func(config map[string]interface{}) {
+		section(config, "wallet")["address"] = lower
+		section(config, "security")["blacklisted_addresses"] = []string{lower}
+	})
+
+	out, _ := e.run("validate")
+	assertContains(t, out,
+		"❌ Invalid value for wallet.address: \""+lower+"\" fails the EIP-55 checksum (expected "+testAddrB+")",
+		"❌ Invalid value for security.blacklisted_addresses: ",
+		"2 error(s)")
+	assertContains(t, e.mustFail("add", "security.whitelisted_addresses", lower), "fails the EIP-55 checksum")
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// APIKeyMeta is what's known about an API key besides its value. It lives
+// under api_keys_meta, apart from the secrets, and is never exported.
+type APIKeyMeta struct {
+	// ExpiresAt is a date (2006-01-02) or RFC 3339 timestamp
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// keyExpiryWarnDays is how far ahead `acm validate` warns about expiring keys
+const keyExpiryWarnDays = 14
+
+// keyExpiry is the expiry state of one key
+type keyExpiry struct {
+	Key       string `json:"key"`
+	ExpiresAt string `json:"expires_at"`
+	DaysLeft  int    `json:"days_left"`
+	Status    string `json:"status"` // "expired", "expiring" or "ok"
+}
+
+// parseExpiry accepts a plain date, meaning the end of that day in UTC, or
+// an RFC 3339 timestamp
+func parseExpiry(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date (YYYY-MM-DD) or RFC 3339 time", value)
+	}
+	return t, nil
+}
+
+// apiKeyNames are the names api_keys_meta entries may use
+func apiKeyNames() []string {
+	names := make([]string, len(apiKeyEnvVars))
+	for i, env := range apiKeyEnvVars {
+		names[i] = strings.TrimPrefix(env.Key, "api_keys.")
+	}
+	return names
+}
+
+// keyExpiries reports every key with an expiry date, soonest first. Entries
+// naming an unknown key or holding a bad date are returned as errors.
+func keyExpiries(config AgentConfig, now time.Time, warnDays int) ([]keyExpiry, []error) {
+	var expiries []keyExpiry
+	var errs []error
+	for name, meta := range config.APIKeysMeta {
+		if meta.ExpiresAt == "" {
+			continue
+		}
+		if !containsString(apiKeyNames(), name) {
+			errs = append(errs, fmt.Errorf("api_keys_meta.%s: unknown key (use %s)", name, strings.Join(apiKeyNames(), ", ")))
+			continue
+		}
+		expires, err := parseExpiry(meta.ExpiresAt)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("api_keys_meta.%s.expires_at: %v", name, err))
+			continue
+		}
+
+		left := expires.Sub(now)
+		e := keyExpiry{Key: name, ExpiresAt: meta.ExpiresAt, DaysLeft: int(left.Hours() / 24), Status: "ok"}
+		switch {
+		case left <= 0:
+			e.Status = "expired"
+		case left <= time.Duration(warnDays)*24*time.Hour:
+			e.Status = "expiring"
+		}
+		expiries = append(expiries, e)
+	}
+	sort.Slice(expiries, func(i, j int) bool { return expiries[i].DaysLeft < expiries[j].DaysLeft })
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return expiries, errs
+}
+
+// checkKeyExpiry fails on expired keys and warns about keys expiring within
+// keyExpiryWarnDays
+func checkKeyExpiry(config AgentConfig, _ validationContext) []finding {
+	expiries, errs := keyExpiries(config, time.Now(), keyExpiryWarnDays)
+	var findings []finding
+	for _, err := range errs {
+		findings = append(findings, errorf("key-expiry", "%v", err).on("api_keys_meta"))
+	}
+	for _, e := range expiries {
+		switch e.Status {
+		case "expired":
+			findings = append(findings, errorf("key-expiry", "API key %s expired on %s", e.Key, e.ExpiresAt).on("api_keys."+e.Key))
+		case "expiring":
+			findings = append(findings, warnf("key-expiry", "API key %s expires on %s (%s)", e.Key, e.ExpiresAt, daysLeft(e.DaysLeft)).on("api_keys."+e.Key))
+		}
+	}
+	return findings
+}
+
+func daysLeft(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "in 1 day"
+	}
+	return fmt.Sprintf("in %d days", days)
+}
+
+// checkExpiryCommand lists keys with an expiry date. It exits 1 when a key
+// has expired or expires within --days, so it can run from cron.
+func checkExpiryCommand(args []string) {
+	fs := flag.NewFlagSet("keys check-expiry", flag.ExitOnError)
+	days := fs.Int("days", 30, "warn about keys expiring within this many days")
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	parseFlags(fs, args)
+
+	config := loadConfig()
+	expiries, errs := keyExpiries(config, time.Now(), *days)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+	}
+
+	failed := len(errs) > 0
+	for _, e := range expiries {
+		if e.Status != "ok" {
+			failed = true
+		}
+	}
+
+	if *asJSON {
+		if expiries == nil {
+			expiries = []keyExpiry{}
+		}
+		data, _ := json.MarshalIndent(expiries, "", "  ")
+		fmt.Println(string(data))
+	} else if len(expiries) == 0 && len(errs) == 0 {
+		fmt.Println("No key expiry dates recorded. Set one with 'acm set api_keys_meta.<key>.expires_at YYYY-MM-DD'")
+	} else {
+		for _, e := range expiries {
+			switch e.Status {
+			case "expired":
+				fmt.Printf("❌ %-10s expired on %s\n", e.Key, e.ExpiresAt)
+			case "expiring":
+				fmt.Printf("⚠️  %-10s expires on %s (%s)\n", e.Key, e.ExpiresAt, daysLeft(e.DaysLeft))
+			default:
+				fmt.Printf("✅ %-10s expires on %s (%s)\n", e.Key, e.ExpiresAt, daysLeft(e.DaysLeft))
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// exportLeaves are the config leaves exports write out: everything except
+// api_keys_meta and the file-structure keys extends, inherit_secrets and
+// include, which only acm itself reads
+func exportLeaves(config *AgentConfig) []configLeaf {
+	var leaves []configLeaf
+	for _, leaf := range configLeaves(config) {
+		switch leaf.Key {
+		case "api_keys_meta", "extends", "inherit_secrets", "include":
+		default:
+			leaves = append(leaves, leaf)
+		}
+	}
+	return leaves
+}
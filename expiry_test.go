@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeyExpiries(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	config := defaultConfig()
+	config.APIKeysMeta = map[string]APIKeyMeta{
+		"etherscan": {ExpiresAt: "2026-02-28"},
+		"basescan":  {ExpiresAt: "2026-03-10"},
+		"discord":   {ExpiresAt: "2026-06-01T00:00:00Z"},
+		"nonsense":  {ExpiresAt: "2026-06-01"},
+	}
+	expiries, errs := keyExpiries(config, now, 14)
+
+	want := []keyExpiry{
+		// a plain date lasts to the end of that day
+		{Key: "etherscan", ExpiresAt: "2026-02-28", DaysLeft: 0, Status: "expired"},
+		{Key: "basescan", ExpiresAt: "2026-03-10", DaysLeft: 9, Status: "expiring"},
+		{Key: "discord", ExpiresAt: "2026-06-01T00:00:00Z", DaysLeft: 91, Status: "ok"},
+	}
+	if len(expiries) != len(want) {
+		t.Fatalf("expiries = %+v", expiries)
+	}
+	for i := range want {
+		if expiries[i] != want[i] {
+			t.Errorf("expiry %d = %+v, want %+v", i, expiries[i], want[i])
+		}
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "api_keys_meta.nonsense: unknown key") {
+		t.Errorf("errs = %v", errs)
+	}
+
+	config.APIKeysMeta = map[string]APIKeyMeta{"etherscan": {ExpiresAt: "next week"}}
+	if _, errs := keyExpiries(config, now, 14); len(errs) != 1 || !strings.Contains(errs[0].Error(), "is not a date") {
+		t.Errorf("bad date errs = %v", errs)
+	}
+}
+
+func TestKeysCheckExpiry(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustRun("keys", "check-expiry"), "No key expiry dates recorded")
+
+	day := func(days int) string { return time.Now().UTC().AddDate(0, 0, days).Format("2006-01-02") }
+	e.mustRun("set", "api_keys_meta.discord.expires_at", day(90))
+	assertContains(t, e.mustRun("keys", "check-expiry"), "✅ discord")
+
+	e.mustRun("set", "api_keys_meta.basescan.expires_at", day(5))
+	out := e.mustFail("keys", "check-expiry")
+	assertContains(t, out, "⚠️  basescan", "expires on "+day(5))
+	assertContains(t, e.mustRun("keys", "check-expiry", "--days", "3"), "✅ basescan")
+
+	e.mustRun("set", "api_keys_meta.etherscan.expires_at", day(-2))
+	out = e.mustFail("keys", "check-expiry")
+	assertContains(t, out, "❌ etherscan  expired on "+day(-2))
+
+	out, _ = e.run("validate")
+	assertContains(t, out, "API key etherscan expired on "+day(-2), "API key basescan expires on "+day(5))
+	assertNotContains(t, out, "API key discord")
+}
+
+func TestKeyExpiryNotExported(t *testing.T) {
+	config := defaultConfig()
+	config.APIKeysMeta = map[string]APIKeyMeta{"etherscan": {ExpiresAt: "2099-01-01"}}
+	for _, format := range []string{"tools", "yaml", "consul-kv", "env", "docker-compose", "ansible-vars", "cloud-init"} {
+		files, err := exportFormats[format](config, exportOptions{KVPrefix: "agent", Secrets: "inline"})
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		for _, file := range files {
+			if strings.Contains(string(file.Data), "2099-01-01") || strings.Contains(string(file.Data), "api_keys_meta") {
+				t.Errorf("%s %s contains the key expiry:\n%s", format, file.Name, file.Data)
+			}
+		}
+	}
+}
@@ -0,0 +1,619 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const manifestFile = "manifest.json"
+
+// ExportManifest records every file generated by `acm export` so a
+// deployment step can confirm nothing was corrupted in transit
+type ExportManifest struct {
+	Version     string          `json:"version"`
+	GeneratedAt string          `json:"generated_at"`
+	Files       []ManifestEntry `json:"files"`
+}
+
+type ManifestEntry struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// exportFile is one generated file, relative to the export directory
+type exportFile struct {
+	Name string
+	Data []byte
+	// Volatile files differ on every run by design (ansible-vault.yml has a
+	// random salt), so --check and --selftest don't compare them
+	Volatile bool
+}
+
+type exportOptions struct {
+	NoSecrets         bool
+	KVPrefix          string
+	SecretsPrefix     string
+	Secrets           string
+	AuthRealm         string
+	Htpasswd          string
+	Host              string
+	VaultPasswordFile string
+	EnvPrefix         string
+}
+
+// exportFormats maps each --format value to the exporter that renders it
+var exportFormats = map[string]func(AgentConfig, exportOptions) ([]exportFile, error){
+	"tools":          exportTools,
+	"json":           exportTools,
+	"yaml":           exportToolsYAML,
+	"consul-kv":      exportConsulKV,
+	"env":            exportEnv,
+	"docker-compose": exportDockerCompose,
+	"nginx":          exportNginx,
+	"caddy":          exportCaddy,
+	"ansible-vars":   exportAnsibleVars,
+	"cloud-init":     exportCloudInit,
+}
+
+func exportConfig(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	verify := fs.Bool("verify", false, "verify exported files against the manifest")
+	check := fs.Bool("check", false, "exit 1 if the exported files differ from a fresh export, without writing")
+	selftest := fs.Bool("selftest", false, "export twice in memory and fail unless the bytes are identical")
+	format := fs.String("format", "tools", "export format: tools (or json), yaml, consul-kv, env, docker-compose, nginx, caddy, ansible-vars or cloud-init")
+	noSecrets := fs.Bool("no-secrets", false, "omit secrets (consul-kv)")
+	kvPrefix := fs.String("kv-prefix", "agent", "key prefix (consul-kv)")
+	secretsPrefix := fs.String("secrets-prefix", "", "separate key prefix for secrets (consul-kv)")
+	authRealm := fs.String("auth-realm", "", "protect the dashboard with basic auth under this realm (nginx)")
+	htpasswd := fs.String("htpasswd", "/etc/nginx/.htpasswd", "basic auth user file (nginx)")
+	host := fs.String("host", "", "public hostname for the dashboard (caddy)")
+	envPrefix := fs.String("prefix", "", "prefix every variable name, e.g. AGENT1_ (env)")
+	vaultPasswordFile := fs.String("vault-password-file", "", "encrypt secrets into ansible-vault.yml with this password (ansible-vars)")
+	asJSON := fs.Bool("json", false, "print a JSON summary of the written files")
+	requireSecrets := fs.Bool("require-secrets", false, "fail if a secret the tools need resolves empty")
+	allProfiles := fs.Bool("all-profiles", false, "export every profile into its own export directory")
+	secrets := fs.String("secrets", "ref", "emit secrets as ${VAR} references (ref) or values (inline) (docker-compose, cloud-init)")
+	parseFlags(fs, args)
+
+	// Export individual tool configs
+	exportDir := getExportDir()
+
+	if *verify {
+		if *allProfiles {
+			fmt.Println("❌ --verify can't be combined with --all-profiles")
+			os.Exit(1)
+		}
+		verifyExports(exportDir)
+		return
+	}
+
+	exporter, ok := exportFormats[*format]
+	if !ok {
+		fmt.Printf("❌ Unknown export format: %s\n", *format)
+		os.Exit(1)
+	}
+	job := exportJob{
+		Exporter:       exporter,
+		RequireSecrets: *requireSecrets,
+		Options: exportOptions{
+			NoSecrets:         *noSecrets,
+			KVPrefix:          *kvPrefix,
+			SecretsPrefix:     *secretsPrefix,
+			Secrets:           *secrets,
+			AuthRealm:         *authRealm,
+			Htpasswd:          *htpasswd,
+			Host:              *host,
+			VaultPasswordFile: *vaultPasswordFile,
+			EnvPrefix:         *envPrefix,
+		},
+	}
+
+	if (*check || *selftest) && *allProfiles {
+		fmt.Println("❌ --check and --selftest can't be combined with --all-profiles")
+		os.Exit(1)
+	}
+	if *allProfiles {
+		exportAllProfiles(job, *format, *asJSON)
+		return
+	}
+	if *check {
+		checkExports(job, loadConfig(), exportDir)
+		return
+	}
+	if *selftest {
+		selftestExport(job, loadConfig())
+		return
+	}
+
+	files, err := job.run(loadConfig(), exportDir)
+	if err != nil {
+		printExportFailure("", err)
+		os.Exit(exitCode(err))
+	}
+
+	if *asJSON {
+		printExportSummary(exportSummary{Files: summarizeExport(exportDir, files)})
+		return
+	}
+
+	fmt.Printf("✅ Exported %s to %s/\n", exportLabel(*format), exportDir)
+	for _, file := range files {
+		fmt.Printf("   - %s\n", file.Name)
+	}
+}
+
+func exportLabel(format string) string {
+	if format != "tools" {
+		return format + " format"
+	}
+	return "tool configs"
+}
+
+// exportJob is one export run: a format with its options, applied to a config
+type exportJob struct {
+	Exporter       func(AgentConfig, exportOptions) ([]exportFile, error)
+	Options        exportOptions
+	RequireSecrets bool
+}
+
+// exportFailure is an export error with an optional follow-up hint
+type exportFailure struct {
+	Reason string
+	Hint   string
+}
+
+func (e *exportFailure) Error() string {
+	return e.Reason
+}
+
+// printExportFailure prints err, prefixed by the profile name with
+// --all-profiles
+func printExportFailure(profile string, err error) {
+	if profile != "" {
+		fmt.Printf("❌ %s: %v\n", profile, err)
+	} else {
+		fmt.Printf("❌ %v\n", err)
+	}
+	var failure *exportFailure
+	if errors.As(err, &failure) && failure.Hint != "" {
+		fmt.Printf("   %s\n", failure.Hint)
+	}
+}
+
+// run resolves the config's references and secrets, renders the export and
+// writes it with an updated manifest into exportDir. It returns the files
+// written, manifest included.
+func (job exportJob) run(config AgentConfig, exportDir string) ([]exportFile, error) {
+	files, err := job.render(config)
+	if err != nil {
+		return nil, err
+	}
+	data, err := updatedManifest(exportDir, files)
+	if err != nil {
+		return nil, &exportFailure{Reason: fmt.Sprintf("Export failed: %v", err)}
+	}
+
+	files = append(files, exportFile{Name: manifestFile, Data: data})
+	if err := writeExportBatch(exportDir, files); err != nil {
+		hint := "No files were changed"
+		if errors.Is(err, errPartialExport) {
+			hint = "Some files may be from this export; run it again once the problem is fixed"
+		}
+		return nil, &exportFailure{Reason: fmt.Sprintf("Export failed: %v", err), Hint: hint}
+	}
+	return files, nil
+}
+
+// render resolves the config's references and secrets and renders the
+// export in memory
+func (job exportJob) render(config AgentConfig) ([]exportFile, error) {
+	// Resolve secrets through the environment, not just the on-disk values
+	if err := expandConfigEnvRefs(&config); err != nil {
+		return nil, err
+	}
+	sources := resolveSecrets(&config)
+	if job.RequireSecrets {
+		var missing []string
+		for _, key := range requiredSecrets() {
+			if sources[key].Provider == "unset" {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, &exportFailure{
+				Reason: "Required secrets are not set: " + strings.Join(missing, ", "),
+				Hint:   "Set them with 'acm set' or in the environment (see 'acm env-map')",
+			}
+		}
+	}
+
+	files, err := job.Exporter(config, job.Options)
+	if err != nil {
+		return nil, &exportFailure{Reason: fmt.Sprintf("Export failed: %v", err)}
+	}
+	return files, nil
+}
+
+// updatedManifest records files in the manifest of exportDir. The
+// timestamp only moves when a file changed, so exporting an unchanged config
+// twice leaves every byte as it was.
+func updatedManifest(exportDir string, files []exportFile) ([]byte, error) {
+	manifest := readManifest(exportDir)
+	changed := manifest.Version != version
+	for _, file := range files {
+		entry := manifestEntryFor(file.Name, file.Data)
+		if old, ok := manifest.entry(file.Name); !ok || old != entry {
+			changed = true
+		}
+		manifest.upsert(entry)
+	}
+	manifest.Version = version
+	if changed || manifest.GeneratedAt == "" {
+		manifest.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	return json.MarshalIndent(manifest, "", "  ")
+}
+
+// exportAllProfiles runs job for every profile into its own export
+// directory. A failing profile is reported and the rest still export; the
+// exit status is 1 if any failed.
+func exportAllProfiles(job exportJob, format string, asJSON bool) {
+	if explicitConfigPath() != "" {
+		fmt.Println("❌ --all-profiles can't be combined with --config or ACM_CONFIG")
+		os.Exit(1)
+	}
+	names := listProfiles()
+	if len(names) == 0 {
+		fmt.Println("No profiles found. Run 'acm init' to create one.")
+		os.Exit(1)
+	}
+
+	summary := exportSummary{Files: []exportSummaryFile{}}
+	failed := 0
+	for _, name := range names {
+		exportDir := profileExportDir(name)
+		config, err := readConfig(profileConfigPath(name))
+		var files []exportFile
+		if err == nil {
+			files, err = job.run(config, exportDir)
+		}
+		if err != nil {
+			failed++
+			if asJSON {
+				if summary.Errors == nil {
+					summary.Errors = map[string]string{}
+				}
+				summary.Errors[name] = err.Error()
+			} else {
+				printExportFailure(name, err)
+			}
+			continue
+		}
+		summary.Files = append(summary.Files, summarizeExport(exportDir, files)...)
+		if !asJSON {
+			fmt.Printf("✅ %s: exported %s to %s/ (%d files)\n", name, exportLabel(format), exportDir, len(files))
+		}
+	}
+
+	if asJSON {
+		printExportSummary(summary)
+	} else if failed > 0 {
+		fmt.Println()
+		fmt.Printf("❌ %d of %d profile(s) failed to export\n", failed, len(names))
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// exportSummary is the machine-readable output of `acm export --json`
+type exportSummary struct {
+	Files  []exportSummaryFile `json:"files"`
+	Errors map[string]string   `json:"errors,omitempty"` // by profile, with --all-profiles
+}
+
+type exportSummaryFile struct {
+	Path   string `json:"path"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+func summarizeExport(exportDir string, files []exportFile) []exportSummaryFile {
+	entries := []exportSummaryFile{}
+	for _, file := range files {
+		entry := manifestEntryFor(file.Name, file.Data)
+		entries = append(entries, exportSummaryFile{
+			Path:   filepath.Join(exportDir, file.Name),
+			Bytes:  entry.Size,
+			SHA256: entry.SHA256,
+		})
+	}
+	return entries
+}
+
+func printExportSummary(summary exportSummary) {
+	data, _ := json.MarshalIndent(summary, "", "  ")
+	fmt.Println(string(data))
+}
+
+// writeExportBatch writes every file into a staging directory inside
+// exportDir and only moves them into place once all of them were written, so
+// a full disk or read-only directory fails the export before anything
+// changes. The moves are atomic one file at a time, not as a batch: readers
+// may briefly see some new files next to old ones. If a move fails, the files
+// already moved are put back as they were, and the error says if that failed
+// too.
+func writeExportBatch(exportDir string, files []exportFile) error {
+	if err := os.MkdirAll(exportDir, 0755); err != nil {
+		return err
+	}
+	staging, err := os.MkdirTemp(exportDir, ".export-*")
+	if err != nil {
+		return fmt.Errorf("export directory %s is not writable: %v", exportDir, err)
+	}
+	defer os.RemoveAll(staging)
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(staging, file.Name), file.Data, 0600); err != nil {
+			return fmt.Errorf("writing %s: %v", file.Name, err)
+		}
+	}
+	// Keep what each move replaces, to roll back to
+	previous := make([][]byte, len(files))
+	for i, file := range files {
+		data, err := os.ReadFile(filepath.Join(exportDir, file.Name))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("reading %s: %v", file.Name, err)
+		}
+		previous[i] = data
+	}
+	for i, file := range files {
+		if err := os.Rename(filepath.Join(staging, file.Name), filepath.Join(exportDir, file.Name)); err != nil {
+			err = fmt.Errorf("moving %s into place: %v", file.Name, err)
+			if rollbackErr := rollbackExportBatch(exportDir, files[:i], previous); rollbackErr != nil {
+				err = fmt.Errorf("%v; %w: %v", err, errPartialExport, rollbackErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// errPartialExport means a failed export couldn't be rolled back
+var errPartialExport = errors.New("restoring the previous export failed")
+
+// rollbackExportBatch restores moved files to their previous contents, or
+// removes them where there was no file before
+func rollbackExportBatch(exportDir string, moved []exportFile, previous [][]byte) error {
+	var failed []string
+	for i, file := range moved {
+		path := filepath.Join(exportDir, file.Name)
+		var err error
+		if previous[i] == nil {
+			err = os.Remove(path)
+		} else {
+			err = writeFileAtomic(path, previous[i], 0600)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", file.Name, err))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// exportTools renders the per-tool JSON configs
+func exportTools(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	// Export for wallet-monitor
+	walletConfig := map[string]interface{}{
+		"address":                config.Wallet.Address,
+		"etherscan_key":          config.APIKeys.Etherscan,
+		"basescan_key":           config.APIKeys.Basescan,
+		"check_interval":         config.Monitoring.CheckInterval, // minutes, kept for older readers
+		"check_interval_seconds": config.Monitoring.CheckInterval * 60,
+		"daily_limit":            config.Wallet.DailyLimit,
+		"daily_limit_wei":        ethToWei(config.Wallet.DailyLimit),
+		"alert_threshold":        config.Wallet.AlertThreshold,
+		"alert_threshold_wei":    ethToWei(config.Wallet.AlertThreshold),
+		"webhook_url":            config.Monitoring.WebhookURL,
+		"webhook_secret":         config.Monitoring.WebhookSecret,
+		"webhook_template":       config.Monitoring.WebhookTemplate,
+		"rate_limit":             config.Monitoring.RateLimit,
+		"observability":          config.Observability,
+	}
+
+	// Export for reputation-scanner
+	scannerConfig := map[string]interface{}{
+		"address":       config.Wallet.Address,
+		"etherscan_key": config.APIKeys.Etherscan,
+		"basescan_key":  config.APIKeys.Basescan,
+		"rate_limit":    config.Monitoring.RateLimit,
+		"observability": config.Observability,
+	}
+
+	// Export for security-dashboard
+	dashboardConfig := map[string]interface{}{
+		"port":          config.Monitoring.DashboardPort,
+		"observability": config.Observability,
+	}
+
+	var files []exportFile
+	for _, tool := range []struct {
+		name   string
+		config map[string]interface{}
+	}{
+		{"wallet-monitor.json", walletConfig},
+		{"reputation-scanner.json", scannerConfig},
+		{"security-dashboard.json", dashboardConfig},
+	} {
+		data, err := json.MarshalIndent(tool.config, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, exportFile{Name: tool.name, Data: data})
+	}
+	return files, nil
+}
+
+// exportToolsYAML writes the tool configs as YAML, under .yaml names
+func exportToolsYAML(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	files, err := exportTools(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i, file := range files {
+		if files[i].Data, err = jsonToYAML(file.Data); err != nil {
+			return nil, err
+		}
+		files[i].Name = strings.TrimSuffix(file.Name, ".json") + ".yaml"
+	}
+	return files, nil
+}
+
+// consulKVEntry matches the format read by `consul kv import`
+type consulKVEntry struct {
+	Key   string `json:"key"`
+	Flags int    `json:"flags"`
+	Value string `json:"value"`
+}
+
+// exportConsulKV flattens the whole config into <prefix>/<section>/<field>
+// entries, JSON-encoding lists. Secrets go under --secrets-prefix when set,
+// or are dropped entirely with --no-secrets.
+func exportConsulKV(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	entries := []consulKVEntry{}
+	for _, leaf := range exportLeaves(&config) {
+		prefix := opts.KVPrefix
+		if isSecretKey(leaf.Key) {
+			if opts.NoSecrets {
+				continue
+			}
+			if opts.SecretsPrefix != "" {
+				prefix = opts.SecretsPrefix
+			}
+		}
+
+		key := strings.ReplaceAll(leaf.Key, ".", "/")
+		if prefix != "" {
+			key = strings.TrimSuffix(prefix, "/") + "/" + key
+		}
+		entries = append(entries, consulKVEntry{
+			Key:   key,
+			Value: base64.StdEncoding.EncodeToString([]byte(leafString(leaf.Value))),
+		})
+	}
+	for _, derived := range derivedExportKeys {
+		key := strings.ReplaceAll(derived.Key, ".", "/")
+		if opts.KVPrefix != "" {
+			key = strings.TrimSuffix(opts.KVPrefix, "/") + "/" + key
+		}
+		entries = append(entries, consulKVEntry{
+			Key:   key,
+			Value: base64.StdEncoding.EncodeToString([]byte(derived.Value(config))),
+		})
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []exportFile{{Name: "consul-kv.json", Data: data}}, nil
+}
+
+// readManifest loads the existing manifest so exports of different formats
+// accumulate in one file
+func readManifest(exportDir string) ExportManifest {
+	var manifest ExportManifest
+	if data, err := os.ReadFile(filepath.Join(exportDir, manifestFile)); err == nil {
+		json.Unmarshal(data, &manifest)
+	}
+	return manifest
+}
+
+func (m *ExportManifest) entry(name string) (ManifestEntry, bool) {
+	for _, entry := range m.Files {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+	return ManifestEntry{}, false
+}
+
+func (m *ExportManifest) upsert(entry ManifestEntry) {
+	for i := range m.Files {
+		if m.Files[i].Name == entry.Name {
+			m.Files[i] = entry
+			return
+		}
+	}
+	m.Files = append(m.Files, entry)
+}
+
+func manifestEntryFor(name string, data []byte) ManifestEntry {
+	sum := sha256.Sum256(data)
+	return ManifestEntry{
+		Name:   name,
+		Size:   int64(len(data)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+}
+
+// verifyExports recomputes the size and checksum of every file listed in the
+// manifest and exits non-zero on any mismatch
+func verifyExports(exportDir string) {
+	data, err := os.ReadFile(filepath.Join(exportDir, manifestFile))
+	if err != nil {
+		fmt.Printf("❌ Manifest not found in %s\n", exportDir)
+		fmt.Println("   Run 'acm export' to generate it")
+		os.Exit(1)
+	}
+
+	var manifest ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		fmt.Printf("❌ Invalid manifest: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("🔍 Verifying exports in %s/\n", exportDir)
+	fmt.Println()
+
+	failures := 0
+	for _, entry := range manifest.Files {
+		content, err := os.ReadFile(filepath.Join(exportDir, entry.Name))
+		if err != nil {
+			fmt.Printf("❌ %s: missing\n", entry.Name)
+			failures++
+			continue
+		}
+		actual := manifestEntryFor(entry.Name, content)
+		switch {
+		case actual.Size != entry.Size:
+			fmt.Printf("❌ %s: size %d, manifest says %d\n", entry.Name, actual.Size, entry.Size)
+			failures++
+		case actual.SHA256 != entry.SHA256:
+			fmt.Printf("❌ %s: checksum mismatch\n", entry.Name)
+			failures++
+		default:
+			fmt.Printf("✅ %s\n", entry.Name)
+		}
+	}
+
+	fmt.Println()
+	if failures > 0 {
+		fmt.Printf("Found %d corrupted file(s)\n", failures)
+		os.Exit(1)
+	}
+	fmt.Printf("All %d file(s) match the manifest\n", len(manifest.Files))
+}
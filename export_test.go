@@ -0,0 +1,351 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func (e *testEnv) exportDir() string {
+	return filepath.Join(e.home, ".config", "agent", "exports")
+}
+
+func (e *testEnv) manifest() ExportManifest {
+	e.t.Helper()
+	var manifest ExportManifest
+	if err := json.Unmarshal([]byte(e.read(filepath.Join(e.exportDir(), manifestFile))), &manifest); err != nil {
+		e.t.Fatal(err)
+	}
+	return manifest
+}
+
+func TestExportManifestMatchesFiles(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export")
+
+	manifest := e.manifest()
+	if manifest.Version != version || manifest.GeneratedAt == "" {
+		t.Errorf("manifest version %q generated %q", manifest.Version, manifest.GeneratedAt)
+	}
+	names := map[string]bool{}
+	for _, entry := range manifest.Files {
+		names[entry.Name] = true
+		data, err := os.ReadFile(filepath.Join(e.exportDir(), entry.Name))
+		if err != nil {
+			t.Fatalf("%s is in the manifest but: %v", entry.Name, err)
+		}
+		sum := sha256.Sum256(data)
+		if entry.Size != int64(len(data)) || entry.SHA256 != hex.EncodeToString(sum[:]) {
+			t.Errorf("%s: manifest says %d bytes %s", entry.Name, entry.Size, entry.SHA256)
+		}
+	}
+	for _, name := range []string{"wallet-monitor.json", "reputation-scanner.json", "security-dashboard.json"} {
+		if !names[name] {
+			t.Errorf("manifest doesn't list %s", name)
+		}
+	}
+
+	assertContains(t, e.mustRun("export", "--verify"), "match the manifest")
+}
+
+func TestExportVerifyDetectsTampering(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export")
+	dir := e.exportDir()
+
+	path := filepath.Join(dir, "wallet-monitor.json")
+	data := []byte(e.read(path))
+	data[len(data)/2] ^= 1
+	e.write(path, string(data))
+	assertContains(t, e.mustFail("export", "--verify"), "wallet-monitor.json: checksum mismatch")
+
+	e.write(path, "{}")
+	assertContains(t, e.mustFail("export", "--verify"), "wallet-monitor.json: size 2")
+
+	os.Remove(filepath.Join(dir, "security-dashboard.json"))
+	assertContains(t, e.mustFail("export", "--verify"), "security-dashboard.json: missing", "Found 2 corrupted file(s)")
+}
+
+func TestUpdatedManifestKeepsOtherFormats(t *testing.T) {
+	dir := t.TempDir()
+	manifest := ExportManifest{Version: version, GeneratedAt: "2026-01-01T00:00:00Z"}
+	manifest.upsert(manifestEntryFor("agent.env", []byte("A=1\n")))
+	data, _ := json.Marshal(manifest)
+	os.WriteFile(filepath.Join(dir, manifestFile), data, 0600)
+
+	data, err := updatedManifest(dir, []exportFile{{Name: "agent.env", Data: []byte("A=1\n")}, {Name: "x.json", Data: []byte("{}")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got ExportManifest
+	json.Unmarshal(data, &got)
+	if len(got.Files) != 2 {
+		t.Fatalf("files = %+v, want agent.env and x.json", got.Files)
+	}
+	if entry, _ := got.entry("x.json"); entry != manifestEntryFor("x.json", []byte("{}")) {
+		t.Errorf("x.json entry = %+v", entry)
+	}
+}
+
+// consulKV runs the consul-kv exporter and decodes its entries
+func consulKV(t *testing.T, config AgentConfig, opts exportOptions) map[string]string {
+	t.Helper()
+	files, err := exportConsulKV(config, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []consulKVEntry
+	if err := json.Unmarshal(files[0].Data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	kv := map[string]string{}
+	for _, entry := range entries {
+		value, err := base64.StdEncoding.DecodeString(entry.Value)
+		if err != nil {
+			t.Fatalf("%s: %v", entry.Key, err)
+		}
+		kv[entry.Key] = string(value)
+	}
+	return kv
+}
+
+func TestExportConsulKVFlattens(t *testing.T) {
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-value-123"
+	kv := consulKV(t, config, exportOptions{KVPrefix: "agent"})
+
+	for key, want := range map[string]string{
+		"agent/agent/name":                  "Arithmos",
+		"agent/wallet/daily_limit":          "0.5",
+		"agent/wallet/networks":             `["ethereum","base"]`,
+		"agent/monitoring/rate_limit/rps":   "5",
+		"agent/monitoring/rate_limit/burst": "10",
+		"agent/security/firewall_enabled":   "true",
+		"agent/api_keys/etherscan":          "etherscan-secret-value-123",
+		"agent/wallet/daily_limit_wei":      "500000000000000000",
+	} {
+		if kv[key] != want {
+			t.Errorf("%s = %q, want %q", key, kv[key], want)
+		}
+	}
+	for key := range kv {
+		if !strings.HasPrefix(key, "agent/") || strings.Contains(key, ".") {
+			t.Errorf("key %s isn't a slash path under the prefix", key)
+		}
+	}
+}
+
+func TestExportConsulKVSecrets(t *testing.T) {
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-value-123"
+
+	kv := consulKV(t, config, exportOptions{KVPrefix: "agent", SecretsPrefix: "secret/agent"})
+	if kv["secret/agent/api_keys/etherscan"] != "etherscan-secret-value-123" {
+		t.Errorf("secret not under --secrets-prefix: %v", kv)
+	}
+	if _, ok := kv["agent/api_keys/etherscan"]; ok {
+		t.Error("secret also under the main prefix")
+	}
+
+	kv = consulKV(t, config, exportOptions{KVPrefix: "agent", NoSecrets: true})
+	for key := range kv {
+		if strings.Contains(key, "api_keys/") {
+			t.Errorf("--no-secrets kept %s", key)
+		}
+	}
+}
+
+// toolConfigs runs the tools exporter and decodes each file
+func toolConfigs(t *testing.T, config AgentConfig) map[string]map[string]interface{} {
+	t.Helper()
+	files, err := exportTools(config, exportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools := map[string]map[string]interface{}{}
+	for _, file := range files {
+		var tool map[string]interface{}
+		if err := json.Unmarshal(file.Data, &tool); err != nil {
+			t.Fatalf("%s: %v", file.Name, err)
+		}
+		tools[file.Name] = tool
+	}
+	return tools
+}
+
+func TestExportRateLimit(t *testing.T) {
+	config := defaultConfig()
+	config.Monitoring.RateLimit = RateLimitConfig{RPS: 2.5, Burst: 4, Backoff: "linear"}
+	tools := toolConfigs(t, config)
+	for _, name := range []string{"wallet-monitor.json", "reputation-scanner.json"} {
+		rateLimit, _ := tools[name]["rate_limit"].(map[string]interface{})
+		if rateLimit["rps"] != 2.5 || rateLimit["burst"] != 4.0 || rateLimit["backoff"] != "linear" {
+			t.Errorf("%s rate_limit = %v", name, tools[name]["rate_limit"])
+		}
+	}
+}
+
+func TestExportReadOnlyDirFails(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export")
+	before := e.read(filepath.Join(e.exportDir(), "wallet-monitor.json"))
+	os.Chmod(e.exportDir(), 0500)
+	defer os.Chmod(e.exportDir(), 0700)
+
+	e.mustRun("set", "wallet.daily_limit", "0.9")
+	assertContains(t, e.mustFail("export"), "is not writable")
+	if e.read(filepath.Join(e.exportDir(), "wallet-monitor.json")) != before {
+		t.Error("a failed export changed wallet-monitor.json")
+	}
+}
+
+func TestExportDirIsAFile(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.write(e.exportDir(), "not a directory")
+	out := e.mustFail("export")
+	assertNotContains(t, out, "✅")
+}
+
+func TestExportFailsWithoutPartialBatch(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export")
+	dir := e.exportDir()
+	before := e.read(filepath.Join(dir, "wallet-monitor.json"))
+
+	// a directory where an export file goes can't be replaced
+	os.Remove(filepath.Join(dir, "security-dashboard.json"))
+	e.write(filepath.Join(dir, "security-dashboard.json", "keep"), "x")
+	e.mustRun("set", "wallet.daily_limit", "0.9")
+	out := e.mustFail("export")
+	assertContains(t, out, "security-dashboard.json")
+	assertNotContains(t, out, "✅")
+	if e.read(filepath.Join(dir, "wallet-monitor.json")) != before {
+		t.Error("files were replaced by a failed export")
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".export-") {
+			t.Errorf("staging directory %s left behind", entry.Name())
+		}
+	}
+}
+
+func TestRollbackExportBatch(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "old.json"), []byte("new contents"), 0600)
+	os.WriteFile(filepath.Join(dir, "added.json"), []byte("{}"), 0600)
+
+	moved := []exportFile{{Name: "old.json"}, {Name: "added.json"}}
+	if err := rollbackExportBatch(dir, moved, [][]byte{[]byte("old contents"), nil}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "old.json")); string(data) != "old contents" {
+		t.Errorf("old.json = %q, want its previous contents", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "added.json")); !os.IsNotExist(err) {
+		t.Errorf("added.json wasn't removed: %v", err)
+	}
+}
+
+func TestExportObservability(t *testing.T) {
+	config := defaultConfig()
+	config.Observability = ObservabilityConfig{OTLPEndpoint: "https://otel.example.com:4318", SampleRate: 0.25, LogLevel: "warn"}
+	tools := toolConfigs(t, config)
+	if len(tools) != 3 {
+		t.Fatalf("exported %d tool configs, want 3", len(tools))
+	}
+	for name, tool := range tools {
+		obs, _ := tool["observability"].(map[string]interface{})
+		if obs["otlp_endpoint"] != "https://otel.example.com:4318" || obs["sample_rate"] != 0.25 || obs["log_level"] != "warn" {
+			t.Errorf("%s observability = %v", name, tool["observability"])
+		}
+	}
+}
+
+func TestExportJSONSummary(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	out := e.mustRun("export", "--json")
+
+	var summary exportSummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	assertNotContains(t, out, "✅")
+	listed := map[string]bool{}
+	for _, file := range summary.Files {
+		listed[filepath.Base(file.Path)] = true
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			t.Fatalf("%s: %v", file.Path, err)
+		}
+		sum := sha256.Sum256(data)
+		if file.Bytes != int64(len(data)) || file.SHA256 != hex.EncodeToString(sum[:]) {
+			t.Errorf("%s: summary says %d bytes %s, file has %d", file.Path, file.Bytes, file.SHA256, len(data))
+		}
+	}
+	entries, _ := os.ReadDir(e.exportDir())
+	for _, entry := range entries {
+		if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && !listed[entry.Name()] {
+			t.Errorf("%s was written but isn't in the summary", entry.Name())
+		}
+	}
+	if len(summary.Files) < 3 {
+		t.Errorf("summary lists %d files", len(summary.Files))
+	}
+}
+
+func TestExportAllProfiles(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "testnet")
+	e.mustRun("--profile", "testnet", "init")
+	e.mustRun("--profile", "testnet", "set", "monitoring.dashboard_port", "9090")
+
+	out := e.mustRun("export", "--all-profiles")
+	assertContains(t, out, "default: exported", "testnet: exported")
+	assertContains(t, e.read(filepath.Join(e.exportDir(), "security-dashboard.json")), "8080")
+	assertContains(t, e.read(filepath.Join(e.exportDir(), "testnet", "security-dashboard.json")), "9090")
+
+	assertContains(t, e.mustFail("--config", e.configPath(), "export", "--all-profiles"), "can't be combined with --config")
+}
+
+func TestExportAllProfilesFailureContinues(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "broken")
+	e.mustRun("profile", "create", "testnet")
+	e.mustRun("--profile", "testnet", "init")
+	e.write(e.profilePath("broken"), `{"wallet": `)
+
+	out := e.mustFail("export", "--all-profiles")
+	assertContains(t, out, "default: exported", "testnet: exported", "1 of 3 profile(s) failed to export")
+	if _, err := os.Stat(filepath.Join(e.exportDir(), "testnet", "wallet-monitor.json")); err != nil {
+		t.Errorf("testnet wasn't exported after broken failed: %v", err)
+	}
+
+	out, code := e.run("export", "--all-profiles", "--json")
+	if code != 1 {
+		t.Errorf("--json exit code %d, want 1", code)
+	}
+	var summary exportSummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, out)
+	}
+	if _, ok := summary.Errors["broken"]; !ok || len(summary.Errors) != 1 || len(summary.Files) == 0 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
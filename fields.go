@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// fieldMeta describes one config leaf: its dotted key (matching the JSON
+// tags), display label and how its value is rendered
+type fieldMeta struct {
+	Key    string
+	Label  string
+	Secret bool
+	// Format renders the value for display; nil uses the default rendering
+	Format func(v reflect.Value) string
+	// Validate checks a new value before `acm set` stores it, and the
+	// current value during `acm validate`
+	Validate fieldValidator
+}
+
+// configSections lists the top-level sections in display order
+var configSections = []struct {
+	Key   string
+	Title string
+}{
+	{"agent", "AGENT"},
+	{"wallet", "WALLET"},
+	{"security", "SECURITY"},
+	{"api_keys", "API KEYS"},
+	{"monitoring", "MONITORING"},
+	{"observability", "OBSERVABILITY"},
+	{"validation", "VALIDATION"},
+}
+
+// configFields is the single source of truth for field order, labels and
+// masking. Add an entry here when adding a field to AgentConfig.
+var configFields = []fieldMeta{
+	{Key: "agent.name", Label: "Name"},
+	{Key: "agent.id", Label: "ID"},
+	{Key: "agent.erc8004_id", Label: "ERC-8004", Format: func(v reflect.Value) string { return fmt.Sprintf("#%d", v.Int()) }},
+	{Key: "agent.website", Label: "Website", Validate: validateHTTPURL},
+	{Key: "agent.github", Label: "GitHub", Validate: validateGitHub},
+
+	{Key: "wallet.address", Label: "Address", Validate: validateAddress},
+	{Key: "wallet.networks", Label: "Networks", Validate: validateNetworkList},
+	{Key: "wallet.daily_limit", Label: "Daily Limit", Format: formatETH},
+	{Key: "wallet.alert_threshold", Label: "Alert Threshold", Format: formatETH},
+
+	{Key: "security.firewall_enabled", Label: "Firewall"},
+	{Key: "security.honeypot_enabled", Label: "Honeypot"},
+	{Key: "security.prompt_guard_enabled", Label: "Prompt Guard"},
+	{Key: "security.simulator_enabled", Label: "Simulator"},
+	{Key: "security.whitelisted_addresses", Label: "Whitelist", Format: formatAddressCount, Validate: validateAddressList},
+	{Key: "security.blacklisted_addresses", Label: "Blacklist", Format: formatAddressCount, Validate: validateAddressList},
+
+	{Key: "api_keys.etherscan", Label: "Etherscan", Secret: true},
+	{Key: "api_keys.basescan", Label: "Basescan", Secret: true},
+	{Key: "api_keys.openai", Label: "OpenAI", Secret: true},
+	{Key: "api_keys.anthropic", Label: "Anthropic", Secret: true},
+	{Key: "api_keys.discord", Label: "Discord", Secret: true},
+
+	{Key: "monitoring.dashboard_enabled", Label: "Dashboard"},
+	{Key: "monitoring.dashboard_port", Label: "Port", Validate: validatePort},
+	{Key: "monitoring.check_interval_minutes", Label: "Check Interval", Format: func(v reflect.Value) string { return fmt.Sprintf("%d minutes", v.Int()) }, Validate: validateMinutes},
+	{Key: "monitoring.webhook_url", Label: "Webhook", Format: func(v reflect.Value) string { return webhookStatus(v.String()) }, Validate: validateHTTPURL},
+	{Key: "monitoring.webhook_secret", Label: "Webhook Secret", Secret: true},
+	{Key: "monitoring.webhook_template", Label: "Webhook Payload", Format: func(v reflect.Value) string { return webhookTemplateStatus(v.String()) }},
+	{Key: "monitoring.rate_limit.rps", Label: "Rate Limit", Format: func(v reflect.Value) string { return fmt.Sprintf("%g req/s", v.Float()) }},
+	{Key: "monitoring.rate_limit.burst", Label: "Burst"},
+	{Key: "monitoring.rate_limit.backoff", Label: "Backoff", Validate: validateOneOf(backoffStrategies)},
+
+	{Key: "observability.otlp_endpoint", Label: "OTLP", Validate: validateHTTPURL},
+	{Key: "observability.sample_rate", Label: "Sample Rate", Validate: validateRange(0, 1)},
+	{Key: "observability.log_level", Label: "Log Level", Validate: validateOneOf(logLevels)},
+
+	{Key: "validation.hooks", Label: "Hooks", Format: func(v reflect.Value) string { return fmt.Sprintf("%d configured", v.Len()) }},
+}
+
+func formatETH(v reflect.Value) string {
+	return formatETHAmount(v.Float()) + " ETH"
+}
+
+func formatAddressCount(v reflect.Value) string {
+	return fmt.Sprintf("%d addresses", v.Len())
+}
+
+// displayValue renders a field for `show`, masking secrets
+func (f fieldMeta) displayValue(v reflect.Value) string {
+	switch {
+	case f.Secret:
+		return keyStatus(v.String())
+	case f.Format != nil:
+		return f.Format(v)
+	case v.Kind() == reflect.Bool:
+		return boolStatus(v.Bool())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// lookupField walks config along a dotted key using the JSON tag names
+func lookupField(config *AgentConfig, key string) (reflect.Value, error) {
+	v := reflect.ValueOf(config).Elem()
+	for _, part := range strings.Split(key, ".") {
+		// Map entries such as networks.<name> are readable but, being
+		// copies, not settable; assignField writes them
+		if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+			v = v.MapIndex(reflect.ValueOf(part))
+			if !v.IsValid() {
+				return reflect.Value{}, fmt.Errorf("unknown key: %s", key)
+			}
+			continue
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown key: %s", key)
+		}
+		field, ok := fieldByTag(v, part)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("unknown key: %s", key)
+		}
+		v = field
+	}
+	return v, nil
+}
+
+// errUnknownKey means a key doesn't name a leaf assignField can set
+var errUnknownKey = errors.New("unknown key")
+
+// assignField sets the leaf a dotted key names, walking structs by their
+// JSON tags and maps by entry name, such as agent.website or
+// networks.arbitrum.rpc_url. A missing map entry is created (autovivified)
+// unless create is false. The value is parsed for the leaf's type; nothing
+// is changed when it doesn't parse.
+func assignField(config *AgentConfig, key, value string, create bool) error {
+	return setPath(reflect.ValueOf(config).Elem(), strings.Split(key, "."), key, value, create)
+}
+
+func setPath(v reflect.Value, parts []string, key, value string, create bool) error {
+	if len(parts) == 0 {
+		return setLeaf(v, key, value)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		field, ok := fieldByTag(v, parts[0])
+		if !ok {
+			return errUnknownKey
+		}
+		return setPath(field, parts[1:], key, value, create)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || len(parts) < 2 {
+			return errUnknownKey
+		}
+		name := reflect.ValueOf(parts[0])
+		entry := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(name); existing.IsValid() {
+			entry.Set(existing)
+		} else if !create {
+			prefix := strings.TrimSuffix(key, "."+strings.Join(parts[1:], "."))
+			return fmt.Errorf("%s does not exist (--no-create)", prefix)
+		}
+		if err := setPath(entry, parts[1:], key, value, create); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		v.SetMapIndex(name, entry)
+		return nil
+	}
+	return errUnknownKey
+}
+
+// setLeaf parses value into a scalar field, or a list given as
+// comma-separated items
+func setLeaf(v reflect.Value, key, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Float64:
+		parse := parseFloatInto
+		if isETHAmountKey(key) {
+			parse = parseETHInto
+		}
+		var f float64
+		if err := parse(&f, key, value); err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Int:
+		var n int
+		if key == "monitoring.check_interval_minutes" {
+			minutes, err := parseMinutes(value)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %v", key, err)
+			}
+			n = minutes
+		} else if err := parseIntInto(&n, key, value); err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		var b bool
+		if err := parseBoolInto(&b, key, value); err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return errUnknownKey
+		}
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items).Convert(v.Type()))
+	default:
+		return errUnknownKey
+	}
+	return nil
+}
+
+// fieldByTag finds the struct field whose JSON tag name is name
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if jsonName(t.Field(i)) == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func jsonName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "" || tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
+
+// configLeaf is a resolvable scalar or list value and its dotted key
+type configLeaf struct {
+	Key   string
+	Value reflect.Value
+}
+
+// configLeaves enumerates every leaf of config in struct order
+func configLeaves(config *AgentConfig) []configLeaf {
+	return appendLeaves(nil, reflect.ValueOf(config).Elem(), "")
+}
+
+func appendLeaves(leaves []configLeaf, v reflect.Value, prefix string) []configLeaf {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if v.Field(i).Kind() == reflect.Struct {
+			leaves = appendLeaves(leaves, v.Field(i), key)
+			continue
+		}
+		leaves = append(leaves, configLeaf{Key: key, Value: v.Field(i)})
+	}
+	return leaves
+}
+
+// leafString renders a leaf as plain text, JSON-encoding lists and maps. An
+// unset list is [], like an empty one, rather than null.
+func leafString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		data, _ := json.Marshal(leafInterface(v))
+		return string(data)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// leafInterface is the value of a leaf for JSON encoding, with a nil list
+// as an empty one
+func leafInterface(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return v.Interface()
+}
+
+// resolveKey maps a loosely typed key to its canonical dotted form, ignoring
+// case and the difference between dots, underscores, dashes and camelCase
+// (wallet.dailyLimit, wallet_daily_limit and WALLET.DAILY-LIMIT are all
+// wallet.daily_limit). A trailing part of a key also resolves when it names a
+// single field, like rate_limit.rps. Deprecated keys resolve to the field
+// that replaced them. Keys that match nothing are returned unchanged so the
+// caller reports them as unknown.
+func resolveKey(key string) (string, error) {
+	if current, deprecated := currentKey(key); deprecated {
+		return current, nil
+	}
+	var config AgentConfig
+	var keys []string
+	for _, leaf := range configLeaves(&config) {
+		keys = append(keys, leaf.Key)
+	}
+	return matchKey(key, keys)
+}
+
+// matchKey resolves key against the canonical keys as resolveKey describes
+func matchKey(key string, keys []string) (string, error) {
+	for _, k := range keys {
+		if k == key {
+			return key, nil
+		}
+	}
+
+	want := normalizeKey(key)
+	var exact, suffix []string
+	for _, k := range keys {
+		parts := strings.Split(k, ".")
+		if normalizeKey(k) == want {
+			exact = append(exact, k)
+			continue
+		}
+		for i := 1; i < len(parts); i++ {
+			if normalizeKey(strings.Join(parts[i:], ".")) == want {
+				suffix = append(suffix, k)
+				break
+			}
+		}
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = suffix
+	}
+	switch len(matches) {
+	case 0:
+		return key, nil
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous key %s matches %s", key, strings.Join(matches, ", "))
+	}
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer(".", "", "_", "", "-", "").Replace(key))
+}
+
+// canonicalKey resolves key for get/set, hinting at the canonical spelling
+// on stderr and exiting on ambiguous keys
+func canonicalKey(key string) string {
+	resolved, err := resolveKey(key)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if _, deprecated := currentKey(key); resolved != key && !deprecated {
+		fmt.Fprintf(os.Stderr, "💡 %s is %s\n", key, resolved)
+	}
+	return resolved
+}
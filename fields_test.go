@@ -0,0 +1,171 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShowListsEveryMetadataField(t *testing.T) {
+	isolatePaths(t)
+	writeTestConfig(t, defaultConfig())
+	out := captureStdout(t, func() { showConfig(nil) })
+
+	for _, field := range configFields {
+		if !strings.Contains(out, "  "+field.Label+":") {
+			t.Errorf("show doesn't list %s as %q", field.Key, field.Label)
+		}
+	}
+	for _, section := range configSections {
+		assertContains(t, out, section.Title+":")
+	}
+}
+
+func TestShowPicksUpNewMetadataEntry(t *testing.T) {
+	isolatePaths(t)
+	writeTestConfig(t, defaultConfig())
+	saved := configFields
+	t.Cleanup(func() { configFields = saved })
+
+	// A field only gets shown through its entry: drop one, then add it back
+	// with another label in a different place
+	var fields []fieldMeta
+	for _, field := range saved {
+		if field.Key != "wallet.alert_threshold" {
+			fields = append(fields, field)
+		}
+	}
+	configFields = fields
+	assertNotContains(t, captureStdout(t, func() { showConfig(nil) }), "Alert Threshold:")
+
+	configFields = append([]fieldMeta{{Key: "wallet.alert_threshold", Label: "Alert At", Format: formatETH}}, fields...)
+	out := captureStdout(t, func() { showConfig(nil) })
+	assertContains(t, out, "Alert At:", "0.1 ETH")
+	if strings.Index(out, "Alert At:") > strings.Index(out, "Address:") {
+		t.Errorf("show doesn't follow the metadata order:\n%s", out)
+	}
+}
+
+func TestEveryLeafHasMetadata(t *testing.T) {
+	config := defaultConfig()
+	known := map[string]bool{}
+	for _, field := range configFields {
+		known[field.Key] = true
+	}
+	for _, leaf := range configLeaves(&config) {
+		if !strings.Contains(leaf.Key, ".") || strings.HasPrefix(leaf.Key, "validation.") {
+			continue
+		}
+		if !known[leaf.Key] {
+			t.Errorf("%s has no configFields entry, so show doesn't list it", leaf.Key)
+		}
+	}
+}
+
+func TestShowMasksSecrets(t *testing.T) {
+	isolatePaths(t)
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-value-123"
+	writeTestConfig(t, config)
+	out := captureStdout(t, func() { showConfig(nil) })
+	assertNotContains(t, out, "etherscan-secret-value-123")
+	assertContains(t, out, "Etherscan:")
+}
+
+func TestResolveKeySpellings(t *testing.T) {
+	for _, key := range []string{
+		"wallet.daily_limit",
+		"wallet.dailyLimit",
+		"wallet_daily_limit",
+		"WALLET.DAILY-LIMIT",
+		"Wallet.DailyLimit",
+		"daily_limit",
+	} {
+		if got, err := resolveKey(key); err != nil || got != "wallet.daily_limit" {
+			t.Errorf("resolveKey(%s) = %s, %v", key, got, err)
+		}
+	}
+	for key, want := range map[string]string{
+		"rate_limit.rps":  "monitoring.rate_limit.rps",
+		"rateLimit.burst": "monitoring.rate_limit.burst",
+		"api_keys.openAI": "api_keys.openai",
+		"no.such.key":     "no.such.key",
+		"erc8004Id":       "agent.erc8004_id",
+	} {
+		if got, err := resolveKey(key); err != nil || got != want {
+			t.Errorf("resolveKey(%s) = %s, %v, want %s", key, got, err, want)
+		}
+	}
+}
+
+func TestMatchKeyAmbiguous(t *testing.T) {
+	keys := []string{"wallet.daily_limit", "networks.base.daily_limit", "wallet.address"}
+	_, err := matchKey("dailyLimit", keys)
+	if err == nil {
+		t.Fatal("ambiguous key resolved")
+	}
+	assertContains(t, err.Error(), "ambiguous key dailyLimit matches wallet.daily_limit, networks.base.daily_limit")
+
+	// a full match wins over suffix matches
+	if got, err := matchKey("wallet.dailyLimit", keys); err != nil || got != "wallet.daily_limit" {
+		t.Errorf("matchKey(wallet.dailyLimit) = %s, %v", got, err)
+	}
+}
+
+func TestGetSetHintCanonicalKey(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	out := e.mustRun("set", "wallet.dailyLimit", "0.25")
+	assertContains(t, out, "💡 wallet.dailyLimit is wallet.daily_limit", "Set wallet.daily_limit")
+	assertContains(t, e.mustRun("get", "WALLET_DAILY_LIMIT"), "0.25")
+}
+
+// valueColumns returns, for each indented line of each section in show's
+// output, the rune offset where the value starts
+func valueColumns(out string) map[string][]int {
+	columns := map[string][]int{}
+	title := ""
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasSuffix(line, ":") && !strings.HasPrefix(line, " "):
+			title = line
+		case title != "" && strings.HasPrefix(line, "  "):
+			colon := strings.Index(line, ":")
+			rest := line[colon+1:]
+			start := colon + 1 + len(rest) - len(strings.TrimLeft(rest, " "))
+			columns[title] = append(columns[title], len([]rune(line[:start])))
+		default:
+			title = ""
+		}
+	}
+	return columns
+}
+
+func TestShowAlignsColumns(t *testing.T) {
+	isolatePaths(t)
+	config := defaultConfig()
+	config.Agent.Name = strings.Repeat("long-agent-name-", 4)
+	config.Networks = map[string]NetworkSettings{
+		"ethereum":     {RPCURL: "https://eth.example.com"},
+		"base-sepolia": {DailyLimit: 0.25},
+		"op":           {RPCURL: "https://op.example.com", DailyLimit: 1},
+	}
+	writeTestConfig(t, config)
+	out := captureStdout(t, func() { showConfig(nil) })
+
+	columns := valueColumns(out)
+	for _, title := range []string{"AGENT:", "WALLET:", "SECURITY:", "NETWORKS:"} {
+		cols := columns[title]
+		if len(cols) < 2 {
+			t.Errorf("section %s has %d rows", title, len(cols))
+			continue
+		}
+		for _, col := range cols[1:] {
+			if col != cols[0] {
+				t.Errorf("section %s values start at columns %v:\n%s", title, cols, out)
+				break
+			}
+		}
+	}
+	// the widest label is followed by a single space
+	assertContains(t, out, "  Alert Threshold: 0.1 ETH\n", "  base-sepolia: limit 0.25 ETH\n", "  ethereum:     RPC https://eth.example.com\n")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// genKeyCommand creates a new agent wallet keypair. The private key is
+// printed once or written to its own file, and never stored in the config.
+func genKeyCommand(args []string) {
+	fs := flag.NewFlagSet("gen-key", flag.ExitOnError)
+	out := fs.String("out", "", "write the private key to this file (0600) instead of printing it")
+	parseFlags(fs, args)
+
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		fmt.Printf("❌ Failed to generate key: %v\n", err)
+		os.Exit(1)
+	}
+	privateHex := hex.EncodeToString(key.Serialize())
+	address := pubkeyAddress(key.PubKey())
+
+	fmt.Printf("✅ Generated new wallet %s\n", address)
+	fmt.Println()
+	if *out != "" {
+		*out = expandPath(*out)
+		f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, err = fmt.Fprintln(f, privateHex)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			fmt.Printf("❌ Failed to write private key: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🔑 Private key written to %s\n", *out)
+		fmt.Println("   Move it into your wallet or secret manager, then delete the file")
+	} else {
+		fmt.Println("🔑 Private key (shown once, it is not saved anywhere):")
+		fmt.Printf("   %s\n", privateHex)
+		fmt.Println("   Store it in your wallet or secret manager now")
+	}
+	fmt.Println()
+
+	if _, err := os.Stat(getConfigPath()); err != nil {
+		fmt.Println("No config yet; run 'acm init' and re-run with --yes to save the address")
+		return
+	}
+	ok, err := confirm(fmt.Sprintf("Set wallet.address to %s?", address))
+	if errors.Is(err, errNoTTY) {
+		fmt.Println("wallet.address left unchanged (re-run with --yes to set it)")
+		return
+	}
+	if err != nil || !ok {
+		fmt.Println("wallet.address left unchanged")
+		return
+	}
+	config := loadConfig()
+	config.Wallet.Address = address
+	saveConfig(config)
+	fmt.Println("✅ Set wallet.address")
+}
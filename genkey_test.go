@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestPubkeyAddressKnownKeys(t *testing.T) {
+	for privateHex, want := range map[string]string{
+		"0000000000000000000000000000000000000000000000000000000000000001": "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		"4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318": "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23",
+	} {
+		data, _ := hex.DecodeString(privateHex)
+		key := secp256k1.PrivKeyFromBytes(data)
+		if got := pubkeyAddress(key.PubKey()); got != want {
+			t.Errorf("address of %s = %s, want %s", privateHex, got, want)
+		}
+	}
+}
+
+var privateKeyPattern = regexp.MustCompile(`\b[0-9a-f]{64}\b`)
+
+func TestGenKeyKeepsPrivateKeyOutOfConfig(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	out := e.mustRun("--yes", "gen-key")
+	private := privateKeyPattern.FindString(out)
+	if private == "" {
+		t.Fatalf("no private key printed:\n%s", out)
+	}
+	assertContains(t, out, "Set wallet.address")
+
+	config := e.read(e.configPath())
+	if strings.Contains(config, private) {
+		t.Fatal("private key written to the config")
+	}
+	data, _ := hex.DecodeString(private)
+	address := pubkeyAddress(secp256k1.PrivKeyFromBytes(data).PubKey())
+	assertContains(t, config, address)
+}
+
+func TestGenKeyOut(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	before := e.read(e.configPath())
+	path := filepath.Join(e.home, "agent.key")
+
+	out := e.mustRun("gen-key", "--out", path)
+	assertContains(t, out, "Private key written to "+path, "wallet.address left unchanged")
+	private := strings.TrimSpace(e.read(path))
+	if !privateKeyPattern.MatchString(private) || strings.Contains(out, private) {
+		t.Errorf("key file holds %q, output:\n%s", private, out)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("key file mode: %v", err)
+	}
+	if e.read(e.configPath()) != before {
+		t.Error("config changed without --yes")
+	}
+
+	assertContains(t, e.mustFail("gen-key", "--out", path), "Failed to write private key")
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGetAllCoversEveryLeaf(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	out := e.mustRun("get", "--all")
+
+	lines := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			t.Fatalf("line %q is not key=value", line)
+		}
+		lines[key] = value
+	}
+	config := defaultConfig()
+	for _, leaf := range configLeaves(&config) {
+		_, listed := lines[leaf.Key]
+		if meta := !strings.Contains(leaf.Key, ".") && leaf.Key != "version"; meta {
+			if listed {
+				t.Errorf("unset %s is listed", leaf.Key)
+			}
+			continue
+		}
+		if !listed {
+			t.Errorf("%s is missing", leaf.Key)
+		}
+	}
+	if lines["wallet.daily_limit"] != "0.5" || lines["wallet.networks"] != `["ethereum","base"]` {
+		t.Errorf("wallet values: %v", lines)
+	}
+	if lines["api_keys.etherscan"] == "" || strings.Contains(out, "etherscan-secret-value-123") {
+		t.Errorf("api_keys.etherscan = %q, want it masked", lines["api_keys.etherscan"])
+	}
+}
+
+func TestGetAllListsSetMetaKeys(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "child", "--extends", "default")
+	out := e.mustRun("--profile", "child", "get", "--all")
+	assertContains(t, out, "\nextends=default\n")
+	assertNotContains(t, out, "\ninclude=", "\nnetworks=")
+}
+
+func TestGetAllJSON(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.openai", "sk-openai-abcdefghijklmnop")
+	var flat map[string]interface{}
+	if err := json.Unmarshal([]byte(e.mustRun("get", "--all", "--format", "json")), &flat); err != nil {
+		t.Fatal(err)
+	}
+	if flat["wallet.daily_limit"] != 0.5 || flat["security.firewall_enabled"] != true {
+		t.Errorf("flat = %v", flat)
+	}
+	if v, _ := flat["api_keys.openai"].(string); v == "" || strings.Contains(v, "abcdefghijklmnop") {
+		t.Errorf("api_keys.openai = %v, want it masked", flat["api_keys.openai"])
+	}
+	if _, ok := flat["include"]; ok {
+		t.Error("unset include is listed")
+	}
+}
+
+func TestGetSetScalars(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	for _, tt := range []struct{ key, value, bad string }{
+		{"agent.website", "https://agent.example.com", "agent.example.com"},
+		{"agent.github", "https://github.com/example", "https://gitlab.com/example"},
+		{"agent.erc8004_id", "2048", "two"},
+		{"monitoring.dashboard_enabled", "true", "maybe"},
+		{"security.prompt_guard_enabled", "false", "nope"},
+		{"security.simulator_enabled", "false", "2"},
+	} {
+		e.mustRun("set", tt.key, tt.value)
+		if got := strings.TrimSpace(e.mustRun("get", tt.key)); got != tt.value {
+			t.Errorf("get %s = %q, want %q", tt.key, got, tt.value)
+		}
+		assertContains(t, e.mustFail("set", tt.key, tt.bad), "invalid value for "+tt.key)
+		if got := strings.TrimSpace(e.mustRun("get", tt.key)); got != tt.value {
+			t.Errorf("a rejected set changed %s to %q", tt.key, got)
+		}
+	}
+
+	// the values are stored with their JSON types
+	config := map[string]interface{}{}
+	json.Unmarshal([]byte(e.read(e.configPath())), &config)
+	if id := section(config, "agent")["erc8004_id"]; id != 2048.0 {
+		t.Errorf("erc8004_id stored as %#v", id)
+	}
+	if enabled := section(config, "monitoring")["dashboard_enabled"]; enabled != true {
+		t.Errorf("dashboard_enabled stored as %#v", enabled)
+	}
+}
+
+func TestGetExists(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.mustRun("set", "security.whitelisted_addresses", "")
+	e.mustRun("set", "monitoring.dashboard_enabled", "false")
+
+	for _, tt := range []struct {
+		key  string
+		code int
+	}{
+		{"api_keys.etherscan", 0},
+		{"wallet.daily_limit", 0},
+		{"wallet.networks", 0},
+		{"api_keys.basescan", 1},
+		{"monitoring.webhook_url", 1},
+		{"monitoring.dashboard_enabled", 1},
+		{"security.whitelisted_addresses", 1},
+		{"no.such.key", 2},
+		{"wallet", 2},
+	} {
+		out, code := e.run("get", "--exists", tt.key)
+		if code != tt.code {
+			t.Errorf("get --exists %s exited %d, want %d", tt.key, code, tt.code)
+		}
+		if tt.code != 2 && out != "" {
+			t.Errorf("get --exists %s printed %q", tt.key, out)
+		}
+	}
+
+	// the environment counts, as for every read
+	e.setenv("BASESCAN_API_KEY", "basescan-from-env-123")
+	e.mustRun("get", "--exists", "api_keys.basescan")
+
+	if out := e.mustRun("get", "--exists", "--print", "api_keys.etherscan"); out != "true\n" {
+		t.Errorf("--print for a set key = %q", out)
+	}
+	if out, code := e.run("get", "--exists", "--print", "monitoring.webhook_url"); out != "false\n" || code != 1 {
+		t.Errorf("--print for an unset key = %q, exit %d", out, code)
+	}
+}
+
+func TestGetAllFlattensMaps(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "networks.base.daily_limit", "0.2")
+	out := e.mustRun("get", "--all")
+	assertContains(t, out, "\nnetworks.base.daily_limit=0.2\n", "\nnetworks.base.rpc_url=\n", "\nvalidation.hooks=[]\n", "\nsecurity.whitelisted_addresses=[]\n")
+	assertNotContains(t, out, "\nnetworks=", "null")
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		if got := strings.TrimSpace(e.mustRun("get", key)); got != value && !isSecretKey(key) {
+			t.Errorf("get --all lists %s=%s, get prints %s", key, value, got)
+		}
+	}
+
+	var flat map[string]interface{}
+	if err := json.Unmarshal([]byte(e.mustRun("get", "--all", "--format", "json")), &flat); err != nil {
+		t.Fatal(err)
+	}
+	if flat["networks.base.daily_limit"] != 0.2 {
+		t.Errorf("networks.base.daily_limit = %v", flat["networks.base.daily_limit"])
+	}
+	if hooks, ok := flat["validation.hooks"].([]interface{}); !ok || len(hooks) != 0 {
+		t.Errorf("validation.hooks = %#v, want []", flat["validation.hooks"])
+	}
+}
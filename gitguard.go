@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// forceWrite is set by the global --force flag. It overrides the refusal to
+// save a config into a git working tree, and lets `init` overwrite a config.
+var forceWrite bool
+
+// gitWorkTree returns the root of the git working tree containing path, or
+// "" when there is none. A .git file counts too: worktrees and submodules
+// use one.
+func gitWorkTree(path string) string {
+	dir := filepath.Dir(absPath(path))
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// gitIgnored asks git whether path is ignored in the working tree at root.
+// It errors when git can't answer, e.g. because it isn't installed.
+func gitIgnored(root, path string) (bool, error) {
+	cmd := exec.Command("git", "-C", root, "check-ignore", "-q", "--", absPath(path))
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// checkGitWorkTree guards against the most common way keys leak: saving a
+// config inside a repository and committing it. It warns whenever path is in
+// a working tree and refuses, unless --force is given, when git doesn't
+// ignore the file.
+func checkGitWorkTree(path string) error {
+	root := gitWorkTree(path)
+	if root == "" {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  %s is inside the git working tree at %s; keep it out of commits, it holds your API keys\n", path, root)
+
+	ignored, err := gitIgnored(root, path)
+	if ignored || forceWrite {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't check whether git ignores %s (%v); re-run with --force to save anyway", path, err)
+	}
+	return fmt.Errorf("%s is not gitignored; add it to .gitignore or re-run with --force to save anyway", path)
+}
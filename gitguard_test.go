@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitWorkTree(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "repo", ".git"), 0700)
+	os.MkdirAll(filepath.Join(root, "repo", "configs", "agent"), 0700)
+	os.MkdirAll(filepath.Join(root, "worktree"), 0700)
+	os.WriteFile(filepath.Join(root, "worktree", ".git"), []byte("gitdir: ../repo/.git/worktrees/w\n"), 0600)
+
+	for path, want := range map[string]string{
+		filepath.Join(root, "repo", "configs", "agent", "config.json"): filepath.Join(root, "repo"),
+		filepath.Join(root, "worktree", "config.json"):                 filepath.Join(root, "worktree"),
+		filepath.Join(root, "config.json"):                             "",
+	} {
+		if got := gitWorkTree(path); got != want {
+			t.Errorf("gitWorkTree(%s) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// gitRepo creates a git working tree for tests that need git itself
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func TestSaveInGitRepoRefused(t *testing.T) {
+	repo := gitRepo(t)
+	e := newTestEnv(t)
+	path := filepath.Join(repo, "agent.json")
+
+	out := e.mustFail("--config", path, "init")
+	assertContains(t, out, "inside the git working tree at "+repo, "is not gitignored", "--force")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("config written despite the refusal: %v", err)
+	}
+
+	assertContains(t, e.mustRun("--config", path, "--force", "init"), "inside the git working tree")
+	assertContains(t, e.mustFail("--config", path, "set", "agent.name", "X"), "is not gitignored")
+}
+
+func TestSaveInGitRepoIgnored(t *testing.T) {
+	repo := gitRepo(t)
+	e := newTestEnv(t)
+	path := filepath.Join(repo, "agent.json")
+	e.write(filepath.Join(repo, ".gitignore"), "agent.json\n")
+
+	out := e.mustRun("--config", path, "init")
+	assertContains(t, out, "inside the git working tree")
+	assertNotContains(t, out, "not gitignored")
+
+	assertNotContains(t, e.mustRun("init"), "git working tree")
+}
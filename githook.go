@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The block `acm hook install` manages inside .git/hooks/pre-commit. Other
+// lines of the hook are left alone.
+const (
+	hookBlockStart = "# >>> acm pre-commit >>>"
+	hookBlockEnd   = "# <<< acm pre-commit <<<"
+)
+
+// gitHookCommand handles `acm hook install|uninstall`
+func gitHookCommand(args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: acm hook install [--check validate|export] | acm hook uninstall")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "install":
+		gitHookInstall(args[1:])
+	case "uninstall":
+		gitHookUninstall()
+	default:
+		fmt.Printf("❌ Unknown hook command: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+func gitHookInstall(args []string) {
+	fs := flag.NewFlagSet("hook install", flag.ExitOnError)
+	check := fs.String("check", "validate", "what the hook runs: validate (config-test --strict) or export (export --check)")
+	parseFlags(fs, args)
+
+	root, hookPath, err := preCommitHookPath()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	// Name the config relative to the repo when it's inside it, since hooks
+	// run from the top of the working tree
+	configPath := absPath(getConfigPath())
+	if rel, err := filepath.Rel(root, configPath); err == nil && !strings.HasPrefix(rel, "..") {
+		configPath = rel
+	}
+	acm := "acm"
+	if _, err := exec.LookPath("acm"); err != nil {
+		if exe, err := os.Executable(); err == nil {
+			acm = exe
+		}
+	}
+
+	var command string
+	switch *check {
+	case "validate":
+		command = fmt.Sprintf("%s --strict config-test %s", shellQuote(acm), shellQuote(configPath))
+	case "export":
+		command = fmt.Sprintf("%s --config %s export --check", shellQuote(acm), shellQuote(configPath))
+	default:
+		fmt.Printf("❌ Unknown hook check: %s (use validate or export)\n", *check)
+		os.Exit(1)
+	}
+	block := strings.Join([]string{
+		hookBlockStart,
+		"# Installed by `acm hook install`; remove with `acm hook uninstall`",
+		command + " || exit 1",
+		hookBlockEnd,
+	}, "\n") + "\n"
+
+	existing, err := os.ReadFile(hookPath)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Printf("❌ Failed to read %s: %v\n", hookPath, err)
+		os.Exit(1)
+	}
+	script, replaced := withHookBlock(string(existing), block)
+	os.MkdirAll(filepath.Dir(hookPath), 0755)
+	if err := writeFileAtomic(hookPath, []byte(script), 0755); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", hookPath, err)
+		os.Exit(1)
+	}
+
+	switch {
+	case replaced:
+		fmt.Printf("✅ Updated the acm check in %s\n", hookPath)
+	case len(existing) > 0:
+		fmt.Printf("✅ Added the acm check to the existing hook %s\n", hookPath)
+	default:
+		fmt.Printf("✅ Installed %s\n", hookPath)
+	}
+	fmt.Printf("   Runs before each commit: %s\n", command)
+}
+
+func gitHookUninstall() {
+	_, hookPath, err := preCommitHookPath()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	existing, err := os.ReadFile(hookPath)
+	if os.IsNotExist(err) {
+		fmt.Println("✅ No pre-commit hook installed")
+		return
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to read %s: %v\n", hookPath, err)
+		os.Exit(1)
+	}
+
+	script, found := withHookBlock(string(existing), "")
+	if !found {
+		fmt.Printf("✅ %s has no acm check\n", hookPath)
+		return
+	}
+	// Remove a hook that only ever held the acm check
+	if strings.TrimSpace(strings.TrimPrefix(script, "#!/bin/sh")) == "" {
+		err = os.Remove(hookPath)
+	} else {
+		err = writeFileAtomic(hookPath, []byte(script), 0755)
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to update %s: %v\n", hookPath, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Removed the acm check from %s\n", hookPath)
+}
+
+// withHookBlock returns script with the acm block replaced by block (or
+// removed when block is empty) and whether there was one. A new block goes
+// right after the shebang so an `exit 0` later in the hook can't skip it.
+func withHookBlock(script, block string) (string, bool) {
+	if start := strings.Index(script, hookBlockStart); start >= 0 {
+		if end := strings.Index(script[start:], hookBlockEnd); end >= 0 {
+			end += start + len(hookBlockEnd)
+			if end < len(script) && script[end] == '\n' {
+				end++
+			}
+			return script[:start] + block + script[end:], true
+		}
+	}
+	if block == "" {
+		return script, false
+	}
+
+	if script == "" {
+		return "#!/bin/sh\n" + block, false
+	}
+	if strings.HasPrefix(script, "#!") {
+		nl := strings.Index(script, "\n")
+		if nl < 0 {
+			return script + "\n" + block, false
+		}
+		return script[:nl+1] + block + script[nl+1:], false
+	}
+	return block + script, false
+}
+
+// preCommitHookPath finds the working tree of the current directory and its
+// pre-commit hook, honoring core.hooksPath
+func preCommitHookPath() (string, string, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", "", errors.New("not inside a git working tree")
+	}
+	root := strings.TrimSpace(string(out))
+	out, err = exec.Command("git", "-C", root, "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("couldn't find the git hooks directory: %v", err)
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	return root, filepath.Join(dir, "pre-commit"), nil
+}
+
+// shellQuote quotes s for a POSIX shell when it needs it
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// hookRepo makes the test home a git working tree and returns the path of
+// its pre-commit hook
+func (e *testEnv) hookRepo() string {
+	e.t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		e.t.Skip("git not installed")
+	}
+	e.init()
+	if out, err := exec.Command("git", "init", "-q", e.home).CombinedOutput(); err != nil {
+		e.t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return filepath.Join(e.home, ".git", "hooks", "pre-commit")
+}
+
+func TestHookInstall(t *testing.T) {
+	e := newTestEnv(t)
+	hook := e.hookRepo()
+
+	assertContains(t, e.mustRun("hook", "install"), "Installed "+hook)
+	info, err := os.Stat(hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+		t.Errorf("hook mode = %v, want it executable", info.Mode())
+	}
+	script := e.read(hook)
+	if !strings.HasPrefix(script, "#!/bin/sh\n"+hookBlockStart) {
+		t.Errorf("hook doesn't start with the acm block:\n%s", script)
+	}
+	// the config is inside the repo, so it's named relative to the top
+	assertContains(t, script, "--strict config-test .config/agent/config.json || exit 1")
+
+	// reinstalling replaces the block rather than adding another
+	assertContains(t, e.mustRun("hook", "install", "--check", "export"), "Updated the acm check in "+hook)
+	script = e.read(hook)
+	if strings.Count(script, hookBlockStart) != 1 {
+		t.Errorf("hook has %d acm blocks:\n%s", strings.Count(script, hookBlockStart), script)
+	}
+	assertContains(t, script, "--config .config/agent/config.json export --check || exit 1")
+	assertNotContains(t, script, "config-test")
+
+	assertContains(t, e.mustFail("hook", "install", "--check", "lint"), "Unknown hook check: lint")
+}
+
+func TestHookKeepsExistingHook(t *testing.T) {
+	e := newTestEnv(t)
+	hook := e.hookRepo()
+	existing := "#!/bin/sh\nmake lint\nexit 0\n"
+	e.write(hook, existing)
+	os.Chmod(hook, 0755)
+
+	assertContains(t, e.mustRun("hook", "install"), "Added the acm check to the existing hook "+hook)
+	script := e.read(hook)
+	// the check goes before the hook's own exit 0
+	if start, lint := strings.Index(script, hookBlockStart), strings.Index(script, "make lint"); start < 0 || lint < start {
+		t.Errorf("acm block isn't ahead of the existing commands:\n%s", script)
+	}
+
+	assertContains(t, e.mustRun("hook", "uninstall"), "Removed the acm check from "+hook)
+	if got := e.read(hook); got != existing {
+		t.Errorf("uninstall left\n%s\nwant\n%s", got, existing)
+	}
+	assertContains(t, e.mustRun("hook", "uninstall"), "has no acm check")
+}
+
+func TestHookUninstallRemovesOwnHook(t *testing.T) {
+	e := newTestEnv(t)
+	hook := e.hookRepo()
+	e.mustRun("hook", "install")
+	e.mustRun("hook", "uninstall")
+	if _, err := os.Stat(hook); !os.IsNotExist(err) {
+		t.Errorf("hook holding only the acm check still exists: %v", err)
+	}
+	assertContains(t, e.mustRun("hook", "uninstall"), "No pre-commit hook installed")
+}
+
+func TestHookOutsideRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustFail("hook", "install"), "not inside a git working tree")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// hookTimeout bounds how long a single validation hook may run
+const hookTimeout = 10 * time.Second
+
+// runValidationHooks runs every command in validation.hooks with the config
+// JSON on stdin and reports a non-zero exit as an error whose message is the
+// hook's stderr. Hooks are split on whitespace and run without a shell, in
+// dir (the config's directory), with a minimal environment and with API keys
+// masked in the JSON they receive.
+func runValidationHooks(config AgentConfig, dir string) []finding {
+	if len(config.Validation.Hooks) == 0 {
+		return nil
+	}
+
+	config.APIKeys = APIKeysConfig{
+		Etherscan: maskSecret(config.APIKeys.Etherscan),
+		Basescan:  maskSecret(config.APIKeys.Basescan),
+		OpenAI:    maskSecret(config.APIKeys.OpenAI),
+		Anthropic: maskSecret(config.APIKeys.Anthropic),
+		Discord:   maskSecret(config.APIKeys.Discord),
+	}
+	config.Monitoring.WebhookSecret = maskSecret(config.Monitoring.WebhookSecret)
+	input, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return []finding{errorf("hooks", "Validation hooks: %v", err)}
+	}
+
+	var findings []finding
+	for _, hook := range config.Validation.Hooks {
+		if err := runHook(hook, dir, input); err != nil {
+			findings = append(findings, errorf("hooks", "Hook %s: %v", hook, err))
+		}
+	}
+	return findings
+}
+
+func runHook(hook, dir string, input []byte) error {
+	argv := strings.Fields(hook)
+	if len(argv) == 0 {
+		return errors.New("empty command")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "ACM_VERSION=" + version}
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Don't wait on children of a killed hook that still hold stderr open
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", hookTimeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writeHook saves a shell script next to the config and returns its name
+func (e *testEnv) writeHook(name, script string) string {
+	e.t.Helper()
+	path := filepath.Join(filepath.Dir(e.configPath()), name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0700); err != nil {
+		e.t.Fatal(err)
+	}
+	return path
+}
+
+func (e *testEnv) setHooks(hooks ...string) {
+	e.t.Helper()
+	e.editConfig(func(config map[string]interface{}) {
+		list := make([]interface{}, len(hooks))
+		for i, hook := range hooks {
+			list[i] = hook
+		}
+		section(config, "validation")["hooks"] = list
+	})
+}
+
+func TestValidationHookPasses(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	seen := filepath.Join(e.home, "seen")
+	e.writeHook("check.sh", `cat > "$1.json"; env > "$1.env"`+"\n")
+	e.setHooks("./check.sh " + seen)
+
+	e.mustRun("validate")
+	input := e.read(seen + ".json")
+	assertContains(t, input, `"name": "Arithmos"`)
+	assertNotContains(t, input, "etherscan-secret-value-123")
+
+	env := e.read(seen + ".env")
+	assertContains(t, env, "ACM_VERSION="+version)
+	assertNotContains(t, env, "HOME=")
+}
+
+func TestValidationHookFails(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	e := newTestEnv(t)
+	e.init()
+	e.writeHook("ok.sh", "exit 0\n")
+	e.writeHook("policy.sh", "cat > /dev/null\necho 'daily_limit needs approval above 0.1' >&2\nexit 3\n")
+	e.setHooks("./ok.sh", "./policy.sh")
+
+	out, _ := e.run("validate")
+	assertContains(t, out, "❌ Hook ./policy.sh: daily_limit needs approval above 0.1", "1 error(s)")
+	assertNotContains(t, out, "Hook ./ok.sh")
+	e.mustFail("validate", "--exit-on", "first")
+}
+
+func TestRunHookErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	dir := t.TempDir()
+	if err := runHook("  ", dir, nil); err == nil {
+		t.Error("empty hook ran")
+	}
+	if err := runHook("./missing-hook", dir, nil); err == nil {
+		t.Error("missing hook succeeded")
+	}
+	os.WriteFile(filepath.Join(dir, "quiet.sh"), []byte("#!/bin/sh\nexit 1\n"), 0700)
+	if err := runHook("./quiet.sh", dir, nil); err == nil || err.Error() != "exit status 1" {
+		t.Errorf("silent failing hook: %v", err)
+	}
+}
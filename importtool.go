@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// toolImportField maps one field of an exported tool config back onto the
+// config key it was exported from. Objects (rate_limit, observability) map
+// onto a section field by field.
+type toolImportField struct {
+	Field string
+	Key   string
+	// Convert turns a derived value back into the config's unit
+	Convert func(string) (string, error)
+}
+
+// toolImportFields is the inverse of exportTools
+var toolImportFields = map[string][]toolImportField{
+	"wallet-monitor": {
+		{Field: "address", Key: "wallet.address"},
+		{Field: "etherscan_key", Key: "api_keys.etherscan"},
+		{Field: "basescan_key", Key: "api_keys.basescan"},
+		{Field: "check_interval", Key: "monitoring.check_interval_minutes"},
+		{Field: "check_interval_seconds", Key: "monitoring.check_interval_minutes", Convert: secondsToMinutes},
+		{Field: "daily_limit", Key: "wallet.daily_limit"},
+		{Field: "daily_limit_wei", Key: "wallet.daily_limit", Convert: weiToETH},
+		{Field: "alert_threshold", Key: "wallet.alert_threshold"},
+		{Field: "alert_threshold_wei", Key: "wallet.alert_threshold", Convert: weiToETH},
+		{Field: "webhook_url", Key: "monitoring.webhook_url"},
+		{Field: "webhook_secret", Key: "monitoring.webhook_secret"},
+		{Field: "webhook_template", Key: "monitoring.webhook_template"},
+		{Field: "rate_limit", Key: "monitoring.rate_limit"},
+		{Field: "observability", Key: "observability"},
+	},
+	"reputation-scanner": {
+		{Field: "address", Key: "wallet.address"},
+		{Field: "etherscan_key", Key: "api_keys.etherscan"},
+		{Field: "basescan_key", Key: "api_keys.basescan"},
+		{Field: "rate_limit", Key: "monitoring.rate_limit"},
+		{Field: "observability", Key: "observability"},
+	},
+	"security-dashboard": {
+		{Field: "port", Key: "monitoring.dashboard_port"},
+		{Field: "observability", Key: "observability"},
+	},
+}
+
+func secondsToMinutes(value string) (string, error) {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds%60 != 0 {
+		return "", fmt.Errorf("%q is not a whole number of minutes in seconds", value)
+	}
+	return strconv.Itoa(seconds / 60), nil
+}
+
+// importedValue is one value read from a tool config
+type importedValue struct {
+	Field string // dotted path in the tool config
+	Key   string
+	Value string
+}
+
+// importCommand handles `acm import --from-tool <tool> [file]`, pulling
+// edits made to an exported tool config back into the config. Only fields
+// whose value differs from the effective config are written, so ${VAR}
+// references and environment secrets stay as they are.
+func importCommand(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	tool := fs.String("from-tool", "", "tool whose exported config to read: wallet-monitor, reputation-scanner or security-dashboard")
+	positional := parseFlags(fs, args)
+	fields, ok := toolImportFields[*tool]
+	if !ok || len(positional) > 1 {
+		if *tool != "" && !ok {
+			fmt.Printf("❌ Unknown tool: %s\n", *tool)
+		}
+		fmt.Println("Usage: acm import --from-tool <wallet-monitor|reputation-scanner|security-dashboard> [file]")
+		os.Exit(1)
+	}
+
+	path := filepath.Join(getExportDir(), *tool+".json")
+	if len(positional) == 1 {
+		path = expandPath(positional[0])
+	}
+	data, err := readConfigFile(path)
+	if err != nil {
+		fmt.Printf("❌ Failed to read %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	values, problems, err := readToolConfig(data, fields)
+	if err != nil {
+		fmt.Printf("❌ Invalid %s config in %s: %v\n", *tool, path, err)
+		os.Exit(1)
+	}
+
+	// Report every bad field at once rather than one per run
+	effective := loadEffectiveConfig()
+	changes, invalid := toolConfigChanges(effective, values)
+	if problems = append(problems, invalid...); len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Printf("❌ %s\n", p)
+		}
+		err := validationError("", errors.New("invalid tool config"))
+		exitWith(err, "\n❌ Found %d problem(s) in %s, nothing imported\n", len(problems), path)
+	}
+	if len(changes) == 0 {
+		fmt.Printf("✅ No changes to import from %s\n", path)
+		return
+	}
+
+	fmt.Printf("📥 Importing from %s (current → imported):\n", path)
+	fmt.Println()
+	for _, c := range changes {
+		oldValue, _ := valueLeafString(&effective, c.Key)
+		newValue := c.Value
+		if isSecretKey(c.Key) {
+			oldValue, newValue = maskSecretPair(oldValue, newValue)
+		}
+		fmt.Printf("  ~ %s: %s → %s\n", c.Key, oldValue, newValue)
+	}
+	fmt.Println()
+
+	config := loadConfig()
+	for _, c := range changes {
+		if err := assignValue(&config, c.Key, c.Value, false); err != nil {
+			exitWith(err, "❌ %v\n", err)
+		}
+	}
+	if backup, err := backupConfig(); err != nil {
+		fmt.Printf("❌ Failed to back up the current config: %v\n", err)
+		os.Exit(1)
+	} else if backup != "" {
+		fmt.Printf("💾 Backed up current config to %s\n", backup)
+	}
+	saveConfig(config)
+	fmt.Printf("✅ Imported %d change(s) from %s\n", len(changes), *tool)
+}
+
+// readToolConfig decodes a tool config into values for the config keys in
+// fields, warning about fields acm doesn't own and listing the values that
+// can't be read back, by field path. A derived field such as daily_limit_wei
+// is only used when the plain field is missing, since one of the two is
+// stale when they disagree and the plain one is what's edited.
+func readToolConfig(data []byte, fields []toolImportField) ([]importedValue, []string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	raw := map[string]interface{}{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, nil, err
+	}
+
+	byField := map[string]toolImportField{}
+	for _, f := range fields {
+		byField[f.Field] = f
+	}
+	var values, derived []importedValue
+	var problems []string
+	for _, name := range sortedKeys(raw) {
+		f, ok := byField[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "⚠️  Ignoring %s: not exported from the config\n", name)
+			continue
+		}
+		flat, p := flattenToolValue(name, f.Key, raw[name])
+		problems = append(problems, p...)
+		if f.Convert == nil {
+			values = append(values, flat...)
+			continue
+		}
+		for _, v := range flat {
+			converted, err := f.Convert(v.Value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", v.Field, err))
+				continue
+			}
+			v.Value = converted
+			derived = append(derived, v)
+		}
+	}
+
+	plain := map[string]importedValue{}
+	for _, v := range values {
+		plain[v.Key] = v
+	}
+	for _, v := range derived {
+		p, ok := plain[v.Key]
+		if !ok {
+			values = append(values, v)
+			continue
+		}
+		if !sameNumber(p.Value, v.Value) {
+			fmt.Fprintf(os.Stderr, "⚠️  %s disagrees with %s; using %s\n", v.Field, p.Field, p.Field)
+		}
+	}
+	return values, problems, nil
+}
+
+func sameNumber(a, b string) bool {
+	x, errX := strconv.ParseFloat(a, 64)
+	y, errY := strconv.ParseFloat(b, 64)
+	if errX != nil || errY != nil {
+		return a == b
+	}
+	return x == y
+}
+
+// flattenToolValue turns one tool config value into scalar values, objects
+// field by field, and lists the values that aren't scalars
+func flattenToolValue(field, key string, v interface{}) ([]importedValue, []string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		var values []importedValue
+		var problems []string
+		for _, name := range sortedKeys(v) {
+			flat, p := flattenToolValue(field+"."+name, key+"."+name, v[name])
+			values = append(values, flat...)
+			problems = append(problems, p...)
+		}
+		return values, problems
+	case string:
+		return []importedValue{{Field: field, Key: key, Value: v}}, nil
+	case json.Number:
+		return []importedValue{{Field: field, Key: key, Value: v.String()}}, nil
+	case bool:
+		return []importedValue{{Field: field, Key: key, Value: strconv.FormatBool(v)}}, nil
+	}
+	return nil, []string{fmt.Sprintf("%s: unsupported value %v", field, v)}
+}
+
+// toolConfigChanges returns the imported values that change the effective
+// config, in key order, and every value that fails the checks `acm set`
+// applies.
+func toolConfigChanges(effective AgentConfig, values []importedValue) ([]importedValue, []string) {
+	var changes []importedValue
+	var problems []string
+	for _, v := range values {
+		if oldValue, _ := valueLeafString(&effective, v.Key); oldValue == v.Value {
+			continue
+		}
+		scratch := effective
+		if err := assignValue(&scratch, v.Key, v.Value, false); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", v.Field, errors.Unwrap(err)))
+			continue
+		}
+		oldValue, _ := valueLeafString(&effective, v.Key)
+		newValue, _ := valueLeafString(&scratch, v.Key)
+		if oldValue == newValue {
+			continue
+		}
+		v.Value = newValue
+		changes = append(changes, v)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, problems
+}
+
+func valueLeafString(config *AgentConfig, key string) (string, error) {
+	v, err := lookupField(config, key)
+	if err != nil {
+		return "", err
+	}
+	return leafString(v), nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// editToolConfig applies edit to an exported tool config
+func (e *testEnv) editToolConfig(name string, edit func(tool map[string]interface{})) string {
+	e.t.Helper()
+	path := filepath.Join(e.exportDir(), name+".json")
+	tool := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(e.read(path)), &tool); err != nil {
+		e.t.Fatal(err)
+	}
+	edit(tool)
+	data, _ := json.MarshalIndent(tool, "", "  ")
+	e.write(path, string(data))
+	return path
+}
+
+func TestImportFromToolThreshold(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "agent.name", "import-agent")
+	e.mustRun("export")
+	e.editToolConfig("wallet-monitor", func(tool map[string]interface{}) {
+		tool["alert_threshold"] = 0.25
+	})
+
+	out := e.mustRun("import", "--from-tool", "wallet-monitor")
+	assertContains(t, out, "~ wallet.alert_threshold: 0.1 → 0.25", "Backed up current config to ", "Imported 1 change(s) from wallet-monitor")
+	// the stale wei value loses to the edited plain one
+	assertContains(t, out, "alert_threshold_wei disagrees with alert_threshold; using alert_threshold")
+	assertContains(t, e.mustRun("get", "wallet.alert_threshold"), "0.25")
+	assertContains(t, e.mustRun("get", "agent.name"), "import-agent")
+
+	assertContains(t, e.mustRun("import", "--from-tool", "wallet-monitor"), "No changes to import")
+}
+
+func TestImportFromToolDerivedUnits(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export")
+	path := e.editToolConfig("wallet-monitor", func(tool map[string]interface{}) {
+		delete(tool, "check_interval")
+		delete(tool, "daily_limit")
+		tool["check_interval_seconds"] = 900
+		tool["daily_limit_wei"] = "2000000000000000000"
+		tool["owned_by_the_tool"] = true
+	})
+	copied := filepath.Join(e.home, "edited.json")
+	e.write(copied, e.read(path))
+
+	out := e.mustRun("import", "--from-tool", "wallet-monitor", copied)
+	assertContains(t, out, "Ignoring owned_by_the_tool: not exported from the config")
+	assertContains(t, e.mustRun("get", "monitoring.check_interval_minutes"), "15")
+	assertContains(t, e.mustRun("get", "wallet.daily_limit"), "2")
+}
+
+func TestImportFromToolRejectsBadValues(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export")
+	e.editToolConfig("wallet-monitor", func(tool map[string]interface{}) {
+		tool["address"] = "0x123"
+		tool["check_interval_seconds"] = 90
+		tool["alert_threshold"] = 0.3
+	})
+	before := e.read(e.configPath())
+
+	out := e.mustFail("import", "--from-tool", "wallet-monitor")
+	assertContains(t, out, "address: ", "check_interval_seconds: ", "Found 2 problem(s)", "nothing imported")
+	if e.read(e.configPath()) != before {
+		t.Error("a failed import changed the config")
+	}
+	assertContains(t, e.mustFail("import", "--from-tool", "nope"), "Unknown tool: nope")
+}
+
+func TestSecondsToMinutes(t *testing.T) {
+	if got, err := secondsToMinutes("300"); err != nil || got != "5" {
+		t.Errorf("secondsToMinutes(300) = %s, %v", got, err)
+	}
+	if _, err := secondsToMinutes("90"); err == nil || !strings.Contains(err.Error(), "not a whole number of minutes") {
+		t.Errorf("secondsToMinutes(90) error = %v", err)
+	}
+}
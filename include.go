@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// writeThrough is the global --write-through flag: saves go to the include
+// fragment that sets each changed key instead of the main config
+var writeThrough bool
+
+// includedFragment is one file pulled in by an `include` directive
+type includedFragment struct {
+	path  string
+	raw   map[string]interface{}
+	dirty bool
+}
+
+// resolveIncludes merges the fragments listed in raw's `include` under raw:
+// fragments apply in list order, later ones winning, and the including file
+// wins over all of them. Paths are relative to the including file. It also
+// returns every fragment read, lowest precedence first.
+func resolveIncludes(path string, raw map[string]interface{}, chain []string) (map[string]interface{}, []*includedFragment, error) {
+	list, ok := raw["include"]
+	if !ok {
+		return raw, nil, nil
+	}
+	items, ok := list.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: include must be a list of file names", path)
+	}
+
+	merged := map[string]interface{}{}
+	var fragments []*includedFragment
+	for _, item := range items {
+		name, ok := item.(string)
+		if !ok || name == "" {
+			return nil, nil, fmt.Errorf("%s: include must be a list of file names", path)
+		}
+		fragPath := absPath(includePath(name, filepath.Dir(path)))
+		for _, p := range chain {
+			if p == fragPath {
+				return nil, nil, fmt.Errorf("include cycle: %s", strings.Join(append(chain, fragPath), " → "))
+			}
+		}
+
+		data, err := os.ReadFile(fragPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: include %s: %v", path, name, err)
+		}
+		fragRaw, err := rawConfigMap(fragPath, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", fragPath, err)
+		}
+		if _, ok := fragRaw["extends"]; ok {
+			return nil, nil, fmt.Errorf("%s: extends belongs in the main config, not an included fragment", fragPath)
+		}
+		sub, subFragments, err := resolveIncludes(fragPath, fragRaw, append(chain, fragPath))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		delete(sub, "include")
+		merged = mergeConfigMaps(merged, sub)
+		fragments = append(append(fragments, subFragments...), &includedFragment{path: fragPath, raw: fragRaw})
+	}
+	return mergeConfigMaps(merged, raw), fragments, nil
+}
+
+// includePath resolves an include entry: ~ and absolute paths as given, any
+// other path relative to dir, the including file's directory. expandPath
+// can't be used on its own since it resolves against the working directory.
+func includePath(name, dir string) string {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "~") {
+		return expandPath(name)
+	}
+	return filepath.Join(dir, name)
+}
+
+// owner is the fragment that sets key with the highest precedence, or nil
+// when the main config sets it or nothing does
+func (l *inheritedLayer) owner(key string) *includedFragment {
+	if rawHasKey(l.raw, key) {
+		return nil
+	}
+	for i := len(l.fragments) - 1; i >= 0; i-- {
+		if rawHasKey(l.fragments[i].raw, key) {
+			return l.fragments[i]
+		}
+	}
+	return nil
+}
+
+func rawHasKey(raw map[string]interface{}, key string) bool {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := raw[part].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		raw = next
+	}
+	_, ok := raw[parts[len(parts)-1]]
+	return ok
+}
+
+// writeFragments saves the fragments --write-through changed
+func writeFragments(layer *inheritedLayer, encrypt string) error {
+	for _, fragment := range layer.fragments {
+		if !fragment.dirty {
+			continue
+		}
+		var err error
+		if encrypt != "" {
+			err = encryptRawSecrets(fragment.raw, encrypt)
+		}
+		var data []byte
+		if err == nil {
+			data, err = json.MarshalIndent(fragment.raw, "", "  ")
+		}
+		if err == nil && isYAML(fragment.path) {
+			data, err = jsonToYAML(data)
+		}
+		if err != nil {
+			return &ConfigError{Kind: KindIO, Path: fragment.path, Err: err}
+		}
+		if isJSONC(fragment.path) {
+			if existing, err := os.ReadFile(fragment.path); err == nil {
+				header, _ := splitJSONCHeader(existing)
+				data = append(header, data...)
+			}
+		}
+		if err := ioError(fragment.path, writeFileAtomic(fragment.path, data, 0600)); err != nil {
+			return err
+		}
+		fmt.Printf("📝 Wrote changes to included %s\n", fragment.path)
+		fragment.dirty = false
+	}
+	return nil
+}
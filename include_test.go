@@ -0,0 +1,81 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// includeFragments makes the config include a security and a keys fragment
+func (e *testEnv) includeFragments() (string, string) {
+	e.t.Helper()
+	e.init()
+	dir := filepath.Dir(e.configPath())
+	security := filepath.Join(dir, "security.json")
+	keys := filepath.Join(dir, "keys.json")
+	e.write(security, `{"security": {"honeypot_enabled": false}, "wallet": {"daily_limit": 3}}`)
+	e.write(keys, `{"api_keys": {"etherscan": "frag-etherscan-key-123456"}, "wallet": {"daily_limit": 4}}`)
+	e.editConfig(func(config map[string]interface{}) {
+		config["include"] = []string{"security.json", "keys.json"}
+		delete(section(config, "wallet"), "daily_limit")
+		delete(section(config, "security"), "honeypot_enabled")
+	})
+	return security, keys
+}
+
+func TestIncludeTwoFragments(t *testing.T) {
+	e := newTestEnv(t)
+	e.includeFragments()
+
+	assertContains(t, e.mustRun("get", "security.honeypot_enabled"), "false")
+	e.mustRun("get", "--exists", "api_keys.etherscan")
+	// later fragments win over earlier ones
+	assertContains(t, e.mustRun("get", "wallet.daily_limit"), "4")
+
+	// and the including file wins over all of them
+	e.editConfig(func(config map[string]interface{}) {
+		section(config, "wallet")["daily_limit"] = 5
+	})
+	assertContains(t, e.mustRun("get", "wallet.daily_limit"), "5")
+	assertNotContains(t, e.mustRun("export"), "include")
+}
+
+func TestIncludeCycle(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	dir := filepath.Dir(e.configPath())
+	e.write(filepath.Join(dir, "a.json"), `{"include": ["b.json"]}`)
+	e.write(filepath.Join(dir, "b.json"), `{"include": ["a.json"]}`)
+	e.editConfig(func(config map[string]interface{}) {
+		config["include"] = []string{"a.json"}
+	})
+
+	out := e.mustFail("get", "agent.name")
+	assertContains(t, out, "include cycle: ", filepath.Join(dir, "a.json")+" → "+filepath.Join(dir, "b.json")+" → "+filepath.Join(dir, "a.json"))
+
+	e.editConfig(func(config map[string]interface{}) {
+		config["include"] = []string{"config.json"}
+	})
+	assertContains(t, e.mustFail("get", "agent.name"), "include cycle: ")
+
+	e.editConfig(func(config map[string]interface{}) {
+		config["include"] = []string{"missing.json"}
+	})
+	assertContains(t, e.mustFail("get", "agent.name"), "include missing.json")
+}
+
+func TestIncludeWriteThrough(t *testing.T) {
+	e := newTestEnv(t)
+	security, keys := e.includeFragments()
+
+	// saves go to the main config by default
+	e.mustRun("set", "security.honeypot_enabled", "true")
+	assertContains(t, e.read(e.configPath()), `"honeypot_enabled": true`)
+	assertContains(t, e.read(security), `"honeypot_enabled": false`)
+
+	out := e.mustRun("--write-through", "set", "wallet.daily_limit", "6")
+	assertContains(t, out, "Wrote changes to included "+keys)
+	assertContains(t, e.read(keys), `"daily_limit": 6`)
+	assertContains(t, e.read(security), `"daily_limit": 3`)
+	assertNotContains(t, e.read(e.configPath()), `"daily_limit"`)
+	assertContains(t, e.mustRun("get", "wallet.daily_limit"), "6")
+}
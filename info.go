@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// diagnosticInfo is the bug-report bundle printed by `acm info`. It never
+// holds secret values, only where each secret comes from.
+type diagnosticInfo struct {
+	Version      string `json:"version"`
+	GoVersion    string `json:"go_version"`
+	OS           string `json:"os"`
+	Arch         string `json:"arch"`
+	ConfigPath   string `json:"config_path"`
+	ConfigExists bool   `json:"config_exists"`
+	Profile      string `json:"profile"`
+	ExportDir    string `json:"export_dir"`
+	StateDir     string `json:"state_dir"`
+	Extends      string `json:"extends,omitempty"`
+	Encrypted    bool   `json:"encrypted"`
+	Signed       bool   `json:"signed"` // not supported yet, always false
+	// Provenance details each secret: the file, env var or ${VAR}
+	// reference it came from, and its value masked
+	Provenance map[string]infoSecret `json:"provenance"`
+}
+
+// infoSecret is a secret's source as `acm keys provenance` reports it, with
+// the value as --mask-style shows it
+type infoSecret struct {
+	secretSource
+	Masked string `json:"masked"`
+}
+
+// infoCommand prints the environment and effective settings for bug reports
+func infoCommand(args []string) {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print as JSON")
+	parseFlags(fs, args)
+
+	info := collectInfo()
+	if *asJSON {
+		out, _ := json.MarshalIndent(info, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	fmt.Printf("agent-config-manager v%s (%s, %s/%s)\n", info.Version, info.GoVersion, info.OS, info.Arch)
+	fmt.Println()
+	configStatus := "✅ found"
+	if !info.ConfigExists {
+		configStatus = "❌ missing"
+	}
+	fmt.Printf("  %-12s %s (%s)\n", "Config:", info.ConfigPath, configStatus)
+	fmt.Printf("  %-12s %s\n", "Profile:", info.Profile)
+	if info.Extends != "" {
+		fmt.Printf("  %-12s %s\n", "Extends:", info.Extends)
+	}
+	fmt.Printf("  %-12s %s\n", "Exports:", info.ExportDir)
+	fmt.Printf("  %-12s %s\n", "State:", info.StateDir)
+	fmt.Printf("  %-12s %s\n", "Encrypted:", yesNo(info.Encrypted))
+	fmt.Printf("  %-12s %s\n", "Signed:", yesNo(info.Signed))
+
+	fmt.Println()
+	fmt.Println("Secrets:")
+	keys := make([]string, 0, len(info.Provenance))
+	for key := range info.Provenance {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Println(strings.TrimRight(fmt.Sprintf("  %-22s %-14s %s", key, info.Provenance[key].Masked, describeSource(info.Provenance[key].secretSource)), " "))
+	}
+}
+
+func collectInfo() diagnosticInfo {
+	info := diagnosticInfo{
+		Version:    version,
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		ConfigPath: getConfigPath(),
+		Profile:    activeProfile(),
+		ExportDir:  getExportDir(),
+		StateDir:   stateDir(),
+		Provenance: map[string]infoSecret{},
+	}
+	if explicitConfigPath() != "" {
+		info.Profile = "(none, --config or ACM_CONFIG)"
+	}
+
+	if _, err := os.Stat(info.ConfigPath); err == nil {
+		info.ConfigExists = true
+		config, sources, err := secretProvenance(info.ConfigPath)
+		if err != nil {
+			exitWith(err, "❌ Invalid config: %v\n", errors.Unwrap(err))
+		}
+		info.Extends = config.Extends
+		info.Encrypted = encryptedConfigs[info.ConfigPath] != ""
+		for key, source := range sources {
+			info.Provenance[key] = infoSecret{source, maskSecret(apiKeyValue(config, key))}
+		}
+	} else {
+		// Without a file, only the environment can provide secrets
+		var config AgentConfig
+		for key, source := range resolveSecrets(&config) {
+			info.Provenance[key] = infoSecret{source, maskSecret(apiKeyValue(config, key))}
+		}
+	}
+	return info
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+)
+
+func TestInfoFields(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.setenv("BASESCAN_API_KEY", "basescan-from-env-123456")
+
+	out := e.mustRun("info")
+	assertContains(t, out,
+		"agent-config-manager v"+version,
+		runtime.GOOS+"/"+runtime.GOARCH,
+		e.configPath()+" (✅ found)",
+		"Profile:     default",
+		"Encrypted:   no",
+		"api_keys.etherscan",
+		"BASESCAN_API_KEY",
+	)
+	assertNotContains(t, out, "etherscan-secret-value-123", "basescan-from-env-123456")
+}
+
+func TestInfoJSON(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.mustRun("keys", "encrypt")
+	e.setenv("BASESCAN_API_KEY", "basescan-from-env-123456")
+
+	out := e.mustRun("info", "--json")
+	assertNotContains(t, out, "etherscan-secret-value-123", "basescan-from-env-123456")
+	var info diagnosticInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, out)
+	}
+	if info.Version != version || info.ConfigPath != e.configPath() || !info.ConfigExists || info.Profile != "default" {
+		t.Errorf("info = %+v", info)
+	}
+	if !info.Encrypted {
+		t.Error("encrypted config reported as plaintext")
+	}
+	if info.Provenance["api_keys.etherscan"].Provider != "file" || info.Provenance["api_keys.basescan"].Provider != "env" {
+		t.Errorf("provenance = %+v", info.Provenance)
+	}
+	if info.Provenance["api_keys.etherscan"].Masked != maskSecret("etherscan-secret-value-123") {
+		t.Errorf("masked etherscan = %q", info.Provenance["api_keys.etherscan"].Masked)
+	}
+	// one object per secret, with no older per-key maps beside it
+	var raw map[string]json.RawMessage
+	json.Unmarshal([]byte(out), &raw)
+	for _, old := range []string{"secrets", "masked"} {
+		if _, ok := raw[old]; ok {
+			t.Errorf("info --json still has %s", old)
+		}
+	}
+	var provenance map[string]map[string]interface{}
+	json.Unmarshal(raw["provenance"], &provenance)
+	if basescan := provenance["api_keys.basescan"]; basescan["env_var"] != "BASESCAN_API_KEY" || basescan["masked"] != maskSecret("basescan-from-env-123456") {
+		t.Errorf("provenance api_keys.basescan = %v", basescan)
+	}
+}
+
+func TestInfoWithoutConfig(t *testing.T) {
+	e := newTestEnv(t)
+	out := e.mustRun("info")
+	assertContains(t, out, "(❌ missing)")
+}
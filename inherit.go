@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// inheritedLayer remembers how a config that extends a base profile or
+// includes fragments looked on disk and after resolution, so saving writes
+// only what changed back to the child file and everything else keeps being
+// inherited
+type inheritedLayer struct {
+	raw      map[string]interface{}
+	resolved AgentConfig
+	// complete is set when raw is a whole config that only has local
+	// overrides on top; it is written back in field order
+	complete bool
+	// fragments are the included files, lowest precedence first
+	fragments []*includedFragment
+}
+
+var inheritedLayers = map[string]*inheritedLayer{}
+
+// resolveInheritance loads the config at path with its includes and its
+// `extends` chain applied: fields absent from the child and its fragments
+// come from the base profile. Base profile secrets are only inherited when
+// the child sets inherit_secrets.
+func resolveInheritance(path string, data []byte) (AgentConfig, error) {
+	raw, err := rawConfigMap(path, data)
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	own, fragments, err := resolveIncludes(path, raw, []string{absPath(path)})
+	if err != nil {
+		return AgentConfig{}, err
+	}
+
+	merged, err := resolveExtends(own, []inheritLink{{name: profileNameFor(path), path: absPath(path)}})
+	if err != nil {
+		return AgentConfig{}, err
+	}
+
+	data, err = json.Marshal(merged)
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	config, err := parseConfig("", data)
+	if err != nil {
+		return AgentConfig{}, err
+	}
+
+	inheritedLayers[path] = &inheritedLayer{raw: raw, resolved: config, fragments: fragments}
+	return config, nil
+}
+
+// inheritLink is one config in an extends chain
+type inheritLink struct {
+	name string
+	path string
+}
+
+func resolveExtends(raw map[string]interface{}, chain []inheritLink) (map[string]interface{}, error) {
+	base, _ := raw["extends"].(string)
+	if base == "" {
+		return raw, nil
+	}
+	if !profileNamePattern.MatchString(base) {
+		return nil, fmt.Errorf("invalid profile name in extends: %s", base)
+	}
+
+	basePath := profileConfigPath(base)
+	for _, link := range chain {
+		if link.path == absPath(basePath) {
+			names := make([]string, 0, len(chain)+1)
+			for _, l := range chain {
+				names = append(names, l.name)
+			}
+			return nil, fmt.Errorf("inheritance cycle: %s", strings.Join(append(names, base), " → "))
+		}
+	}
+
+	data, err := os.ReadFile(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("base profile %s not found", base)
+	}
+	baseRaw, err := rawConfigMap(basePath, data)
+	if err == nil {
+		baseRaw, _, err = resolveIncludes(basePath, baseRaw, []string{absPath(basePath)})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("base profile %s: %v", base, err)
+	}
+	baseMerged, err := resolveExtends(baseRaw, append(chain, inheritLink{name: base, path: absPath(basePath)}))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(baseMerged, "extends")
+	delete(baseMerged, "inherit_secrets")
+	delete(baseMerged, "include")
+	if inherit, _ := raw["inherit_secrets"].(bool); !inherit {
+		delete(baseMerged, "api_keys")
+	}
+	return mergeConfigMaps(baseMerged, raw), nil
+}
+
+// mergeConfigMaps overlays top onto base: objects merge key by key, any
+// other value in top replaces the base value
+func mergeConfigMaps(base, top map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range top {
+		baseObj, baseIsObj := merged[k].(map[string]interface{})
+		topObj, topIsObj := v.(map[string]interface{})
+		if baseIsObj && topIsObj {
+			merged[k] = mergeConfigMaps(baseObj, topObj)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+func rawConfigMap(path string, data []byte) (map[string]interface{}, error) {
+	if err := checkConfigSize(int64(len(data))); err != nil {
+		return nil, err
+	}
+	data, err := normalizeEncoding(path, data)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = configJSON(path, data); err != nil {
+		return nil, err
+	}
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	applyFieldAliases(path, raw)
+	return raw, nil
+}
+
+// patch writes every leaf of config that differs from the resolved view
+// into the child's own JSON object and returns it. With --write-through a
+// leaf set by an included fragment goes to that fragment instead.
+func (l *inheritedLayer) patch(config AgentConfig) map[string]interface{} {
+	for _, leaf := range configLeaves(&config) {
+		old, err := lookupField(&l.resolved, leaf.Key)
+		if err == nil && leafString(old) == leafString(leaf.Value) {
+			continue
+		}
+		target := l.raw
+		if fragment := l.owner(leaf.Key); writeThrough && fragment != nil {
+			target, fragment.dirty = fragment.raw, true
+		}
+		setRawValue(target, leaf.Key, leaf.Value.Interface())
+	}
+	l.resolved = config
+	return l.raw
+}
+
+func setRawValue(raw map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := raw[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			raw[part] = next
+		}
+		raw = next
+	}
+	raw[parts[len(parts)-1]] = value
+}
+
+// profileNameFor names the profile stored at path, or returns the path
+// itself for configs outside the profile layout
+func profileNameFor(path string) string {
+	for _, name := range listProfiles() {
+		if absPath(profileConfigPath(name)) == absPath(path) {
+			return name
+		}
+	}
+	return path
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func (e *testEnv) profilePath(name string) string {
+	return filepath.Join(e.home, ".config", "agent", "profiles", name, "config.json")
+}
+
+func TestExtendsResolvesFromBase(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.mustRun("profile", "create", "testnet", "--extends", "default")
+
+	e.mustRun("--profile", "testnet", "set", "wallet.daily_limit", "0.1")
+	assertContains(t, e.mustRun("--profile", "testnet", "get", "wallet.daily_limit"), "0.1")
+	assertContains(t, e.mustRun("--profile", "testnet", "get", "agent.name"), "Arithmos")
+	assertContains(t, e.mustRun("get", "wallet.daily_limit"), "0.5")
+
+	// set writes only the changed field to the child
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(e.read(e.profilePath("testnet"))), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw["extends"] != "default" || len(section(raw, "wallet")) != 1 {
+		t.Errorf("child config = %v, want extends and wallet.daily_limit only", raw)
+	}
+	if _, ok := raw["agent"]; ok {
+		t.Errorf("child config copied inherited fields: %v", raw)
+	}
+
+	// a base value changed later shows through
+	e.mustRun("set", "agent.name", "Renamed")
+	assertContains(t, e.mustRun("--profile", "testnet", "show"), "Renamed")
+}
+
+func TestExtendsSecretsOptIn(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	e.mustRun("profile", "create", "testnet", "--extends", "default")
+
+	if _, code := e.run("--profile", "testnet", "get", "api_keys.etherscan", "--exists"); code != 1 {
+		t.Errorf("base secret inherited without inherit_secrets (exit %d)", code)
+	}
+
+	e.write(e.profilePath("testnet"), `{"extends": "default", "inherit_secrets": true}`)
+	e.mustRun("--profile", "testnet", "get", "api_keys.etherscan", "--exists")
+}
+
+func TestExtendsCycle(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "testnet", "--extends", "default")
+	e.mustRun("profile", "create", "staging", "--extends", "testnet")
+	e.editConfig(func(config map[string]interface{}) {
+		config["extends"] = "staging"
+	})
+
+	out := e.mustFail("--profile", "testnet", "show")
+	assertContains(t, out, "inheritance cycle: testnet → default → staging → testnet")
+
+	e.write(e.profilePath("staging"), `{"extends": "missing"}`)
+	assertContains(t, e.mustFail("--profile", "staging", "show"), "base profile missing not found")
+}
+
+func TestExtendsNotExported(t *testing.T) {
+	config := defaultConfig()
+	config.Extends, config.InheritSecrets, config.Include = "default", true, []string{"fragment.json"}
+	for _, format := range []string{"consul-kv", "env", "ansible-vars"} {
+		files, err := exportFormats[format](config, exportOptions{KVPrefix: "agent", Secrets: "inline"})
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		for _, file := range files {
+			for _, name := range []string{"extends", "inherit_secrets", "include"} {
+				if strings.Contains(strings.ToLower(string(file.Data)), name) {
+					t.Errorf("%s %s contains %s:\n%s", format, file.Name, name, file.Data)
+				}
+			}
+		}
+	}
+	out := newTestEnv(t).mustRun("env-map")
+	assertNotContains(t, out, "extends", "inherit_secrets", "include")
+}
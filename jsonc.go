@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// allowComments is set by the global --comments flag to accept JSON with
+// comments (JWCC) regardless of the file extension
+var allowComments bool
+
+// isJSONC reports whether path should be read as JSON with comments
+func isJSONC(path string) bool {
+	return !isYAML(path) && (allowComments || strings.HasSuffix(path, ".jsonc"))
+}
+
+// stripJSONComments removes // and /* */ comments and trailing commas so the
+// result can be passed to encoding/json. Comments are replaced by spaces and
+// newlines are kept, so parse errors still point at the right line.
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				out = append(out, ' ')
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			out = append(out, ' ', ' ')
+			i += 2
+			for i < len(data) && !(data[i] == '*' && i+1 < len(data) && data[i+1] == '/') {
+				if data[i] == '\n' {
+					out = append(out, '\n')
+				} else {
+					out = append(out, ' ')
+				}
+				i++
+			}
+			out = append(out, ' ', ' ')
+			i++
+		default:
+			out = append(out, c)
+		}
+	}
+	return stripTrailingCommas(out)
+}
+
+// stripTrailingCommas blanks commas that directly precede a closing } or ]
+func stripTrailingCommas(data []byte) []byte {
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+			continue
+		}
+		if c != ',' {
+			continue
+		}
+		j := i + 1
+		for j < len(data) && (data[j] == ' ' || data[j] == '\t' || data[j] == '\n' || data[j] == '\r') {
+			j++
+		}
+		if j < len(data) && (data[j] == '}' || data[j] == ']') {
+			data[i] = ' '
+		}
+	}
+	return data
+}
+
+// splitJSONCHeader separates the comment block before the opening brace
+// from the body, and reports whether the body itself contains comments
+func splitJSONCHeader(data []byte) (header []byte, bodyHasComments bool) {
+	start := bytes.IndexByte(data, '{')
+	if start < 0 {
+		return nil, false
+	}
+	body := data[start:]
+	return data[:start], !bytes.Equal(bytes.TrimSpace(stripJSONComments(append([]byte(nil), body...))), bytes.TrimSpace(body))
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+const commentedConfig = `// Agent config for the staging box
+/* shared with the ops team */
+{
+  "version": "0.1.0",
+  "agent": {
+    "name": "Arithmos", // display name
+    "id": "arithmos-quillsworth",
+    "website": "https://arithmos.dev/*not-a-comment*/",
+  },
+  "wallet": {
+    /* the hot wallet */
+    "address": "0x120e011fB8a12bfcB61e5c1d751C26A5D33Aae91",
+    "networks": ["ethereum", "base",],
+    "daily_limit": 0.5,
+  },
+}
+`
+
+func TestStripJSONComments(t *testing.T) {
+	var config AgentConfig
+	if err := json.Unmarshal(stripJSONComments([]byte(commentedConfig)), &config); err != nil {
+		t.Fatalf("stripped config doesn't parse: %v", err)
+	}
+	if config.Agent.Name != "Arithmos" || config.Wallet.DailyLimit != 0.5 || len(config.Wallet.Networks) != 2 {
+		t.Errorf("config = %+v", config)
+	}
+	if config.Agent.Website != "https://arithmos.dev/*not-a-comment*/" {
+		t.Errorf("comment markers inside a string were stripped: %q", config.Agent.Website)
+	}
+}
+
+func TestStripJSONCommentsKeepsLines(t *testing.T) {
+	in := []byte("{\n  // one\n  /* two\n  three */\n  \"a\": 1\n}")
+	out := stripJSONComments(in)
+	if len(out) != len(in) {
+		t.Errorf("length changed: %d → %d", len(in), len(out))
+	}
+	for i := range in {
+		if in[i] == '\n' && out[i] != '\n' {
+			t.Fatalf("newline at %d was removed", i)
+		}
+	}
+}
+
+func TestCommentedConfigLoads(t *testing.T) {
+	e := newTestEnv(t)
+	path := filepath.Join(e.home, "agent.jsonc")
+	e.write(path, commentedConfig)
+
+	if got := e.mustRun("--config", path, "get", "wallet.daily_limit"); got != "0.5\n" {
+		t.Errorf("get wallet.daily_limit = %q", got)
+	}
+	e.mustRun("--config", path, "set", "agent.name", "Quill")
+	saved := e.read(path)
+	assertContains(t, saved, "// Agent config for the staging box", "/* shared with the ops team */", `"name": "Quill"`)
+
+	// A .json file only accepts comments with --comments
+	plain := filepath.Join(e.home, "agent.json")
+	e.write(plain, commentedConfig)
+	e.mustFail("--config", plain, "get", "agent.name")
+	if got := e.mustRun("--comments", "--config", plain, "get", "agent.name"); got != "Arithmos\n" {
+		t.Errorf("get agent.name with --comments = %q", got)
+	}
+}
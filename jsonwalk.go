@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// jsonToken is one token of a JSON document with its dotted path and its
+// byte range in the input
+type jsonToken struct {
+	Path  string // dotted path, with [i] for array elements
+	IsKey bool   // an object key rather than a value
+	Token json.Token
+	Start int
+	End   int
+}
+
+// walkJSON calls visit for every key and value in data, in document order.
+// Offsets index into data, so callers can rewrite values in place; JSONC
+// input must first go through stripJSONComments, which keeps offsets.
+// It stops at the first syntax error, leaving that to the real parse.
+func walkJSON(data []byte, visit func(t jsonToken)) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	// Each open object or array, and for arrays the next element index
+	type frame struct {
+		path    string
+		isArray bool
+		index   int
+	}
+	var stack []*frame
+	expectKey := false
+	var pending string // path of the value about to be read
+
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return
+		}
+		end := int(dec.InputOffset())
+		// The offset before a token may still sit on a separator
+		for start < end && bytes.IndexByte([]byte(" \t\r\n,:"), data[start]) >= 0 {
+			start++
+		}
+
+		var top *frame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if top != nil && !top.isArray && expectKey {
+			if key, ok := tok.(string); ok {
+				pending = key
+				if top.path != "" {
+					pending = top.path + "." + key
+				}
+				visit(jsonToken{Path: pending, IsKey: true, Token: key, Start: start, End: end})
+				expectKey = false
+				continue
+			}
+		}
+		if top != nil && top.isArray && tok != json.Delim(']') {
+			pending = top.path + "[" + strconv.Itoa(top.index) + "]"
+			top.index++
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, &frame{path: pending})
+			expectKey = true
+		case json.Delim('['):
+			stack = append(stack, &frame{path: pending, isArray: true})
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			expectKey = len(stack) > 0 && !stack[len(stack)-1].isArray
+		default:
+			visit(jsonToken{Path: pending, Token: tok, Start: start, End: end})
+			expectKey = top != nil && !top.isArray
+		}
+	}
+}
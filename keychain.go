@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// keychainService and keychainAccount name the OS keychain entry holding
+// the encryption key
+const (
+	keychainService = "agent-config-manager"
+	keychainAccount = "secret-key"
+)
+
+var (
+	errKeyringUnavailable = errors.New("no OS keychain available")
+	errKeyringNotFound    = errors.New("not found in the OS keychain")
+)
+
+// keyring stores secrets by service and account: the macOS Keychain,
+// Windows Credential Manager or libsecret, depending on the platform
+type keyring interface {
+	Get(service, account string) (string, error)
+	Set(service, account, secret string) error
+	Delete(service, account string) error
+}
+
+// systemKeyring is the keychain loadSecretKey consults; ACM_NO_KEYCHAIN=1
+// turns it off
+var systemKeyring = func() keyring {
+	if os.Getenv("ACM_NO_KEYCHAIN") == "1" {
+		return unavailableKeyring{}
+	}
+	return platformKeyring()
+}
+
+// unavailableKeyring is used where no keychain can be reached, so every
+// lookup falls back to the key file
+type unavailableKeyring struct{}
+
+func (unavailableKeyring) Get(string, string) (string, error) { return "", errKeyringUnavailable }
+func (unavailableKeyring) Set(string, string, string) error   { return errKeyringUnavailable }
+func (unavailableKeyring) Delete(string, string) error        { return errKeyringUnavailable }
+
+// keychainSecretKey returns the encoded encryption key stored in the
+// keychain, or "" when there is none or no keychain is reachable
+func keychainSecretKey() string {
+	encoded, err := systemKeyring().Get(keychainService, keychainAccount)
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
+
+// keychainCommand handles `acm keychain store|remove`
+func keychainCommand(args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: acm keychain store [--remove-file] | acm keychain remove")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "store":
+		keychainStore(args[1:])
+	case "remove":
+		keychainRemove()
+	default:
+		fmt.Printf("❌ Unknown keychain command: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// keychainStore copies the encryption key into the OS keychain, optionally
+// deleting the key file once the keychain copy reads back
+func keychainStore(args []string) {
+	fs := flag.NewFlagSet("keychain store", flag.ExitOnError)
+	removeFile := fs.Bool("remove-file", false, "delete secret.key once the key is in the keychain")
+	parseFlags(fs, args)
+
+	key, err := loadSecretKey()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Println("   Create one with: acm keys encrypt")
+		os.Exit(1)
+	}
+	encoded := base64.StdEncoding.EncodeToString(key)
+	ring := systemKeyring()
+	if err := ring.Set(keychainService, keychainAccount, encoded); err != nil {
+		fmt.Printf("❌ Failed to store the key in the keychain: %v\n", err)
+		os.Exit(1)
+	}
+	if stored, err := ring.Get(keychainService, keychainAccount); err != nil || stored != encoded {
+		fmt.Println("❌ The keychain did not return the stored key; keeping the key file")
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Stored the encryption key in the OS keychain (service %s)\n", keychainService)
+
+	if !*removeFile {
+		return
+	}
+	if err := os.Remove(secretKeyPath()); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("❌ Failed to remove %s: %v\n", secretKeyPath(), err)
+		os.Exit(1)
+	}
+	fmt.Printf("🗑️  Removed %s\n", secretKeyPath())
+}
+
+// keychainRemove deletes the keychain entry, writing the key back to the key
+// file first so encrypted values stay readable
+func keychainRemove() {
+	ring := systemKeyring()
+	encoded, err := ring.Get(keychainService, keychainAccount)
+	if err != nil {
+		fmt.Printf("❌ No encryption key to remove: %v\n", err)
+		os.Exit(1)
+	}
+
+	if _, err := os.Stat(secretKeyPath()); os.IsNotExist(err) {
+		if err := writeFileAtomic(secretKeyPath(), []byte(encoded+"\n"), 0600); err != nil {
+			fmt.Printf("❌ Failed to write %s: %v\n", secretKeyPath(), err)
+			os.Exit(1)
+		}
+		fmt.Printf("🔑 Wrote the key back to %s\n", secretKeyPath())
+	}
+	if err := ring.Delete(keychainService, keychainAccount); err != nil {
+		fmt.Printf("❌ Failed to remove the key from the keychain: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Removed the encryption key from the OS keychain")
+}
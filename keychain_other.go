@@ -0,0 +1,90 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// platformKeyring drives the keychain's command-line tool: security(1) on
+// macOS, secret-tool(1) from libsecret elsewhere
+func platformKeyring() keyring {
+	tool := "secret-tool"
+	if runtime.GOOS == "darwin" {
+		tool = "security"
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return unavailableKeyring{}
+	}
+	if tool == "security" {
+		return macKeychain{}
+	}
+	return secretToolKeyring{}
+}
+
+// macKeychain stores generic passwords in the login keychain. security(1)
+// only takes the secret as an argument, so it is briefly visible to ps.
+type macKeychain struct{}
+
+func (macKeychain) Get(service, account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		return "", keyringError(err, 44)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (macKeychain) Set(service, account, secret string) error {
+	return keyringError(exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", account, "-w", secret).Run(), 44)
+}
+
+func (macKeychain) Delete(service, account string) error {
+	return keyringError(exec.Command("security", "delete-generic-password", "-s", service, "-a", account).Run(), 44)
+}
+
+// secretToolKeyring stores secrets through libsecret (GNOME Keyring, KWallet)
+type secretToolKeyring struct{}
+
+func (secretToolKeyring) Get(service, account string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output()
+	if err != nil {
+		return "", keyringError(err, 1)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (secretToolKeyring) Set(service, account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", service, "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return errKeyringUnavailable
+	}
+	return nil
+}
+
+func (secretToolKeyring) Delete(service, account string) error {
+	return keyringError(exec.Command("secret-tool", "clear", "service", service, "account", account).Run(), 1)
+}
+
+// keyringError maps a tool's exit status to errKeyringNotFound when it is
+// the tool's "no such item" code, and to errKeyringUnavailable otherwise
+// (no keychain daemon, locked keychain, ...)
+func keyringError(err error, notFound int) error {
+	var exit *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exit) && exit.ExitCode() == notFound:
+		return errKeyringNotFound
+	}
+	return errKeyringUnavailable
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+// memoryKeyring is a keyring kept in a map
+type memoryKeyring map[string]string
+
+func (k memoryKeyring) Get(service, account string) (string, error) {
+	secret, ok := k[service+"/"+account]
+	if !ok {
+		return "", errKeyringNotFound
+	}
+	return secret, nil
+}
+
+func (k memoryKeyring) Set(service, account, secret string) error {
+	k[service+"/"+account] = secret
+	return nil
+}
+
+func (k memoryKeyring) Delete(service, account string) error {
+	if _, ok := k[service+"/"+account]; !ok {
+		return errKeyringNotFound
+	}
+	delete(k, service+"/"+account)
+	return nil
+}
+
+// useKeyring makes ring the OS keychain, with no key in the environment or
+// cached
+func useKeyring(t *testing.T, ring keyring) {
+	t.Helper()
+	isolatePaths(t)
+	t.Setenv("ACM_SECRET_KEY", "")
+	saved, savedKey := systemKeyring, cachedSecretKey
+	systemKeyring, cachedSecretKey = func() keyring { return ring }, nil
+	t.Cleanup(func() { systemKeyring, cachedSecretKey = saved, savedKey })
+}
+
+func TestKeychainStoreAndRetrieve(t *testing.T) {
+	ring := memoryKeyring{}
+	useKeyring(t, ring)
+	if err := createSecretKey(); err != nil {
+		t.Fatal(err)
+	}
+	key, err := loadSecretKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() { keychainStore([]string{"--remove-file"}) })
+	assertContains(t, out, "Stored the encryption key in the OS keychain", "Removed "+secretKeyPath())
+	if _, err := os.Stat(secretKeyPath()); !os.IsNotExist(err) {
+		t.Errorf("secret.key is still there: %v", err)
+	}
+
+	cachedSecretKey = nil
+	got, err := loadSecretKey()
+	if err != nil || !bytes.Equal(got, key) {
+		t.Errorf("key from the keychain = %x, %v; want %x", got, err, key)
+	}
+}
+
+func TestKeychainRemoveWritesFileBack(t *testing.T) {
+	ring := memoryKeyring{}
+	useKeyring(t, ring)
+	if err := createSecretKey(); err != nil {
+		t.Fatal(err)
+	}
+	key, _ := loadSecretKey()
+	captureStdout(t, func() { keychainStore([]string{"--remove-file"}) })
+
+	out := captureStdout(t, func() { keychainRemove() })
+	assertContains(t, out, "Wrote the key back to "+secretKeyPath(), "Removed the encryption key from the OS keychain")
+	if len(ring) != 0 {
+		t.Errorf("keychain still holds %v", ring)
+	}
+	cachedSecretKey = nil
+	if got, err := loadSecretKey(); err != nil || !bytes.Equal(got, key) {
+		t.Errorf("key from the file = %x, %v; want %x", got, err, key)
+	}
+}
+
+func TestKeychainMissFallsBack(t *testing.T) {
+	for name, ring := range map[string]keyring{
+		"empty keychain": memoryKeyring{},
+		"no keychain":    unavailableKeyring{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			useKeyring(t, ring)
+			if _, err := loadSecretKey(); !errors.Is(err, errNoSecretKey) {
+				t.Errorf("without a key file: %v, want errNoSecretKey", err)
+			}
+			if err := createSecretKey(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadSecretKey(); err != nil {
+				t.Errorf("with a key file: %v", err)
+			}
+		})
+	}
+}
+
+func TestKeychainDisabled(t *testing.T) {
+	isolatePaths(t)
+	t.Setenv("ACM_NO_KEYCHAIN", "1")
+	if _, err := systemKeyring().Get(keychainService, keychainAccount); !errors.Is(err, errKeyringUnavailable) {
+		t.Errorf("ACM_NO_KEYCHAIN=1 keychain lookup = %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// apiKeyEnvVars maps well-known environment variable names to the api_keys
+// field they configure
+var apiKeyEnvVars = []struct {
+	Key   string
+	Names []string
+	Set   func(*APIKeysConfig, string)
+}{
+	{"api_keys.etherscan", []string{"ETHERSCAN_API_KEY", "ETHERSCAN_KEY"}, func(k *APIKeysConfig, v string) { k.Etherscan = v }},
+	{"api_keys.basescan", []string{"BASESCAN_API_KEY", "BASESCAN_KEY"}, func(k *APIKeysConfig, v string) { k.Basescan = v }},
+	{"api_keys.openai", []string{"OPENAI_API_KEY"}, func(k *APIKeysConfig, v string) { k.OpenAI = v }},
+	{"api_keys.anthropic", []string{"ANTHROPIC_API_KEY"}, func(k *APIKeysConfig, v string) { k.Anthropic = v }},
+	{"api_keys.discord", []string{"DISCORD_TOKEN", "DISCORD_BOT_TOKEN", "DISCORD_API_KEY"}, func(k *APIKeysConfig, v string) { k.Discord = v }},
+}
+
+// keysCommand handles `acm keys <subcommand>`
+func keysCommand(args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: acm keys import <file> | acm keys encrypt | acm keys decrypt | acm keys check-expiry | acm keys provenance")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "import":
+		if len(args) < 2 {
+			fmt.Println("Usage: acm keys import <file>")
+			os.Exit(1)
+		}
+		importKeys(expandPath(args[1]))
+	case "encrypt":
+		encryptKeysCommand()
+	case "decrypt":
+		decryptKeysCommand()
+	case "check-expiry":
+		checkExpiryCommand(args[1:])
+	case "provenance":
+		keysProvenanceCommand(args[1:])
+	default:
+		fmt.Printf("❌ Unknown keys command: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// importKeys reads KEY=VALUE lines from a .env file or shell profile and sets
+// every recognized API key in a single write. Values are never printed.
+func importKeys(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("❌ Failed to open %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+		if err := checkConfigSize(info.Size()); err != nil {
+			fmt.Printf("❌ %s: %v\n", path, err)
+			os.Exit(1)
+		}
+	}
+
+	config := loadConfig()
+	imported := 0
+	overwrites := 0
+
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := parseEnvLine(line)
+		if !ok {
+			fmt.Printf("⚠️  Line %d: not a KEY=VALUE assignment, skipped\n", lineNo)
+			continue
+		}
+
+		if value == "" {
+			fmt.Printf("⚠️  Line %d: %s is empty, skipped\n", lineNo, name)
+			continue
+		}
+
+		matched := false
+		for _, env := range apiKeyEnvVars {
+			for _, candidate := range env.Names {
+				if name == candidate {
+					if current := apiKeyValue(config, env.Key); current != "" && current != value {
+						overwrites++
+					}
+					env.Set(&config.APIKeys, value)
+					fmt.Printf("✅ %s ← %s\n", env.Key, name)
+					warnPlaceholderSecret(env.Key, value)
+					imported++
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			fmt.Printf("⚠️  Line %d: %s is not a recognized key, skipped\n", lineNo, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Printf("❌ Failed to read %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	if imported == 0 {
+		fmt.Println("❌ No recognized API keys found")
+		os.Exit(1)
+	}
+
+	if overwrites > 0 {
+		confirmOrExit(fmt.Sprintf("Overwrite %d existing key(s)?", overwrites))
+	}
+
+	saveConfig(config)
+	fmt.Println()
+	fmt.Printf("Imported %d key(s)\n", imported)
+}
+
+// apiKeyValue returns the current value of an api_keys.* field
+func apiKeyValue(config AgentConfig, key string) string {
+	v, err := lookupField(&config, key)
+	if err != nil {
+		return ""
+	}
+	return v.String()
+}
+
+// parseEnvLine splits a `KEY=VALUE` or `export KEY=VALUE` line, removing
+// surrounding quotes and trailing comments from the value
+func parseEnvLine(line string) (string, string, bool) {
+	line = strings.TrimPrefix(line, "export ")
+	name, value, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", false
+	}
+
+	name = strings.TrimSpace(name)
+	if !isEnvName(name) {
+		return "", "", false
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		end := strings.IndexByte(value[1:], value[0])
+		if end < 0 {
+			return "", "", false
+		}
+		value = value[1 : end+1]
+	} else if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+
+	return name, value, true
+}
+
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
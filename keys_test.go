@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseEnvLine(t *testing.T) {
+	tests := []struct {
+		line        string
+		name, value string
+		ok          bool
+	}{
+		{"ETHERSCAN_API_KEY=abc123", "ETHERSCAN_API_KEY", "abc123", true},
+		{"export OPENAI_API_KEY=sk-1", "OPENAI_API_KEY", "sk-1", true},
+		{`ANTHROPIC_API_KEY="sk-ant # not a comment"`, "ANTHROPIC_API_KEY", "sk-ant # not a comment", true},
+		{"DISCORD_TOKEN='tok'", "DISCORD_TOKEN", "tok", true},
+		{"BASESCAN_KEY=xyz # trailing comment", "BASESCAN_KEY", "xyz", true},
+		{"EMPTY=", "EMPTY", "", true},
+		{"no assignment here", "", "", false},
+		{"1BAD=value", "", "", false},
+		{"BAD-NAME=value", "", "", false},
+		{`UNTERMINATED="value`, "", "", false},
+	}
+	for _, tt := range tests {
+		name, value, ok := parseEnvLine(tt.line)
+		if ok != tt.ok || name != tt.name || value != tt.value {
+			t.Errorf("parseEnvLine(%q) = %q, %q, %v; want %q, %q, %v", tt.line, name, value, ok, tt.name, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestKeysImport(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	env := filepath.Join(e.home, ".env")
+	e.write(env, `# keys
+export ETHERSCAN_API_KEY=etherscan-key-1234567890
+OPENAI_API_KEY="sk-openai-abcdefghijklmnop"
+UNRELATED_SETTING=1
+this line is malformed
+DISCORD_TOKEN=
+`)
+
+	out := e.mustRun("keys", "import", env)
+	assertContains(t, out,
+		"api_keys.etherscan ← ETHERSCAN_API_KEY",
+		"api_keys.openai ← OPENAI_API_KEY",
+		"Line 4: UNRELATED_SETTING is not a recognized key, skipped",
+		"Line 5: not a KEY=VALUE assignment, skipped",
+		"Line 6: DISCORD_TOKEN is empty, skipped",
+	)
+	assertNotContains(t, out, "etherscan-key-1234567890", "sk-openai-abcdefghijklmnop")
+
+	var config AgentConfig
+	if err := json.Unmarshal([]byte(e.read(e.configPath())), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.APIKeys.Etherscan != "etherscan-key-1234567890" || config.APIKeys.OpenAI != "sk-openai-abcdefghijklmnop" {
+		t.Errorf("api_keys = %+v", config.APIKeys)
+	}
+}
+
+func TestKeysImportNothingRecognized(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	env := filepath.Join(e.home, ".env")
+	e.write(env, "FOO=bar\n")
+	assertContains(t, e.mustFail("keys", "import", env), "No recognized API keys found")
+}
+
+func TestKeysImportOverwriteNeedsConfirmation(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "old-etherscan-key-123456")
+	env := filepath.Join(e.home, ".env")
+	e.write(env, "ETHERSCAN_API_KEY=new-etherscan-key-123456\n")
+
+	assertContains(t, e.mustFail("keys", "import", env), "--yes")
+	e.mustRun("--yes", "keys", "import", env)
+	assertContains(t, e.read(e.configPath()), "new-etherscan-key-123456")
+}
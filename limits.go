@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+// Guardrails against loading a giant or hostile file, such as a config
+// fetched with `diff --against-remote`. ACM_MAX_CONFIG_BYTES and
+// ACM_MAX_LIST_ENTRIES raise or lower them.
+const (
+	defaultMaxConfigBytes = 1 << 20
+	defaultMaxListEntries = 10000
+)
+
+func maxConfigBytes() int64 {
+	return envLimit("ACM_MAX_CONFIG_BYTES", defaultMaxConfigBytes, parseSize)
+}
+
+func maxListEntries() int {
+	return int(envLimit("ACM_MAX_LIST_ENTRIES", defaultMaxListEntries, parseCount))
+}
+
+func parseCount(value string) (int64, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%q is not a positive number", value)
+	}
+	return n, nil
+}
+
+var warnedLimits = map[string]bool{}
+
+// envLimit reads a positive limit from the environment with parse, warning
+// once and keeping the default when the value isn't one
+func envLimit(name string, def int64, parse func(string) (int64, error)) int64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := parse(value)
+	if err != nil {
+		if !warnedLimits[name] {
+			warnedLimits[name] = true
+			fmt.Fprintf(os.Stderr, "⚠️  Ignoring %s: %v\n", name, err)
+		}
+		return def
+	}
+	return n
+}
+
+func checkConfigSize(size int64) error {
+	if limit := maxConfigBytes(); size > limit {
+		return fmt.Errorf("file is %d bytes, over the %d byte limit (raise it with ACM_MAX_CONFIG_BYTES)", size, limit)
+	}
+	return nil
+}
+
+// readConfigFile reads a config, refusing one over the size limit before
+// reading it into memory
+func readConfigFile(path string) ([]byte, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+		if err := checkConfigSize(info.Size()); err != nil {
+			return nil, err
+		}
+	}
+	return os.ReadFile(path)
+}
+
+// checkListLengths rejects a config with a list or map field longer than
+// the limit
+func checkListLengths(config *AgentConfig) error {
+	limit := maxListEntries()
+	for _, leaf := range configLeaves(config) {
+		kind := leaf.Value.Kind()
+		if (kind == reflect.Slice || kind == reflect.Map) && leaf.Value.Len() > limit {
+			return fmt.Errorf("%s has %d entries, over the limit of %d (raise it with ACM_MAX_LIST_ENTRIES)", leaf.Key, leaf.Value.Len(), limit)
+		}
+	}
+	return nil
+}
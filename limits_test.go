@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOversizedConfigRejected(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	config := e.read(e.configPath())
+	e.write(e.configPath(), config+strings.Repeat(" ", defaultMaxConfigBytes))
+
+	out := e.mustFail("get", "agent.name")
+	assertContains(t, out, "over the 1048576 byte limit (raise it with ACM_MAX_CONFIG_BYTES)")
+
+	e.setenv("ACM_MAX_CONFIG_BYTES", "2MB")
+	e.mustRun("get", "agent.name")
+
+	// a lower limit applies too
+	e.write(e.configPath(), config)
+	e.setenv("ACM_MAX_CONFIG_BYTES", "64")
+	assertContains(t, e.mustFail("get", "agent.name"), "over the 64 byte limit")
+}
+
+func TestOversizedRemoteConfigRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"agent": {"name": "remote"}}` + strings.Repeat(" ", 2048)))
+	}))
+	defer server.Close()
+	e := newTestEnv(t)
+	e.init()
+	e.setenv("ACM_MAX_CONFIG_BYTES", "1KB")
+	assertContains(t, e.mustFail("diff", "--against-remote", server.URL), "byte limit")
+}
+
+func TestOverlongListRejected(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.editConfig(func(config map[string]interface{}) {
+		section(config, "security")["whitelisted_addresses"] = []string{testAddrA, testAddrB, testAddrA}
+	})
+	e.setenv("ACM_MAX_LIST_ENTRIES", "2")
+	assertContains(t, e.mustFail("get", "agent.name"), "security.whitelisted_addresses has 3 entries, over the limit of 2")
+
+	e.setenv("ACM_MAX_LIST_ENTRIES", "3")
+	e.mustRun("get", "agent.name")
+}
+
+func TestEnvLimitIgnoresBadValue(t *testing.T) {
+	t.Setenv("ACM_MAX_LIST_ENTRIES", "lots")
+	if got := maxListEntries(); got != defaultMaxListEntries {
+		t.Errorf("maxListEntries = %d, want the default", got)
+	}
+	t.Setenv("ACM_MAX_CONFIG_BYTES", "512KB")
+	if got := maxConfigBytes(); got != 512<<10 {
+		t.Errorf("maxConfigBytes = %d, want 512 KiB", got)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// canonicalConfigPath is the fixed path external tools read
+func canonicalConfigPath() string {
+	return filepath.Join(getBaseDir(), "config.json")
+}
+
+// defaultProfileMoved reports whether the default profile was moved out of
+// the canonical path to make room for a link
+func defaultProfileMoved() bool {
+	_, err := os.Stat(filepath.Join(profileDir(defaultProfile), "config.json"))
+	return err == nil
+}
+
+// linkCanonicalConfig makes the canonical config path show the profile
+// name, so tools that know nothing about profiles follow `profile use`. On
+// Unix it is a symlink swapped in atomically; Windows gets a copy, refreshed
+// on every `profile use`.
+func linkCanonicalConfig(name string) {
+	if err := moveDefaultProfile(); err != nil {
+		fmt.Printf("❌ Failed to move the default profile out of %s: %v\n", canonicalConfigPath(), err)
+		os.Exit(1)
+	}
+
+	canonical, target := canonicalConfigPath(), profileConfigPath(name)
+	var err error
+	if runtime.GOOS == "windows" {
+		var data []byte
+		if data, err = os.ReadFile(target); err == nil {
+			err = writeFileAtomic(canonical, data, 0600)
+		}
+	} else {
+		tmp := filepath.Join(filepath.Dir(canonical), ".config.json.link-tmp")
+		os.Remove(tmp)
+		if err = os.Symlink(target, tmp); err == nil {
+			if err = os.Rename(tmp, canonical); err != nil {
+				os.Remove(tmp)
+			}
+		}
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to link %s: %v\n", canonical, err)
+		os.Exit(1)
+	}
+
+	index := loadProfileIndex()
+	if !index.Linked {
+		index.Linked = true
+		saveProfileIndex(index)
+	}
+	fmt.Printf("🔗 %s now points at profile %s\n", canonical, name)
+}
+
+// moveDefaultProfile moves the default profile's config from the base
+// directory into profiles/default the first time the canonical path is
+// linked, so the link never overwrites it. Its snapshots and backups stay in
+// the state directory, which doesn't depend on where the config is.
+func moveDefaultProfile() error {
+	if defaultProfileMoved() {
+		return nil
+	}
+	canonical := canonicalConfigPath()
+	info, err := os.Lstat(canonical)
+	if err != nil || info.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+
+	dir := profileDir(defaultProfile)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.Rename(canonical, filepath.Join(dir, "config.json"))
+}
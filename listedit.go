@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// listEditCommand handles `acm add <key> <item>...` and `acm remove <key>
+// <item>...` for list fields such as wallet.networks and the address lists.
+// Items are compared ignoring case, so an address isn't added twice in
+// different checksum casing. The edited list goes through the same checks
+// as `acm set`.
+func listEditCommand(cmd string, args []string) {
+	if len(args) < 2 {
+		fmt.Printf("Usage: acm %s <list key> <item>...\n", cmd)
+		os.Exit(1)
+	}
+	key := canonicalKey(args[0])
+	config := loadConfig()
+	field, ok := compositeField(&config, key)
+	if !ok || field.Kind() != reflect.Slice || field.Type().Elem().Kind() != reflect.String {
+		err := validationError(key, fmt.Errorf("%s is not a list", key))
+		exitWith(err, "❌ %v\n", err)
+	}
+
+	list := append([]string{}, field.Interface().([]string)...)
+	var changed []string
+	for _, item := range args[1:] {
+		item = strings.TrimSpace(item)
+		if key == "wallet.networks" {
+			item = strings.ToLower(item)
+		}
+		i := indexOfNetwork(list, item)
+		switch {
+		case item == "":
+			continue
+		case cmd == "add" && i >= 0:
+			fmt.Printf("⚠️  %s is already in %s\n", list[i], key)
+		case cmd == "add":
+			list = append(list, item)
+			changed = append(changed, item)
+		case i < 0:
+			fmt.Printf("⚠️  %s is not in %s\n", item, key)
+		default:
+			changed = append(changed, list[i])
+			list = append(list[:i], list[i+1:]...)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	value, _ := json.Marshal(list)
+	if err := assignValue(&config, key, string(value), false); err != nil {
+		exitWith(err, "❌ %v\n", err)
+	}
+	saveConfig(config)
+	verb, prep := "Added", "to"
+	if cmd == "remove" {
+		verb, prep = "Removed", "from"
+	}
+	fmt.Printf("✅ %s %s %s %s\n", verb, strings.Join(changed, ", "), prep, key)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddRemoveListItems(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+
+	assertContains(t, e.mustRun("add", "wallet.networks", "optimism", "arbitrum"), "Added optimism, arbitrum to wallet.networks")
+	assertContains(t, e.mustRun("get", "wallet.networks"), `["ethereum","base","optimism","arbitrum"]`)
+	// duplicates are found ignoring case
+	out := e.mustRun("add", "wallet.networks", "Base")
+	assertContains(t, out, "base is already in wallet.networks")
+	assertNotContains(t, out, "Added")
+
+	assertContains(t, e.mustRun("remove", "wallet.networks", "BASE"), "Removed base from wallet.networks")
+	assertContains(t, e.mustRun("remove", "wallet.networks", "polygon"), "polygon is not in wallet.networks")
+	assertContains(t, e.mustRun("get", "wallet.networks"), `["ethereum","optimism","arbitrum"]`)
+
+	// an address doesn't go in twice in another casing
+	e.mustRun("add", "security.whitelisted_addresses", testAddrA)
+	assertContains(t, e.mustRun("add", "security.whitelisted_addresses", strings.ToLower(testAddrA)), "is already in security.whitelisted_addresses")
+	e.mustRun("remove", "security.whitelisted_addresses", strings.ToLower(testAddrA))
+	assertNotContains(t, e.mustRun("get", "security.whitelisted_addresses"), testAddrA)
+}
+
+func TestAddRejectsBadItems(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	before := e.read(e.configPath())
+
+	assertContains(t, e.mustFail("add", "wallet.networks", "atlantis"), "atlantis")
+	assertContains(t, e.mustFail("add", "security.blacklisted_addresses", "0x12"), "0x12")
+	assertContains(t, e.mustFail("add", "agent.name", "x"), "agent.name is not a list")
+	assertContains(t, e.mustFail("add", "wallet.networks"), "Usage: acm add <list key> <item>...")
+	if e.read(e.configPath()) != before {
+		t.Error("a rejected add changed the config")
+	}
+}
+
+func TestNetworkNamesIgnoreCase(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustRun("add", "wallet.networks", "Optimism"), "Added optimism to wallet.networks")
+	assertContains(t, e.mustRun("add", "wallet.networks", "OPTIMISM"), "optimism is already in wallet.networks")
+	assertContains(t, e.mustRun("get", "wallet.networks"), `["ethereum","base","optimism"]`)
+
+	e.mustRun("set", "wallet.networks", "Ethereum,base")
+	assertContains(t, e.mustRun("get", "wallet.networks"), `["ethereum","base"]`)
+	e.mustRun("set", "wallet.networks", `["Polygon", "BASE"]`)
+	assertContains(t, e.mustRun("get", "wallet.networks"), `["polygon","base"]`)
+	assertContains(t, e.mustFail("set", "wallet.networks", "Atlantis"), `names unknown network "atlantis"`)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var hexAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// addressListCommand handles `acm whitelist|blacklist <subcommand>`. An
+// address may only be on one of the two lists at a time.
+func addressListCommand(list string, args []string) {
+	if len(args) < 1 {
+		printAddressListUsage(list)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list":
+		config := loadConfig()
+		own, _ := addressLists(&config, list)
+		if len(*own) == 0 {
+			fmt.Printf("No %sed addresses\n", list)
+			return
+		}
+		for _, addr := range *own {
+			fmt.Println(addr)
+		}
+	case "add":
+		addressListAdd(list, args[1:])
+	case "remove":
+		if len(args) < 2 {
+			printAddressListUsage(list)
+			os.Exit(1)
+		}
+		addressListRemove(list, args[1])
+	default:
+		printAddressListUsage(list)
+		os.Exit(1)
+	}
+}
+
+func printAddressListUsage(list string) {
+	fmt.Println("Usage:")
+	fmt.Printf("  acm %s list\n", list)
+	fmt.Printf("  acm %s add <address> [--move]\n", list)
+	fmt.Printf("  acm %s remove <address>\n", list)
+}
+
+// addressLists returns the named list and the opposing one
+func addressLists(config *AgentConfig, list string) (own, other *[]string) {
+	if list == "whitelist" {
+		return &config.Security.WhitelistedAddresses, &config.Security.BlacklistedAddresses
+	}
+	return &config.Security.BlacklistedAddresses, &config.Security.WhitelistedAddresses
+}
+
+func otherList(list string) string {
+	if list == "whitelist" {
+		return "blacklist"
+	}
+	return "whitelist"
+}
+
+func addressListAdd(list string, args []string) {
+	fs := flag.NewFlagSet(list+" add", flag.ExitOnError)
+	move := fs.Bool("move", false, "move the address from the opposing list")
+	positional := parseFlags(fs, args)
+	if len(positional) < 1 {
+		printAddressListUsage(list)
+		os.Exit(1)
+	}
+
+	addr := positional[0]
+	if err := validateAddress(addr); err != nil || addr == "" {
+		fmt.Printf("❌ Invalid address: %s\n", addr)
+		if err != nil && hexAddressPattern.MatchString(addr) {
+			fmt.Printf("   It %v\n", err)
+		}
+		os.Exit(1)
+	}
+
+	config := loadConfig()
+	own, other := addressLists(&config, list)
+
+	if indexOfAddress(*own, addr) >= 0 {
+		fmt.Printf("⚠️  %s is already on the %s\n", addr, list)
+		return
+	}
+
+	moved := false
+	if i := indexOfAddress(*other, addr); i >= 0 {
+		if !*move {
+			fmt.Printf("❌ %s is on the %s\n", addr, otherList(list))
+			fmt.Printf("   Use 'acm %s add %s --move' to move it\n", list, addr)
+			os.Exit(1)
+		}
+		*other = append((*other)[:i], (*other)[i+1:]...)
+		moved = true
+	}
+
+	*own = append(*own, addr)
+	saveConfig(config)
+
+	if moved {
+		fmt.Printf("✅ Moved %s from the %s to the %s\n", addr, otherList(list), list)
+		return
+	}
+	fmt.Printf("✅ Added %s to the %s\n", addr, list)
+}
+
+// addressListOverlap reports an address that setting key left on both the
+// whitelist and the blacklist, so set, add, JSON sets and imports refuse it
+// the way `acm whitelist|blacklist add` does
+func addressListOverlap(config *AgentConfig, key string) error {
+	list := "whitelist"
+	switch key {
+	case "security", "security.whitelisted_addresses":
+	case "security.blacklisted_addresses":
+		list = "blacklist"
+	default:
+		return nil
+	}
+	own, other := addressLists(config, list)
+	for _, addr := range *own {
+		if indexOfAddress(*other, addr) >= 0 {
+			return fmt.Errorf("invalid value for %s: %s is on the %s (use 'acm %s add %s --move' to move it)", key, addr, otherList(list), list, addr)
+		}
+	}
+	return nil
+}
+
+func addressListRemove(list, addr string) {
+	config := loadConfig()
+	own, _ := addressLists(&config, list)
+
+	i := indexOfAddress(*own, addr)
+	if i < 0 {
+		fmt.Printf("⚠️  %s is not on the %s\n", addr, list)
+		return
+	}
+
+	*own = append((*own)[:i], (*own)[i+1:]...)
+	saveConfig(config)
+	fmt.Printf("✅ Removed %s from the %s\n", addr, list)
+}
+
+func indexOfAddress(list []string, addr string) int {
+	for i, entry := range list {
+		if strings.EqualFold(entry, addr) {
+			return i
+		}
+	}
+	return -1
+}
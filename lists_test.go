@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var (
+	testAddrA = checksumAddress("0x1111111111111111111111111111111111111111")
+	testAddrB = checksumAddress("0xabcdef0123456789abcdef0123456789abcdef01")
+)
+
+func (e *testEnv) security() SecurityConfig {
+	e.t.Helper()
+	var config AgentConfig
+	if err := json.Unmarshal([]byte(e.read(e.configPath())), &config); err != nil {
+		e.t.Fatal(err)
+	}
+	return config.Security
+}
+
+func TestAddressListAddRejectsOpposingList(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("blacklist", "add", testAddrA)
+
+	out := e.mustFail("whitelist", "add", testAddrA)
+	assertContains(t, out, "is on the blacklist", "--move")
+	// Case doesn't hide the conflict
+	e.mustFail("whitelist", "add", strings.ToLower(testAddrA))
+
+	security := e.security()
+	if len(security.WhitelistedAddresses) != 0 || len(security.BlacklistedAddresses) != 1 {
+		t.Errorf("lists changed on a rejected add: %+v", security)
+	}
+}
+
+func TestAddressListMove(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("whitelist", "add", testAddrA)
+	e.mustRun("whitelist", "add", testAddrB)
+
+	assertContains(t, e.mustRun("blacklist", "add", testAddrA, "--move"), "Moved "+testAddrA+" from the whitelist to the blacklist")
+	security := e.security()
+	if len(security.WhitelistedAddresses) != 1 || security.WhitelistedAddresses[0] != testAddrB {
+		t.Errorf("whitelist = %v, want only %s", security.WhitelistedAddresses, testAddrB)
+	}
+	if len(security.BlacklistedAddresses) != 1 || security.BlacklistedAddresses[0] != testAddrA {
+		t.Errorf("blacklist = %v, want %s", security.BlacklistedAddresses, testAddrA)
+	}
+
+	// --move with nothing to move is a plain add
+	addr := checksumAddress("0x2222222222222222222222222222222222222222")
+	assertContains(t, e.mustRun("blacklist", "add", addr, "--move"), "Added "+addr+" to the blacklist")
+}
+
+func TestValidateReportsOverlap(t *testing.T) {
+	config := defaultConfig()
+	config.Security.WhitelistedAddresses = []string{testAddrA, testAddrB}
+	config.Security.BlacklistedAddresses = []string{testAddrA}
+	findings := checkAddressLists(config, validationContext{})
+	if len(findings) != 1 || !strings.Contains(findings[0].Message, testAddrA+" is both whitelisted and blacklisted") {
+		t.Fatalf("findings = %+v, want one overlap error", findings)
+	}
+	if findings[0].Severity != "error" {
+		t.Errorf("severity = %s", findings[0].Severity)
+	}
+}
+
+func TestSetRejectsOpposingList(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("blacklist", "add", testAddrA)
+
+	for _, args := range [][]string{
+		{"add", "security.whitelisted_addresses", testAddrA},
+		{"set", "security.whitelisted_addresses", testAddrA},
+		{"set", "security.whitelisted_addresses", `["` + testAddrB + `", "` + testAddrA + `"]`},
+		{"set", "security", `{"whitelisted_addresses": ["` + testAddrA + `"], "blacklisted_addresses": ["` + testAddrA + `"]}`},
+	} {
+		out := e.mustFail(args...)
+		assertContains(t, out, testAddrA+" is on the blacklist", "acm whitelist add "+testAddrA+" --move")
+	}
+	security := e.security()
+	if len(security.WhitelistedAddresses) != 0 || len(security.BlacklistedAddresses) != 1 {
+		t.Errorf("lists changed on a rejected set: %+v", security)
+	}
+
+	e.mustRun("whitelist", "add", testAddrA, "--move")
+	assertContains(t, e.mustFail("add", "security.blacklisted_addresses", testAddrA), "is on the whitelist", "acm blacklist add "+testAddrA+" --move")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// noLocal is set by the global --no-local flag: the local overrides file is
+// ignored. writeLocal (--local) sends writes to it instead of the base config.
+var (
+	noLocal    bool
+	writeLocal bool
+)
+
+// localLayers holds the overrides file loaded on top of each config, keyed
+// by the base config path like inheritedLayers
+var localLayers = map[string]*inheritedLayer{}
+
+// decodeRawConfig turns a config held as a JSON object back into an
+// AgentConfig
+func decodeRawConfig(raw map[string]interface{}) (AgentConfig, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	return parseConfig("", data)
+}
+
+// localOverrides reports whether the local overrides file loaded for the
+// config at path sets key
+func localOverrides(path, key string) bool {
+	local, ok := localLayers[path]
+	if !ok {
+		return false
+	}
+	var v interface{} = local.raw
+	for _, part := range strings.Split(key, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if v, ok = obj[part]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// localConfigPath is the overrides file for a config: config.json has
+// config.local.json next to it, config.jsonc has config.local.jsonc
+func localConfigPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".local" + ext
+}
+
+// applyLocalOverrides layers the local overrides file, if there is one, over
+// config, which was loaded from data at path. Local values win. Both files are
+// remembered as layers, so a save writes only what changed, to the base
+// config or with --local to the overrides file.
+func applyLocalOverrides(path string, data []byte, config AgentConfig) (AgentConfig, error) {
+	if noLocal {
+		return config, nil
+	}
+	localPath := localConfigPath(path)
+	localRaw := map[string]interface{}{}
+	localData, err := os.ReadFile(localPath)
+	switch {
+	case os.IsNotExist(err):
+		if !writeLocal {
+			return config, nil
+		}
+	case err != nil:
+		return AgentConfig{}, err
+	default:
+		if localRaw, err = rawConfigMap(localPath, localData); err != nil {
+			return AgentConfig{}, fmt.Errorf("%s: %v", localPath, err)
+		}
+		if _, ok := localRaw["extends"]; ok {
+			return AgentConfig{}, fmt.Errorf("%s: extends belongs in the base config, not the local overrides", localPath)
+		}
+	}
+
+	baseData, err := json.Marshal(config)
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	base := map[string]interface{}{}
+	if err := json.Unmarshal(baseData, &base); err != nil {
+		return AgentConfig{}, err
+	}
+	mergedData, err := json.Marshal(mergeConfigMaps(base, localRaw))
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	merged, err := parseConfig("", mergedData)
+	if err != nil {
+		return AgentConfig{}, fmt.Errorf("%s: %v", localPath, err)
+	}
+
+	layer, ok := inheritedLayers[path]
+	if !ok {
+		raw, err := rawConfigMap(path, data)
+		if err != nil {
+			return AgentConfig{}, err
+		}
+		layer = &inheritedLayer{raw: raw, complete: true}
+		inheritedLayers[path] = layer
+	}
+	layer.resolved = merged
+	localLayers[path] = &inheritedLayer{raw: localRaw, resolved: merged}
+	return merged, nil
+}
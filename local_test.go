@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func (e *testEnv) localPath() string {
+	return filepath.Join(e.home, ".config", "agent", "config.local.json")
+}
+
+func TestLocalOverridesPrecedence(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "agent.name", "shared-agent")
+	e.mustRun("set", "monitoring.dashboard_port", "8080")
+	e.write(e.localPath(), `{"monitoring": {"dashboard_port": 9090}, "api_keys": {"etherscan": "etherscan-local-key-123456"}}`)
+
+	assertContains(t, e.mustRun("get", "monitoring.dashboard_port"), "9090")
+	assertContains(t, e.mustRun("get", "agent.name"), "shared-agent")
+	e.mustRun("get", "api_keys.etherscan", "--exists")
+
+	assertContains(t, e.mustRun("--no-local", "get", "monitoring.dashboard_port"), "8080")
+	if _, code := e.run("--no-local", "get", "api_keys.etherscan", "--exists"); code == 0 {
+		t.Error("--no-local still sees the local API key")
+	}
+}
+
+func TestLocalOverridesWrites(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.write(e.localPath(), `{"monitoring": {"dashboard_port": 9090}}`)
+
+	// writes go to the base config, which the local value still overrides
+	out := e.mustRun("set", "monitoring.dashboard_port", "7000")
+	assertContains(t, out, "monitoring.dashboard_port is also set in "+e.localPath()+", which takes precedence")
+	assertContains(t, e.read(e.configPath()), `"dashboard_port": 7000`)
+	assertContains(t, e.read(e.localPath()), "9090")
+	assertNotContains(t, e.read(e.configPath()), "9090")
+	assertContains(t, e.mustRun("get", "monitoring.dashboard_port"), "9090")
+
+	// --local writes to the overrides file only
+	e.mustRun("--local", "set", "agent.name", "laptop-agent")
+	assertContains(t, e.read(e.localPath()), `"laptop-agent"`, "9090")
+	assertNotContains(t, e.read(e.configPath()), "laptop-agent")
+	assertContains(t, e.mustRun("get", "agent.name"), "laptop-agent")
+}
+
+func TestLocalOverridesCreatedByLocalWrite(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("--local", "set", "monitoring.dashboard_port", "9191")
+	if _, err := os.Stat(e.localPath()); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, e.read(e.localPath()), "9191")
+	assertNotContains(t, e.read(e.configPath()), "9191")
+}
+
+func TestLocalOverridesRejectExtends(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.write(e.localPath(), `{"extends": "other"}`)
+	assertContains(t, e.mustFail("get", "agent.name"), "extends belongs in the base config")
+}
+
+func TestLocalConfigPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"/a/config.json":  "/a/config.local.json",
+		"/a/config.jsonc": "/a/config.local.jsonc",
+		"/a/agent.yaml":   "/a/agent.local.yaml",
+	} {
+		if got := localConfigPath(path); got != want {
+			t.Errorf("localConfigPath(%s) = %s, want %s", path, got, want)
+		}
+	}
+}
@@ -2,9 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 )
 
 const version = "0.1.0"
@@ -12,11 +20,21 @@ const version = "0.1.0"
 // AgentConfig is the unified configuration for all agent tools
 type AgentConfig struct {
 	Version     string            `json:"version"`
+	Extends     string            `json:"extends,omitempty"`
+	InheritSecrets bool           `json:"inherit_secrets,omitempty"`
+	Include     []string          `json:"include,omitempty"`
+	// Unknown holds top-level sections this version doesn't know, written
+	// back unchanged
+	Unknown     map[string]json.RawMessage `json:"-"`
 	Agent       AgentInfo         `json:"agent"`
 	Wallet      WalletConfig      `json:"wallet"`
+	Networks    map[string]NetworkSettings `json:"networks,omitempty"`
 	Security    SecurityConfig    `json:"security"`
 	APIKeys     APIKeysConfig     `json:"api_keys"`
+	APIKeysMeta map[string]APIKeyMeta `json:"api_keys_meta,omitempty"`
 	Monitoring  MonitoringConfig  `json:"monitoring"`
+	Observability ObservabilityConfig `json:"observability"`
+	Validation  ValidationConfig  `json:"validation"`
 }
 
 type AgentInfo struct {
@@ -34,6 +52,12 @@ type WalletConfig struct {
 	AlertThreshold float64 `json:"alert_threshold"`
 }
 
+// NetworkSettings overrides wallet settings for one network in wallet.networks
+type NetworkSettings struct {
+	RPCURL     string  `json:"rpc_url,omitempty"`
+	DailyLimit float64 `json:"daily_limit,omitempty"`
+}
+
 type SecurityConfig struct {
 	FirewallEnabled     bool     `json:"firewall_enabled"`
 	HoneypotEnabled     bool     `json:"honeypot_enabled"`
@@ -55,66 +79,352 @@ type MonitoringConfig struct {
 	DashboardEnabled bool   `json:"dashboard_enabled"`
 	DashboardPort    int    `json:"dashboard_port"`
 	WebhookURL       string `json:"webhook_url,omitempty"`
+	// WebhookSecret is the HMAC key alerts are signed with
+	WebhookSecret    string `json:"webhook_secret,omitempty"`
+	// WebhookTemplate renders the alert payload; empty sends the default
+	WebhookTemplate  string `json:"webhook_template,omitempty"`
 	CheckInterval    int    `json:"check_interval_minutes"`
+	RateLimit        RateLimitConfig `json:"rate_limit"`
+}
+
+// ObservabilityConfig is the telemetry setup shared by all tools
+type ObservabilityConfig struct {
+	OTLPEndpoint string  `json:"otlp_endpoint,omitempty"`
+	SampleRate   float64 `json:"sample_rate"`
+	LogLevel     string  `json:"log_level"`
+}
+
+// logLevels are the log levels understood by the tools
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+func defaultObservability() ObservabilityConfig {
+	return ObservabilityConfig{
+		SampleRate: 1,
+		LogLevel:   "info",
+	}
+}
+
+// ValidationConfig extends `acm validate` with team-specific checks
+type ValidationConfig struct {
+	// Hooks are external validator commands; see runValidationHooks
+	Hooks []string `json:"hooks,omitempty"`
+}
+
+// RateLimitConfig bounds how hard monitoring tools call block explorers
+type RateLimitConfig struct {
+	RPS     float64 `json:"rps"`
+	Burst   int     `json:"burst"`
+	Backoff string  `json:"backoff"`
+}
+
+// backoffStrategies are the retry strategies understood by the tools
+var backoffStrategies = []string{"exponential", "linear", "constant", "none"}
+
+func defaultRateLimit() RateLimitConfig {
+	return RateLimitConfig{
+		RPS:     5,
+		Burst:   10,
+		Backoff: "exponential",
+	}
 }
 
 func main() {
-	if len(os.Args) < 2 {
+	args := parseGlobalFlags(os.Args[1:])
+	currentMaskStyle()
+	if len(args) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	cmd := os.Args[1]
+	cmd := args[0]
 
 	switch cmd {
 	case "init":
-		initConfig()
+		initConfig(args[1:])
 	case "show":
-		showConfig()
+		showConfig(args[1:])
 	case "get":
-		if len(os.Args) < 3 {
-			fmt.Println("Usage: acm get <key>")
-			os.Exit(1)
-		}
-		getValue(os.Args[2])
+		getValue(args[1:])
 	case "set":
-		if len(os.Args) < 4 {
-			fmt.Println("Usage: acm set <key> <value>")
-			os.Exit(1)
-		}
-		setValue(os.Args[2], os.Args[3])
+		setCommand(args[1:])
+	case "add", "remove":
+		listEditCommand(cmd, args[1:])
 	case "validate":
-		validateConfig()
+		validateConfig(args[1:])
+	case "config-test":
+		configTestCommand(args[1:])
 	case "export":
-		exportConfig()
+		exportConfig(args[1:])
+	case "compact":
+		if len(args) < 2 {
+			fmt.Println("Usage: acm compact <out>")
+			os.Exit(1)
+		}
+		compactConfig(expandPath(args[1]))
+	case "redact":
+		redactCommand(args[1:])
+	case "import":
+		importCommand(args[1:])
+	case "reformat":
+		reformatCommand(args[1:])
+	case "networks":
+		networksCommand(args[1:])
+	case "whitelist", "blacklist":
+		addressListCommand(cmd, args[1:])
+	case "diff":
+		diffCommand(args[1:])
+	case "env-map":
+		envMapCommand(args[1:])
+	case "webhook":
+		webhookCommand(args[1:])
+	case "keys":
+		keysCommand(args[1:])
+	case "keychain":
+		keychainCommand(args[1:])
+	case "encrypt":
+		encryptCommand()
+	case "decrypt":
+		decryptCommand()
+	case "profile":
+		profileCommand(args[1:])
+	case "clone-profile":
+		cloneProfileCommand(args[1:])
+	case "hook":
+		gitHookCommand(args[1:])
+	case "template":
+		templateCommand(args[1:])
+	case "snapshot":
+		snapshotCommand(args[1:])
+	case "snapshots":
+		snapshotsCommand()
+	case "restore-snapshot":
+		restoreSnapshotCommand(args[1:])
+	case "backups":
+		backupsCommand()
+	case "restore":
+		restoreCommand(args[1:])
+	case "gen-key":
+		genKeyCommand(args[1:])
+	case "wallet":
+		walletCommand(args[1:])
+	case "verify-keys":
+		verifyKeysCommand(args[1:])
+	case "doctor":
+		doctorCommand(args[1:])
+	case "serve":
+		serveCommand(args[1:])
+	case "info":
+		infoCommand(args[1:])
 	case "version":
-		fmt.Printf("agent-config-manager v%s\n", version)
+		versionCommand(args[1:])
 	default:
 		printUsage()
 	}
 }
 
+// configOverride is the config file path given with the global --config flag
+var configOverride string
+
+// profileOverride is the profile selected with the global --profile flag
+var profileOverride string
+
+// parseGlobalFlags strips flags that apply to every command from args and
+// returns the remaining arguments
+func parseGlobalFlags(args []string) []string {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(rest, args[i:]...)
+		case arg == "--config" && i+1 < len(args):
+			configOverride = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--config="):
+			configOverride = strings.TrimPrefix(arg, "--config=")
+		case arg == "--config-dir" && i+1 < len(args):
+			configDirOverride = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--config-dir="):
+			configDirOverride = strings.TrimPrefix(arg, "--config-dir=")
+		case arg == "--yes" || arg == "-y":
+			assumeYes = true
+		case arg == "--force":
+			forceWrite = true
+		case arg == "--mask-style" && i+1 < len(args):
+			maskStyle = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--mask-style="):
+			maskStyle = strings.TrimPrefix(arg, "--mask-style=")
+		case arg == "--color" && i+1 < len(args):
+			colorMode = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--color="):
+			colorMode = strings.TrimPrefix(arg, "--color=")
+		case arg == "--comments":
+			allowComments = true
+		case arg == "--strict":
+			strictMode = true
+		case arg == "--fix":
+			fixMode = true
+		case arg == "--no-local":
+			noLocal = true
+		case arg == "--local":
+			writeLocal = true
+		case arg == "--write-through":
+			writeThrough = true
+		case arg == "--drop-unknown":
+			dropUnknown = true
+		case arg == "--profile" && i+1 < len(args):
+			profileOverride = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--profile="):
+			profileOverride = strings.TrimPrefix(arg, "--profile=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest
+}
+
 func printUsage() {
 	fmt.Println("🔧 Agent Config Manager")
 	fmt.Println("========================")
 	fmt.Println("")
 	fmt.Println("Usage:")
-	fmt.Println("  acm init        - Create initial configuration")
+	fmt.Println("  acm init [--force] - Create initial configuration")
 	fmt.Println("  acm show        - Display current configuration")
+	fmt.Println("  acm show --format yaml|json - Print the whole config, secrets masked")
 	fmt.Println("  acm get <key>   - Get specific value (e.g., 'wallet.address')")
+	fmt.Println("  acm get --all   - Print every key as key=value (secrets masked)")
+	fmt.Println("  acm get --via-socket <key> - Ask a running 'acm serve' (falls back to the file)")
+	fmt.Println("  acm get --exists <key> [--print] - Exit 0 if the key is set, 1 if not")
+	fmt.Println("  acm get --watch <key> - Print the value, then each new value as the file changes")
 	fmt.Println("  acm set <key> <val> - Set specific value")
-	fmt.Println("  acm validate    - Validate configuration")
+	fmt.Println("  acm set --expand-env|--lazy <key> <val> - Set a value containing ${VAR} references")
+	fmt.Println("  acm set --if-unset <key> <val> - Set a value only if the key is empty or zero")
+	fmt.Println("  acm add|remove <key> <item>... - Add items to or remove them from a list")
+	fmt.Println("  acm validate [--only ids] [--skip ids] [--json] [--all-profiles] [--profile-matrix] [--diff-defaults] [--exit-on first] [--baseline f [--update-baseline]] - Validate configuration")
+	fmt.Println("  acm config-test <file> [--run-hooks] - Check a config file loads and validates")
+	fmt.Println("  acm hook install [--check validate|export]|uninstall - Check the config in a git pre-commit hook")
 	fmt.Println("  acm export      - Export config for all tools")
+	fmt.Println("  acm compact <out> - Write a minimal template without secrets")
+	fmt.Println("  acm redact <in> <out> [--mask-address] - Write a shareable copy with secrets redacted")
+	fmt.Println("  acm reformat [--check] - Rewrite the config with canonical formatting (backs up first)")
+	fmt.Println("  acm diff <file> | --against-remote <url> [--format unified] - Compare configs")
+	fmt.Println("  acm whitelist|blacklist add|remove|list - Manage address lists")
+	fmt.Println("  acm networks add|remove|list - Manage wallet networks")
+	fmt.Println("  acm env-map [--prefix P] - Show the env var each key maps to")
+	fmt.Println("  acm webhook test - Send a sample alert to the webhook")
+	fmt.Println("  acm keys import <file> - Import API keys from a .env file")
+	fmt.Println("  acm import --from-tool <tool> [file] - Pull edits to an exported tool config back into the config")
+	fmt.Println("  acm keys check-expiry [--days N] - Warn about API keys that expire soon")
+	fmt.Println("  acm keychain store [--remove-file]|remove - Keep the encryption key in the OS keychain")
+	fmt.Println("  acm encrypt|decrypt - Encrypt API keys with a passphrase, or store them in plaintext again")
+	fmt.Println("  acm profile list|create|use|rename|delete - Manage profiles")
+	fmt.Println("  acm clone-profile <src> <dst> [--no-secrets] [--new-identity] - Copy a profile to a new one")
+	fmt.Println("  acm template render <file> [--out f] - Render a Go template with the config")
+	fmt.Println("  acm gen-key [--out f] - Generate a new wallet keypair")
+	fmt.Println("  acm wallet balance [--json] - Show the wallet's native balance on each network")
+	fmt.Println("  acm wallet validate-signer - Prove control of wallet.address by signing a challenge")
+	fmt.Println("  acm verify-keys [--workers n] [--fail-fast] - Check API keys with their providers")
+	fmt.Println("  acm doctor [--fix] [--workers n] [--fail-fast] - Validate the config and run all network checks")
+	fmt.Println("  acm serve       - Keep the config loaded for get/set over a unix socket")
+	fmt.Println("  acm serve --http <addr> [--allow-writes] - Serve the config as JSON over HTTP (localhost by default)")
+	fmt.Println("  acm info [--json] - Show version, paths and secret sources for bug reports")
+	fmt.Println("  acm version [--json] - Show the version, commit, build date and Go version")
+	fmt.Println("  acm snapshot <name> [-m msg] - Save a named snapshot of the config")
+	fmt.Println("  acm snapshots   - List snapshots")
+	fmt.Println("  acm restore-snapshot <name> - Restore a snapshot (backs up first)")
+	fmt.Println("  acm backups     - List the automatic backups, newest first")
+	fmt.Println("  acm restore [backup] - Restore a backup, by default the newest good one (backs up first)")
+	fmt.Println("")
+	fmt.Println("Global flags:")
+	fmt.Println("  --config <path> - Use a specific config file (or set ACM_CONFIG)")
+	fmt.Println("  --profile <name> - Use a named profile (or set ACM_PROFILE)")
+	fmt.Println("  --config-dir <dir> - Keep backups, snapshots, exports and the socket here (or set ACM_CONFIG_DIR)")
+	fmt.Println("  --comments      - Allow // and /* */ comments (implied for .jsonc)")
+	fmt.Println("  --strict        - Treat duplicate keys in the config as errors")
+	fmt.Println("  --no-local      - Ignore the config.local.json overrides next to the config")
+	fmt.Println("  --local         - Save changes to config.local.json instead of the config")
+	fmt.Println("  --write-through - Save changes to the included fragment that sets each key")
+	fmt.Println("  --drop-unknown  - Remove top-level sections this version doesn't know on the next save")
+	fmt.Println("  --fix           - Rewrite a config saved with a BOM or CRLF line endings; apply doctor fixes")
+	fmt.Println("  --yes, -y       - Answer yes to every prompt (or set ACM_ASSUME_YES=1)")
+	fmt.Println("  --mask-style <s> - Show secrets as fixed (default), full or last4 (or set ACM_MASK_STYLE)")
+	fmt.Println("  --color <mode>  - Color validation output: auto (default), always or never; NO_COLOR disables auto")
+	fmt.Println("  --force         - Save a config inside a git working tree even if not gitignored")
 	fmt.Println("")
-	fmt.Println("Config location: ~/.config/agent/config.json")
+	fmt.Println("Config location: $XDG_CONFIG_HOME/agent/config.json (default ~/.config/agent/config.json)")
+}
+
+// parseFlags parses args with fs, allowing flags to appear after positional
+// arguments, and returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		if args[0] == "--" {
+			positional = append(positional, args[1:]...)
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	return positional
 }
 
+// getConfigPath resolves the config file location: --config, then
+// ACM_CONFIG, then the active profile under the base config directory
 func getConfigPath() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".config", "agent", "config.json")
+	if path := explicitConfigPath(); path != "" {
+		return path
+	}
+	name := activeProfile()
+	validateProfileName(name)
+	return profileConfigPath(name)
 }
 
-func initConfig() {
+// explicitConfigPath is the config file named by --config or ACM_CONFIG
+func explicitConfigPath() string {
+	if configOverride != "" {
+		return expandPath(configOverride)
+	}
+	return expandPath(os.Getenv("ACM_CONFIG"))
+}
+
+// getBaseDir is the agent config directory, honoring $XDG_CONFIG_HOME and
+// falling back to ~/.config per the XDG Base Directory spec
+func getBaseDir() string {
+	return filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "agent")
+}
+
+// xdgDir returns the base directory named by env, falling back to fallback
+// under the home directory. Relative values are ignored as the spec requires.
+// Without either there is no sensible location, so it exits rather than
+// silently resolving a path relative to the working directory.
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); dir != "" && filepath.IsAbs(dir) {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Printf("❌ Cannot determine the config location: %v\n", err)
+		fmt.Printf("   Pass --config <path>, or set ACM_CONFIG or %s\n", env)
+		os.Exit(1)
+	}
+	return filepath.Join(home, fallback)
+}
+
+func initConfig(args []string) {
+	// --force is a global flag: it also lets init write into a git working tree
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	parseFlags(fs, args)
+
 	configPath := getConfigPath()
 	configDir := filepath.Dir(configPath)
 	
@@ -123,13 +433,31 @@ func initConfig() {
 	
 	// Check if config already exists
 	if _, err := os.Stat(configPath); err == nil {
-		fmt.Printf("⚠️  Config already exists at %s\n", configPath)
-		fmt.Println("   Use 'acm show' to view or 'acm set' to modify")
-		return
+		if !forceWrite {
+			fmt.Printf("⚠️  Config already exists at %s\n", configPath)
+			fmt.Println("   Use 'acm show' to view or 'acm set' to modify")
+			return
+		}
+		confirmOrExit(fmt.Sprintf("Overwrite %s with the default config?", configPath))
 	}
 	
 	// Create default config
-	config := AgentConfig{
+	config := defaultConfig()
+	
+	// Save config
+	saveConfig(config)
+	
+	fmt.Printf("✅ Config created at %s\n", configPath)
+	fmt.Println("")
+	fmt.Println("Next steps:")
+	fmt.Println("  1. Add API keys: acm set api_keys.etherscan YOUR_KEY")
+	fmt.Println("  2. View config:  acm show")
+	fmt.Println("  3. Validate:     acm validate")
+}
+
+// defaultConfig returns the configuration written by `acm init`
+func defaultConfig() AgentConfig {
+	return AgentConfig{
 		Version: version,
 		Agent: AgentInfo{
 			Name:      "Arithmos",
@@ -157,104 +485,281 @@ func initConfig() {
 			DashboardEnabled: true,
 			DashboardPort:    8080,
 			CheckInterval:    5,
+			RateLimit:        defaultRateLimit(),
 		},
+		Observability: defaultObservability(),
 	}
-	
-	// Save config
-	saveConfig(config)
-	
-	fmt.Printf("✅ Config created at %s\n", configPath)
-	fmt.Println("")
-	fmt.Println("Next steps:")
-	fmt.Println("  1. Add API keys: acm set api_keys.etherscan YOUR_KEY")
-	fmt.Println("  2. View config:  acm show")
-	fmt.Println("  3. Validate:     acm validate")
 }
 
 func loadConfig() AgentConfig {
 	configPath := getConfigPath()
 	
-	data, err := os.ReadFile(configPath)
+	config, err := readConfig(configPath)
+	if errors.Is(err, ErrNotFound) {
+		exitWith(err, "❌ Config not found at %s\n   Run 'acm init' to create\n", configPath)
+	}
 	if err != nil {
-		fmt.Printf("❌ Config not found at %s\n", configPath)
-		fmt.Println("   Run 'acm init' to create")
-		os.Exit(1)
+		exitWith(err, "❌ Invalid config: %v\n", errors.Unwrap(err))
 	}
 	
-	var config AgentConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		fmt.Printf("❌ Invalid config: %v\n", err)
-		os.Exit(1)
+	return config
+}
+
+// readConfig loads the config at configPath with its extends chain resolved
+// and encrypted secrets decrypted
+func readConfig(configPath string) (AgentConfig, error) {
+	data, err := readConfigFile(configPath)
+	if err != nil {
+		return AgentConfig{}, ioError(configPath, err)
+	}
+	data, err = repairEncoding(configPath, data)
+	if err != nil {
+		return AgentConfig{}, ioError(configPath, err)
 	}
 	
-	return config
+	config, err := parseConfig(configPath, data)
+	if err == nil && (config.Extends != "" || len(config.Include) > 0) {
+		config, err = resolveInheritance(configPath, data)
+	}
+	if err == nil {
+		config, err = applyLocalOverrides(configPath, data, config)
+	}
+	if err != nil {
+		return AgentConfig{}, parseError(configPath, err)
+	}
+	
+	encrypted, err := decryptConfigSecrets(&config)
+	if err != nil {
+		return AgentConfig{}, parseError(configPath, err)
+	}
+	handleUnknownSections(configPath, &config)
+	if encrypted != "" {
+		encryptedConfigs[configPath] = encrypted
+		for _, layers := range []map[string]*inheritedLayer{inheritedLayers, localLayers} {
+			if layer, ok := layers[configPath]; ok {
+				layer.resolved.APIKeys = config.APIKeys
+			}
+		}
+	}
+	
+	return config, nil
 }
 
-func saveConfig(config AgentConfig) {
-	configPath := getConfigPath()
+// parseConfig decodes config file contents; path decides the dialect
+func parseConfig(path string, data []byte) (AgentConfig, error) {
+	if err := checkConfigSize(int64(len(data))); err != nil {
+		return AgentConfig{}, err
+	}
+	data, err := normalizeEncoding(path, data)
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	if data, err = configJSON(path, data); err != nil {
+		return AgentConfig{}, err
+	}
+	if err := checkDuplicateKeys(path, data); err != nil {
+		return AgentConfig{}, err
+	}
+	data, err = migrateAliases(path, data)
+	if err != nil {
+		return AgentConfig{}, err
+	}
 	
-	data, err := json.MarshalIndent(config, "", "  ")
+	// Configs written before these sections existed get the defaults
+	config := AgentConfig{
+		Monitoring:    MonitoringConfig{RateLimit: defaultRateLimit()},
+		Observability: defaultObservability(),
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return AgentConfig{}, err
+	}
+	if err := checkListLengths(&config); err != nil {
+		return AgentConfig{}, err
+	}
+	return config, nil
+}
+
+func saveConfig(config AgentConfig) {
+	saveConfigTo(getConfigPath(), config)
+}
+
+func saveConfigTo(configPath string, config AgentConfig) {
+	if err := writeConfig(configPath, config); err != nil {
+		exitWith(err, "❌ Failed to write config: %v\n", errors.Unwrap(err))
+	}
+}
+
+// writeConfig encodes config the way it was loaded (inheritance patch,
+// encrypted secrets, JSONC header) and writes it atomically. With --local
+// the changes go to the local overrides file instead.
+func writeConfig(configPath string, config AgentConfig) error {
+	var data []byte
+	var err error
+	encrypt := encryptedConfigs[configPath]
+	layer, hasLayer := inheritedLayers[configPath]
+	if local, ok := localLayers[configPath]; ok {
+		if writeLocal {
+			inheritedLayers[configPath].resolved = config
+			layer, hasLayer = local, true
+			configPath = localConfigPath(configPath)
+		} else {
+			local.resolved = config
+		}
+	}
+	if hasLayer && layer.complete {
+		// A complete base under local overrides: patch it, then write it
+		// like any other config
+		config, err = decodeRawConfig(layer.patch(config))
+		hasLayer = false
+	}
+	if hasLayer {
+		// Only write what changed so the rest stays inherited
+		raw := layer.patch(config)
+		if encrypt != "" {
+			err = encryptRawSecrets(raw, encrypt)
+		}
+		if err == nil {
+			data, err = json.MarshalIndent(raw, "", "  ")
+		}
+	} else if err == nil {
+		if encrypt != "" {
+			err = encryptConfigSecrets(&config, encrypt)
+		}
+		if err == nil {
+			data, err = json.MarshalIndent(config, "", "  ")
+		}
+	}
 	if err != nil {
-		fmt.Printf("❌ Failed to marshal config: %v\n", err)
-		os.Exit(1)
+		return &ConfigError{Kind: KindIO, Path: configPath, Err: err}
+	}
+	if err := checkListLengths(&config); err != nil {
+		return &ConfigError{Kind: KindValidation, Path: configPath, Err: err}
+	}
+	if err := checkGitWorkTree(configPath); err != nil {
+		return &ConfigError{Kind: KindIO, Path: configPath, Err: err}
+	}
+	if isYAML(configPath) {
+		if data, err = jsonToYAML(data); err != nil {
+			return &ConfigError{Kind: KindIO, Path: configPath, Err: err}
+		}
+		if existing, err := os.ReadFile(configPath); err == nil {
+			data = append(splitYAMLHeader(existing), data...)
+		}
+	}
+	
+	// Keep the leading comment block of a JSONC file
+	if isJSONC(configPath) {
+		if existing, err := os.ReadFile(configPath); err == nil {
+			header, bodyHasComments := splitJSONCHeader(existing)
+			if bodyHasComments {
+				fmt.Println("⚠️  Only the comment block at the top of the file is preserved on save")
+			}
+			data = append(header, data...)
+		}
 	}
 	
 	// Set restrictive permissions (no group/other read)
-	if err := os.WriteFile(configPath, data, 0600); err != nil {
-		fmt.Printf("❌ Failed to write config: %v\n", err)
-		os.Exit(1)
+	if err := ioError(configPath, writeFileAtomic(configPath, data, 0600)); err != nil {
+		return err
+	}
+	if hasLayer {
+		return writeFragments(layer, encrypt)
 	}
+	return nil
 }
 
-func showConfig() {
-	config := loadConfig()
+// writeFileAtomic writes data to a temp file in the same directory and
+// renames it into place, so readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	// Write through a symlink (such as a linked canonical config) rather
+	// than replacing it
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func showConfig(args []string) {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text, yaml or json")
+	parseFlags(fs, args)
+	config := loadEffectiveConfig()
+	if *format != "text" {
+		printConfigDocument(config, *format)
+		return
+	}
 	
-	fmt.Println("═".repeat(60))
+	fmt.Println(strings.Repeat("═", 60))
 	fmt.Println("  AGENT CONFIGURATION")
-	fmt.Println("═".repeat(60))
+	fmt.Println(strings.Repeat("═", 60))
 	fmt.Println()
 	
 	fmt.Printf("Version: %s\n", config.Version)
+	if config.Extends != "" {
+		fmt.Printf("Extends: %s\n", config.Extends)
+	}
 	fmt.Println()
 	
-	fmt.Println("AGENT:")
-	fmt.Printf("  Name:       %s\n", config.Agent.Name)
-	fmt.Printf("  ID:         %s\n", config.Agent.ID)
-	fmt.Printf("  ERC-8004:   #%d\n", config.Agent.ERC8004ID)
-	fmt.Printf("  Website:    %s\n", config.Agent.Website)
-	fmt.Printf("  GitHub:     %s\n", config.Agent.GitHub)
-	fmt.Println()
-	
-	fmt.Println("WALLET:")
-	fmt.Printf("  Address:    %s\n", config.Wallet.Address)
-	fmt.Printf("  Networks:   %v\n", config.Wallet.Networks)
-	fmt.Printf("  Daily Limit: %.2f ETH\n", config.Wallet.DailyLimit)
-	fmt.Printf("  Alert Threshold: %.2f ETH\n", config.Wallet.AlertThreshold)
-	fmt.Println()
-	
-	fmt.Println("SECURITY:")
-	fmt.Printf("  Firewall:   %s\n", boolStatus(config.Security.FirewallEnabled))
-	fmt.Printf("  Honeypot:   %s\n", boolStatus(config.Security.HoneypotEnabled))
-	fmt.Printf("  Prompt Guard: %s\n", boolStatus(config.Security.PromptGuardEnabled))
-	fmt.Printf("  Simulator:  %s\n", boolStatus(config.Security.SimulatorEnabled))
-	fmt.Printf("  Whitelist:  %d addresses\n", len(config.Security.WhitelistedAddresses))
-	fmt.Printf("  Blacklist:  %d addresses\n", len(config.Security.BlacklistedAddresses))
-	fmt.Println()
-	
-	fmt.Println("API KEYS:")
-	fmt.Printf("  Etherscan:  %s\n", keyStatus(config.APIKeys.Etherscan))
-	fmt.Printf("  Basescan:   %s\n", keyStatus(config.APIKeys.Basescan))
-	fmt.Printf("  OpenAI:     %s\n", keyStatus(config.APIKeys.OpenAI))
-	fmt.Printf("  Anthropic:  %s\n", keyStatus(config.APIKeys.Anthropic))
-	fmt.Printf("  Discord:    %s\n", keyStatus(config.APIKeys.Discord))
-	fmt.Println()
+	for _, section := range configSections {
+		var rows [][2]string
+		for _, field := range configFields {
+			if !strings.HasPrefix(field.Key, section.Key+".") {
+				continue
+			}
+			v, err := lookupField(&config, field.Key)
+			if err != nil {
+				continue
+			}
+			rows = append(rows, [2]string{field.Label, field.displayValue(v)})
+		}
+		printShowSection(section.Title, rows)
+	}
 	
-	fmt.Println("MONITORING:")
-	fmt.Printf("  Dashboard:  %s (port %d)\n", boolStatus(config.Monitoring.DashboardEnabled), config.Monitoring.DashboardPort)
-	fmt.Printf("  Check Interval: %d minutes\n", config.Monitoring.CheckInterval)
-	fmt.Printf("  Webhook:    %s\n", webhookStatus(config.Monitoring.WebhookURL))
+	if len(config.Networks) > 0 {
+		var rows [][2]string
+		for _, name := range sortedKeys(config.Networks) {
+			settings := config.Networks[name]
+			var parts []string
+			if settings.RPCURL != "" {
+				parts = append(parts, "RPC "+settings.RPCURL)
+			}
+			if settings.DailyLimit != 0 {
+				parts = append(parts, "limit "+formatETHAmount(settings.DailyLimit)+" ETH")
+			}
+			rows = append(rows, [2]string{name, strings.Join(parts, ", ")})
+		}
+		printShowSection("NETWORKS", rows)
+	}
+	fmt.Println(strings.Repeat("═", 60))
+}
+
+// printShowSection prints a section of `show` as label/value columns that
+// line up however long the labels are
+func printShowSection(title string, rows [][2]string) {
+	fmt.Printf("%s:\n", title)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(w, "  %s:\t%s\n", row[0], row[1])
+	}
+	w.Flush()
 	fmt.Println()
-	fmt.Println("═".repeat(60))
 }
 
 func boolStatus(b bool) string {
@@ -268,7 +773,7 @@ func keyStatus(key string) string {
 	if key == "" {
 		return "❌ not set"
 	}
-	return "✅ set"
+	return "✅ set (" + maskSecret(key) + ")"
 }
 
 func webhookStatus(url string) string {
@@ -278,162 +783,408 @@ func webhookStatus(url string) string {
 	return "✅ configured"
 }
 
-func getValue(key string) {
-	config := loadConfig()
+func webhookTemplateStatus(src string) string {
+	if src == "" {
+		return "default"
+	}
+	return "custom template"
+}
+
+func getValue(args []string) {
+	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	all := fs.Bool("all", false, "print every key as key=value")
+	format := fs.String("format", "text", "output format for --all: text or json")
+	viaSocket := fs.Bool("via-socket", false, "ask a running 'acm serve' instead of reading the file")
+	exists := fs.Bool("exists", false, "print nothing; exit 0 if the key is set, 1 if it is empty or zero")
+	printExists := fs.Bool("print", false, "with --exists, print true or false")
+	watch := fs.Bool("watch", false, "print the value, then a new line each time it changes")
+	positional := parseFlags(fs, args)
+
+	if *all {
+		config := loadEffectiveConfig()
+		printAllValues(&config, *format)
+		return
+	}
 	
-	switch key {
-	case "agent.name":
-		fmt.Println(config.Agent.Name)
-	case "agent.id":
-		fmt.Println(config.Agent.ID)
-	case "agent.erc8004_id":
-		fmt.Println(config.Agent.ERC8004ID)
-	case "wallet.address":
-		fmt.Println(config.Wallet.Address)
-	case "wallet.daily_limit":
-		fmt.Println(config.Wallet.DailyLimit)
-	case "wallet.alert_threshold":
-		fmt.Println(config.Wallet.AlertThreshold)
-	case "security.firewall_enabled":
-		fmt.Println(config.Security.FirewallEnabled)
-	case "security.honeypot_enabled":
-		fmt.Println(config.Security.HoneypotEnabled)
-	case "monitoring.dashboard_port":
-		fmt.Println(config.Monitoring.DashboardPort)
-	default:
+	if len(positional) < 1 {
+		fmt.Println("Usage: acm get <key> | acm get --all [--format json]")
+		os.Exit(1)
+	}
+	key := canonicalKey(positional[0])
+	if *exists {
+		keyExistsCheck(key, *printExists)
+	}
+	if *watch {
+		watchValue(key)
+		return
+	}
+	
+	// Fall back to reading the file when no server is running
+	if *viaSocket {
+		if value, ok := getViaSocket(key); ok {
+			fmt.Println(value)
+			return
+		}
+	}
+	
+	config := loadEffectiveConfig()
+	value, err := valueString(&config, key)
+	if err != nil {
 		fmt.Printf("❌ Unknown key: %s\n", key)
 		os.Exit(1)
 	}
+	fmt.Println(value)
 }
 
-func setValue(key, value string) {
-	config := loadConfig()
+// keyExistsCheck exits 0 when key resolves to a non-empty, non-zero value
+// and 1 otherwise; an unknown key exits 2
+func keyExistsCheck(key string, print bool) {
+	config := loadEffectiveConfig()
+	v, err := lookupField(&config, key)
+	if err != nil || v.Kind() == reflect.Struct {
+		fmt.Fprintf(os.Stderr, "❌ Unknown key: %s\n", key)
+		os.Exit(2)
+	}
+	set := isSetValue(v)
+	if print {
+		fmt.Println(set)
+	}
+	if !set {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// isSetValue reports whether a leaf holds a non-empty, non-zero value
+func isSetValue(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		return v.Len() > 0
+	}
+	return !v.IsZero()
+}
+
+// valueString renders one key as `acm get` prints it, masking secrets
+func valueString(config *AgentConfig, key string) (string, error) {
+	v, err := lookupField(config, key)
+	if err != nil || v.Kind() == reflect.Struct {
+		return "", fmt.Errorf("unknown key: %s", key)
+	}
+	if isSecretKey(key) {
+		return maskSecret(v.String()), nil
+	}
+	return leafString(v), nil
+}
+
+// printAllValues dumps every resolvable leaf, masking secrets. Entries of
+// maps such as networks are listed field by field, under the keys get
+// takes. Top-level keys that only shape the file, such as extends and
+// include, are left out while unset, as they are in the file.
+func printAllValues(config *AgentConfig, format string) {
+	leaves := []configLeaf{}
+	for _, leaf := range configLeaves(config) {
+		switch {
+		case leaf.Value.Kind() == reflect.Map && leaf.Value.Type().Elem().Kind() == reflect.Struct:
+			// each entry's fields resolve on their own, as networks.base.daily_limit
+			names := make([]string, 0, leaf.Value.Len())
+			for _, name := range leaf.Value.MapKeys() {
+				names = append(names, name.String())
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				leaves = appendLeaves(leaves, leaf.Value.MapIndex(reflect.ValueOf(name)), leaf.Key+"."+name)
+			}
+		case strings.Contains(leaf.Key, ".") || leaf.Key == "version" || isSetValue(leaf.Value):
+			leaves = append(leaves, leaf)
+		}
+	}
 	
-	switch key {
-	case "api_keys.etherscan":
-		config.APIKeys.Etherscan = value
-	case "api_keys.basescan":
-		config.APIKeys.Basescan = value
-	case "api_keys.openai":
-		config.APIKeys.OpenAI = value
-	case "api_keys.anthropic":
-		config.APIKeys.Anthropic = value
-	case "api_keys.discord":
-		config.APIKeys.Discord = value
-	case "wallet.daily_limit":
-		var limit float64
-		fmt.Sscanf(value, "%f", &limit)
-		config.Wallet.DailyLimit = limit
-	case "wallet.alert_threshold":
-		var threshold float64
-		fmt.Sscanf(value, "%f", &threshold)
-		config.Wallet.AlertThreshold = threshold
-	case "monitoring.webhook_url":
-		config.Monitoring.WebhookURL = value
-	case "monitoring.check_interval":
-		var interval int
-		fmt.Sscanf(value, "%d", &interval)
-		config.Monitoring.CheckInterval = interval
+	switch format {
+	case "text":
+		for _, leaf := range leaves {
+			value := leafString(leaf.Value)
+			if isSecretKey(leaf.Key) {
+				value = maskSecret(leaf.Value.String())
+			}
+			fmt.Printf("%s=%s\n", leaf.Key, value)
+		}
+	case "json":
+		flat := map[string]interface{}{}
+		for _, leaf := range leaves {
+			if isSecretKey(leaf.Key) {
+				flat[leaf.Key] = maskSecret(leaf.Value.String())
+				continue
+			}
+			flat[leaf.Key] = leafInterface(leaf.Value)
+		}
+		data, _ := json.MarshalIndent(flat, "", "  ")
+		fmt.Println(string(data))
 	default:
-		fmt.Printf("❌ Unknown key: %s\n", key)
+		fmt.Printf("❌ Unknown format: %s\n", format)
+		os.Exit(1)
+	}
+}
+
+// setCommand parses `acm set [--expand-env|--lazy] [--no-create] <key> <value>`. The flags
+// are picked out by hand so values like -1 aren't mistaken for flags.
+func setCommand(args []string) {
+	var positional []string
+	expand, lazy, create, ifUnset := false, false, true, false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--":
+			positional = append(positional, args[i+1:]...)
+			i = len(args)
+		case "--expand-env":
+			expand = true
+		case "--lazy":
+			lazy = true
+		case "--no-create":
+			create = false
+		case "--if-unset":
+			ifUnset = true
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+	if len(positional) != 2 || (expand && lazy) {
+		fmt.Println("Usage: acm set [--expand-env | --lazy] [--no-create] [--if-unset] <key> <value>")
 		os.Exit(1)
 	}
 	
-	saveConfig(config)
-	fmt.Printf("✅ Set %s\n", key)
+	key, value := canonicalKey(positional[0]), positional[1]
+	switch {
+	case expand:
+		expanded, err := expandEnvRefs(value)
+		if err != nil {
+			err = validationError(key, fmt.Errorf("%s: %v", key, err))
+			exitWith(err, "❌ %v\n", err)
+		}
+		value = expanded
+	case lazy:
+		if v, err := lookupField(&AgentConfig{}, key); err == nil && v.Kind() != reflect.String {
+			err = validationError(key, fmt.Errorf("--lazy only works for text values, %s is a %s", key, v.Kind()))
+			exitWith(err, "❌ %v\n", err)
+		}
+		// Fail now rather than on every later read
+		if _, err := expandEnvRefs(value); err != nil {
+			err = validationError(key, fmt.Errorf("%s: %v", key, err))
+			exitWith(err, "❌ %v\n", err)
+		}
+	case hasEnvRefs(value):
+		fmt.Printf("❌ %s contains ${...} references\n", key)
+		fmt.Println("   Use --expand-env to store the expanded value, or --lazy to expand it on every read")
+		os.Exit(1)
+	}
+	if ifUnset {
+		// Only values stored in the config count, not environment secrets
+		config := loadConfig()
+		if v, err := lookupField(&config, key); err == nil && v.Kind() != reflect.Struct && isSetValue(v) {
+			fmt.Printf("✅ %s is already set, left unchanged\n", key)
+			return
+		}
+	}
+	setValue(key, value, create)
 }
 
-func validateConfig() {
+func setValue(key, value string, create bool) {
+	key = canonicalKey(key)
 	config := loadConfig()
 	
-	fmt.Println("🔍 Validating configuration...")
-	fmt.Println()
-	
-	issues := []string{}
-	
-	// Check required fields
-	if config.Wallet.Address == "" {
-		issues = append(issues, "❌ Wallet address not set")
+	if err := assignValue(&config, key, value, create); err != nil {
+		exitWith(err, "❌ %v\n", err)
 	}
 	
-	if config.Wallet.DailyLimit <= 0 {
-		issues = append(issues, "⚠️  Daily limit should be positive")
+	saveConfig(config)
+	fmt.Printf("✅ Set %s\n", key)
+	if path := getConfigPath(); !writeLocal && localOverrides(path, key) {
+		fmt.Fprintf(os.Stderr, "⚠️  %s is also set in %s, which takes precedence (use --local to change it there)\n", key, localConfigPath(path))
 	}
-	
-	if config.APIKeys.Etherscan == "" {
-		issues = append(issues, "⚠️  Etherscan API key not set (needed for monitoring)")
+	if isSecretKey(key) {
+		warnPlaceholderSecret(key, value)
 	}
-	
-	if config.APIKeys.Basescan == "" {
-		issues = append(issues, "⚠️  Basescan API key not set (needed for monitoring)")
+}
+
+// assignValue parses value for the canonical key and stores it in config.
+// Keys inside maps (networks.<name>.*) create the entry when create is set.
+// Nothing is changed when value doesn't parse or fails the field's validator.
+func assignValue(config *AgentConfig, key, value string, create bool) error {
+	for _, alias := range fieldAliases {
+		if key == alias.Old {
+			key = alias.New
+		}
 	}
-	
-	// Check security settings
-	if !config.Security.FirewallEnabled && !config.Security.HoneypotEnabled {
-		issues = append(issues, "⚠️  All security features disabled")
+	// Network names are lowercase, so Optimism is optimism as with
+	// `acm networks add`; the value, a comma-separated or JSON list, has no
+	// other text to keep the case of
+	if key == "wallet.networks" {
+		value = strings.ToLower(value)
 	}
-	
-	// Print results
-	if len(issues) == 0 {
-		fmt.Println("✅ Configuration is valid!")
-	} else {
-		for _, issue := range issues {
-			fmt.Println(issue)
+	// Lists also take comma-separated items; sections and maps only JSON
+	if field, ok := compositeField(config, key); ok && (field.Kind() != reflect.Slice || strings.HasPrefix(strings.TrimSpace(value), "[")) {
+		if err := assignJSONValue(config, key, value); err != nil {
+			return validationError(key, err)
 		}
-		fmt.Println()
-		fmt.Printf("Found %d issue(s)\n", len(issues))
+		return validationError(key, addressListOverlap(config, key))
+	}
+	if err := validateFieldValue(key, value); err != nil {
+		return validationError(key, err)
 	}
+	if key == "monitoring.webhook_template" {
+		if err := validateWebhookTemplate(value); err != nil {
+			return validationError(key, fmt.Errorf("invalid value for %s: %v", key, err))
+		}
+	}
+	err := assignField(config, key, value, create)
+	if errors.Is(err, errUnknownKey) {
+		err = fmt.Errorf("unknown key: %s", key)
+	}
+	if err == nil {
+		err = addressListOverlap(config, key)
+	}
+	return validationError(key, err)
 }
 
-func exportConfig() {
-	config := loadConfig()
-	configPath := getConfigPath()
+// parseFloatInto parses value for key into dst, requiring a finite number
+// in full (so "abc" and "0.5xyz" are rejected)
+func parseFloatInto(dst *float64, key, value string) error {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("invalid value for %s: %q is not a number", key, value)
+	}
+	*dst = f
+	return nil
+}
+
+// parseIntInto parses value for key into dst as a whole number
+func parseIntInto(dst *int, key, value string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %q is not a whole number", key, value)
+	}
+	*dst = n
+	return nil
+}
+
+// parseBoolInto parses value for key into dst as true or false (also 1/0,
+// t/f as strconv.ParseBool accepts)
+func parseBoolInto(dst *bool, key, value string) error {
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %q is not true or false", key, value)
+	}
+	*dst = b
+	return nil
+}
+
+func validateConfig(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	only := fs.String("only", "", "comma-separated rule IDs to run")
+	skip := fs.String("skip", "", "comma-separated rule IDs to skip")
+	asJSON := fs.Bool("json", false, "print findings as JSON")
+	listRules := fs.Bool("list-rules", false, "list rule IDs and exit")
+	allProfiles := fs.Bool("all-profiles", false, "validate every profile and check none share an identity")
+	matrix := fs.Bool("profile-matrix", false, "summarize every rule for every profile in one table")
+	diffDefaults := fs.Bool("diff-defaults", false, "list every field that differs from the defaults written by init")
+	exitOn := fs.String("exit-on", "all", "all: collect every finding; first: stop at the first error and exit 1")
+	baseline := fs.String("baseline", "", "only report findings not recorded in this file, and exit 1 if there are any")
+	updateBaseline := fs.Bool("update-baseline", false, "record the current findings in the --baseline file")
+	parseFlags(fs, args)
+	if *exitOn != "all" && *exitOn != "first" {
+		fmt.Printf("❌ Unknown --exit-on value: %s (use all or first)\n", *exitOn)
+		os.Exit(1)
+	}
 	
-	// Export individual tool configs
-	exportDir := filepath.Join(filepath.Dir(configPath), "exports")
-	os.MkdirAll(exportDir, 0755)
+	if *diffDefaults {
+		diffAgainstDefaults(*asJSON)
+		return
+	}
 	
-	// Export for wallet-monitor
-	walletConfig := map[string]interface{}{
-		"address":         config.Wallet.Address,
-		"etherscan_key":   config.APIKeys.Etherscan,
-		"basescan_key":    config.APIKeys.Basescan,
-		"check_interval":  config.Monitoring.CheckInterval,
-		"alert_threshold": config.Wallet.AlertThreshold,
-		"webhook_url":     config.Monitoring.WebhookURL,
-	}
-	exportToolConfig(exportDir, "wallet-monitor.json", walletConfig)
+	if *listRules {
+		for _, rule := range validationRules {
+			fmt.Printf("  %-18s %s\n", rule.ID, rule.Description)
+		}
+		return
+	}
 	
-	// Export for reputation-scanner
-	scannerConfig := map[string]interface{}{
-		"address":      config.Wallet.Address,
-		"etherscan_key": config.APIKeys.Etherscan,
-		"basescan_key":  config.APIKeys.Basescan,
+	rules, err := selectRules(*only, *skip)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
 	}
-	exportToolConfig(exportDir, "reputation-scanner.json", scannerConfig)
 	
-	// Export for security-dashboard
-	dashboardConfig := map[string]interface{}{
-		"port": config.Monitoring.DashboardPort,
+	if *exitOn == "first" && (*allProfiles || *matrix) {
+		fmt.Println("❌ --exit-on first works on a single config, not with --all-profiles or --profile-matrix")
+		os.Exit(1)
+	}
+	if *baseline != "" && (*allProfiles || *matrix || *exitOn == "first") {
+		fmt.Println("❌ --baseline works on a single config with every finding, not with --all-profiles, --profile-matrix or --exit-on first")
+		os.Exit(1)
+	}
+	if *updateBaseline && *baseline == "" {
+		fmt.Println("❌ --update-baseline needs --baseline <file>")
+		os.Exit(1)
+	}
+	if *allProfiles {
+		validateAllProfiles(rules, *asJSON)
+		return
+	}
+	if *matrix {
+		validateProfileMatrix(rules, *asJSON)
+		return
 	}
-	exportToolConfig(exportDir, "security-dashboard.json", dashboardConfig)
 	
-	fmt.Printf("✅ Exported tool configs to %s/\n", exportDir)
-	fmt.Println("   - wallet-monitor.json")
-	fmt.Println("   - reputation-scanner.json")
-	fmt.Println("   - security-dashboard.json")
-}
-
-func exportToolConfig(dir, filename string, config map[string]interface{}) {
-	path := filepath.Join(dir, filename)
-	data, _ := json.MarshalIndent(config, "", "  ")
-	os.WriteFile(path, data, 0600)
+	config := loadEffectiveConfig()
+	ctx := validationContext{Dir: filepath.Dir(getConfigPath())}
+	var findings []finding
+	if *exitOn == "first" {
+		findings = runValidationToFirstError(config, ctx, rules)
+	} else {
+		findings = runValidation(config, ctx, rules)
+	}
+	// Stopping early is only useful to CI if the exit code says so
+	failed := *exitOn == "first" && hasErrors(findings)
+	
+	suppressed := 0
+	if *baseline != "" {
+		*baseline = expandPath(*baseline)
+		if *updateBaseline {
+			if err := writeBaseline(*baseline, findings); err != nil {
+				fmt.Printf("❌ Failed to write %s: %v\n", *baseline, err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ Recorded %d issue(s) in %s\n", len(findings), *baseline)
+			return
+		}
+		known, err := readBaseline(*baseline)
+		if err != nil {
+			fmt.Printf("❌ Failed to read baseline: %v\n", err)
+			os.Exit(1)
+		}
+		findings, suppressed = newFindings(findings, known)
+		failed = len(findings) > 0
+	}
+	
+	if *asJSON {
+		data, _ := json.MarshalIndent(validationReport{Valid: !hasErrors(findings), Findings: findings, Suppressed: suppressed}, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Println("🔍 Validating configuration...")
+		fmt.Println()
+		printFindings(findings)
+		if suppressed > 0 {
+			fmt.Printf("ℹ️  %d known issue(s) in %s not shown\n", suppressed, *baseline)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
 }
 
-func (s string) repeat(n int) string {
-	result := ""
-	for i := 0; i < n; i++ {
-		result += s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
 	}
-	return result
+	return false
 }
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Most commands print and exit, so tests run them in a child process: the
+// test binary re-executes itself and runs main with the arguments in
+// ACM_TEST_ARGS, a JSON list.
+const testArgsEnv = "ACM_TEST_ARGS"
+
+func TestMain(m *testing.M) {
+	if data, ok := os.LookupEnv(testArgsEnv); ok {
+		var args []string
+		if err := json.Unmarshal([]byte(data), &args); err != nil {
+			panic(err)
+		}
+		os.Args = append([]string{"acm"}, args...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// testEnv is a home directory of its own for running acm commands
+type testEnv struct {
+	t    *testing.T
+	home string
+	env  []string
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	home := t.TempDir()
+	env := []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	return &testEnv{t: t, home: home, env: env}
+}
+
+// setenv adds a variable to every command run from now on
+func (e *testEnv) setenv(name, value string) {
+	e.env = append(e.env, name+"="+value)
+}
+
+// run runs acm with args and returns its combined output and exit code
+func (e *testEnv) run(args ...string) (string, int) {
+	e.t.Helper()
+	return e.runInput("", args...)
+}
+
+// runInput runs acm with stdin set to input
+func (e *testEnv) runInput(input string, args ...string) (string, int) {
+	e.t.Helper()
+	data, _ := json.Marshal(args)
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(append([]string{}, e.env...), testArgsEnv+"="+string(data))
+	cmd.Dir = e.home
+	cmd.Stdin = strings.NewReader(input)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return out.String(), exit.ExitCode()
+	}
+	if err != nil {
+		e.t.Fatalf("running acm %s: %v", strings.Join(args, " "), err)
+	}
+	return out.String(), 0
+}
+
+// mustRun runs acm and fails the test unless it exits 0
+func (e *testEnv) mustRun(args ...string) string {
+	e.t.Helper()
+	out, code := e.run(args...)
+	if code != 0 {
+		e.t.Fatalf("acm %s exited %d:\n%s", strings.Join(args, " "), code, out)
+	}
+	return out
+}
+
+// mustFail runs acm and fails the test if it exits 0
+func (e *testEnv) mustFail(args ...string) string {
+	e.t.Helper()
+	out, code := e.run(args...)
+	if code == 0 {
+		e.t.Fatalf("acm %s succeeded, want an error:\n%s", strings.Join(args, " "), out)
+	}
+	return out
+}
+
+func (e *testEnv) configPath() string {
+	return filepath.Join(e.home, ".config", "agent", "config.json")
+}
+
+func (e *testEnv) stateDir() string {
+	return filepath.Join(e.home, ".local", "state", "acm")
+}
+
+// init creates the default config
+func (e *testEnv) init() {
+	e.t.Helper()
+	e.mustRun("init")
+}
+
+// editConfig applies edit to the config file as a JSON object
+func (e *testEnv) editConfig(edit func(config map[string]interface{})) {
+	e.t.Helper()
+	config := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(e.read(e.configPath())), &config); err != nil {
+		e.t.Fatal(err)
+	}
+	edit(config)
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	e.write(e.configPath(), string(data))
+}
+
+func (e *testEnv) read(path string) string {
+	e.t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return string(data)
+}
+
+func (e *testEnv) write(path, content string) {
+	e.t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		e.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		e.t.Fatal(err)
+	}
+}
+
+// isolatePaths points the running test's config lookup at a fresh home
+// directory, for tests that call path helpers directly, and returns it
+func isolatePaths(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, name := range []string{"XDG_CONFIG_HOME", "XDG_STATE_HOME", "ACM_CONFIG", "ACM_CONFIG_DIR", "ACM_PROFILE"} {
+		t.Setenv(name, "")
+	}
+	saved := [...]string{configOverride, configDirOverride, profileOverride}
+	configOverride, configDirOverride, profileOverride = "", "", ""
+	t.Cleanup(func() {
+		configOverride, configDirOverride, profileOverride = saved[0], saved[1], saved[2]
+	})
+	return home
+}
+
+// writeTestConfig saves config as the current config file, for tests that
+// call commands in-process after isolatePaths
+func writeTestConfig(t *testing.T, config AgentConfig) {
+	t.Helper()
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := getConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		done <- buf.String()
+	}()
+	defer func() { os.Stdout = stdout }()
+	fn()
+	w.Close()
+	return <-done
+}
+
+// section returns config[name] as an object, creating it
+func section(config map[string]interface{}, name string) map[string]interface{} {
+	s, ok := config[name].(map[string]interface{})
+	if !ok {
+		s = map[string]interface{}{}
+		config[name] = s
+	}
+	return s
+}
+
+// assertContains fails unless out contains every want
+func assertContains(t *testing.T, out string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(out, w) {
+			t.Errorf("output doesn't contain %q:\n%s", w, out)
+		}
+	}
+}
+
+// assertNotContains fails if out contains any of unwanted
+func assertNotContains(t *testing.T, out string, unwanted ...string) {
+	t.Helper()
+	for _, u := range unwanted {
+		if strings.Contains(out, u) {
+			t.Errorf("output contains %q:\n%s", u, out)
+		}
+	}
+}
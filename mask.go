@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maskStyle is set by the global --mask-style flag or ACM_MASK_STYLE
+var maskStyle string
+
+// maskStyles are the accepted --mask-style values. fixed, the default,
+// always prints eight asterisks so a masked secret doesn't reveal its length.
+var maskStyles = []string{"fixed", "full", "last4"}
+
+// currentMaskStyle returns the selected mask style, exiting on an unknown one
+func currentMaskStyle() string {
+	style := maskStyle
+	if style == "" {
+		style = os.Getenv("ACM_MASK_STYLE")
+	}
+	if style == "" {
+		return "fixed"
+	}
+	if !containsString(maskStyles, style) {
+		fmt.Printf("❌ Unknown mask style: %s (use %s)\n", style, strings.Join(maskStyles, ", "))
+		os.Exit(1)
+	}
+	return style
+}
+
+// maskSecret hides a secret's value for display in the selected style:
+//
+//	fixed  ********        same for every secret
+//	full   ************    one * per character, revealing the length
+//	last4  ****abcd        the last 4 characters, for secrets long enough
+//	                       that they give little away
+func maskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	const fixed = "********"
+	runes := []rune(secret)
+	switch currentMaskStyle() {
+	case "full":
+		return strings.Repeat("*", len(runes))
+	case "last4":
+		if len(runes) <= 12 {
+			return fixed
+		}
+		return "****" + string(runes[len(runes)-4:])
+	}
+	return fixed
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaskSecretStyles(t *testing.T) {
+	saved := maskStyle
+	t.Cleanup(func() { maskStyle = saved })
+	t.Setenv("ACM_MASK_STYLE", "")
+	long, short := "etherscan-secret-key-abcd", "short-key"
+
+	for style, want := range map[string][2]string{
+		"":      {"********", "********"},
+		"fixed": {"********", "********"},
+		"full":  {strings.Repeat("*", len(long)), strings.Repeat("*", len(short))},
+		"last4": {"****abcd", "********"},
+	} {
+		maskStyle = style
+		if got := maskSecret(long); got != want[0] {
+			t.Errorf("style %q: maskSecret(long) = %s, want %s", style, got, want[0])
+		}
+		if got := maskSecret(short); got != want[1] {
+			t.Errorf("style %q: maskSecret(short) = %s, want %s", style, got, want[1])
+		}
+		if got := maskSecret(""); got != "" {
+			t.Errorf("style %q: maskSecret(\"\") = %q", style, got)
+		}
+	}
+
+	// the environment variable applies when the flag isn't given
+	maskStyle = ""
+	t.Setenv("ACM_MASK_STYLE", "last4")
+	if got := maskSecret(long); got != "****abcd" {
+		t.Errorf("ACM_MASK_STYLE=last4: maskSecret = %s", got)
+	}
+	maskStyle = "fixed"
+	if got := maskSecret(long); got != "********" {
+		t.Errorf("--mask-style fixed over ACM_MASK_STYLE: maskSecret = %s", got)
+	}
+}
+
+func TestMaskStyleAcrossCommands(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-key-abcd")
+	other := defaultConfig()
+	other.APIKeys.Etherscan = "etherscan-other-key-wxyz"
+	data, _ := json.Marshal(other)
+	path := filepath.Join(e.home, "other.json")
+	e.write(path, string(data))
+
+	for _, args := range [][]string{
+		{"show"},
+		{"get", "api_keys.etherscan"},
+		{"info"},
+	} {
+		out := e.mustRun(append([]string{"--mask-style", "last4"}, args...)...)
+		assertContains(t, out, "****abcd")
+		assertNotContains(t, out, "etherscan-secret-key-abcd")
+
+		out = e.mustRun(args...)
+		assertContains(t, out, "********")
+		assertNotContains(t, out, "****abcd", "etherscan-secret-key-abcd")
+	}
+	out, _ := e.run("--mask-style", "last4", "diff", path)
+	assertContains(t, out, "~ api_keys.etherscan: ****abcd → ****wxyz")
+
+	assertContains(t, e.mustFail("--mask-style", "dots", "get", "api_keys.etherscan"), "Unknown mask style: dots")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+// matrixRow is one profile's line in `acm validate --profile-matrix`: the
+// outcome of every rule, "pass", "warn" or "fail"
+type matrixRow struct {
+	Profile string            `json:"profile"`
+	Cells   map[string]string `json:"cells,omitempty"`
+	Error   string            `json:"error,omitempty"` // the config didn't load
+}
+
+// matrixReport is the output of `acm validate --profile-matrix --json`
+type matrixReport struct {
+	Rules []string    `json:"rules"`
+	Rows  []matrixRow `json:"profiles"`
+}
+
+// ruleOutcome reduces a rule's findings to a single cell
+func ruleOutcome(findings []finding) string {
+	outcome := "pass"
+	for _, f := range findings {
+		if f.Severity == "error" {
+			return "fail"
+		}
+		outcome = "warn"
+	}
+	return outcome
+}
+
+// validateProfileMatrix runs rules against every profile and prints a table
+// with a row per profile and a column per rule
+func validateProfileMatrix(rules []validationRule, asJSON bool) {
+	profiles := loadAllProfiles()
+	if len(profiles) == 0 {
+		fmt.Println("No profiles found. Run 'acm init' to create one.")
+		return
+	}
+
+	report := matrixReport{Rules: []string{}}
+	for _, rule := range rules {
+		report.Rules = append(report.Rules, rule.ID)
+	}
+	for _, p := range profiles {
+		row := matrixRow{Profile: p.Name}
+		if p.Err != nil {
+			row.Error = p.Err.Error()
+		} else {
+			row.Cells = map[string]string{}
+			ctx := validationContext{Dir: filepath.Dir(p.Path)}
+			for _, rule := range rules {
+				row.Cells[rule.ID] = ruleOutcome(rule.Check(p.Config, ctx))
+			}
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	if asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, "PROFILE")
+	for _, id := range report.Rules {
+		fmt.Fprintf(w, "\t%s", id)
+	}
+	fmt.Fprintln(w)
+	for _, row := range report.Rows {
+		fmt.Fprint(w, row.Profile)
+		for _, id := range report.Rules {
+			cell := row.Cells[id]
+			if row.Error != "" {
+				cell = "-"
+			}
+			fmt.Fprintf(w, "\t%s", cell)
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+
+	for _, row := range report.Rows {
+		if row.Error != "" {
+			fmt.Printf("\n❌ %s failed to load: %s\n", row.Profile, row.Error)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// defaultNetworkTimeout bounds every outbound request unless --timeout says otherwise
+const defaultNetworkTimeout = 10 * time.Second
+
+// timeoutFlag registers the --timeout flag shared by all network commands
+func timeoutFlag(fs *flag.FlagSet) *time.Duration {
+	timeout := defaultNetworkTimeout
+	fs.Var(durationFlag{&timeout}, "timeout", "network timeout (e.g. 5s, 1m)")
+	return &timeout
+}
+
+// networkContext returns a context that is cancelled when the timeout
+// expires or the user presses Ctrl-C, so in-flight requests stop promptly
+func networkContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// newHTTPClient returns the HTTP client used for all network operations.
+// The timeout is a backstop; callers should also pass a networkContext.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout}
+}
+
+// describeNetworkError turns context failures into a readable reason
+func describeNetworkError(err error, timeout time.Duration) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Sprintf("timed out after %s", timeout)
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	default:
+		return err.Error()
+	}
+}
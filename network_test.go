@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// slowServer answers after delay, or gives up when the client goes away
+func slowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server only notices a client hanging up once the body is read
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNetworkContextCancelsAtDeadline(t *testing.T) {
+	server := slowServer(t, 5*time.Second)
+	timeout := 100 * time.Millisecond
+	ctx, cancel := networkContext(timeout)
+	defer cancel()
+
+	start := time.Now()
+	_, _, _, err := postWebhook(ctx, newHTTPClient(time.Minute), server.URL, []byte("{}"), "")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("request took %s, want it cancelled after %s", elapsed, timeout)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if got := describeNetworkError(err, timeout); got != "timed out after 100ms" {
+		t.Errorf("describeNetworkError = %q", got)
+	}
+}
+
+func TestDescribeNetworkErrorCancelled(t *testing.T) {
+	if got := describeNetworkError(context.Canceled, time.Second); got != "cancelled" {
+		t.Errorf("describeNetworkError = %q, want cancelled", got)
+	}
+	if got := describeNetworkError(errors.New("connection refused"), time.Second); got != "connection refused" {
+		t.Errorf("describeNetworkError = %q", got)
+	}
+}
+
+func TestTimeoutFlag(t *testing.T) {
+	server := slowServer(t, 5*time.Second)
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.webhook_url", server.URL)
+
+	start := time.Now()
+	out := e.mustFail("webhook", "test", "--timeout", "200ms")
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("webhook test took %s with --timeout 200ms", elapsed)
+	}
+	assertContains(t, out, "timed out after 200ms")
+
+	assertContains(t, e.mustFail("webhook", "test", "--timeout", "soon"), "soon")
+}
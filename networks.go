@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// networkInfo describes a chain the monitoring tools know how to talk to
+type networkInfo struct {
+	ChainID  int64
+	Explorer string
+	// ExplorerAPI is the Etherscan-style API for the chain and APIKey the
+	// config key holding its key; both are empty when acm has no key for it
+	ExplorerAPI string
+	APIKey      string
+}
+
+// knownNetworks is the registry of supported values for wallet.networks
+var knownNetworks = map[string]networkInfo{
+	"ethereum":     {ChainID: 1, Explorer: "https://etherscan.io", ExplorerAPI: "https://api.etherscan.io/api", APIKey: "api_keys.etherscan"},
+	"sepolia":      {ChainID: 11155111, Explorer: "https://sepolia.etherscan.io", ExplorerAPI: "https://api-sepolia.etherscan.io/api", APIKey: "api_keys.etherscan"},
+	"base":         {ChainID: 8453, Explorer: "https://basescan.org", ExplorerAPI: "https://api.basescan.org/api", APIKey: "api_keys.basescan"},
+	"base-sepolia": {ChainID: 84532, Explorer: "https://sepolia.basescan.org", ExplorerAPI: "https://api-sepolia.basescan.org/api", APIKey: "api_keys.basescan"},
+	"optimism":     {ChainID: 10, Explorer: "https://optimistic.etherscan.io"},
+	"arbitrum":     {ChainID: 42161, Explorer: "https://arbiscan.io"},
+	"polygon":      {ChainID: 137, Explorer: "https://polygonscan.com"},
+}
+
+// networksCommand handles `acm networks <subcommand>`, editing wallet.networks
+func networksCommand(args []string) {
+	if len(args) < 1 {
+		printNetworksUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list":
+		config := loadConfig()
+		if len(config.Wallet.Networks) == 0 {
+			fmt.Println("No networks configured")
+			return
+		}
+		for _, name := range config.Wallet.Networks {
+			fmt.Println(name)
+		}
+	case "add":
+		if len(args) < 2 {
+			printNetworksUsage()
+			os.Exit(1)
+		}
+		networksAdd(args[1])
+	case "remove":
+		if len(args) < 2 {
+			printNetworksUsage()
+			os.Exit(1)
+		}
+		networksRemove(args[1])
+	default:
+		printNetworksUsage()
+		os.Exit(1)
+	}
+}
+
+func printNetworksUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  acm networks list")
+	fmt.Println("  acm networks add <name>")
+	fmt.Println("  acm networks remove <name>")
+}
+
+func networksAdd(name string) {
+	name = strings.ToLower(name)
+	if _, ok := knownNetworks[name]; !ok {
+		fmt.Printf("❌ Unknown network: %s\n", name)
+		fmt.Printf("   Known networks: %s\n", strings.Join(sortedKeys(knownNetworks), ", "))
+		os.Exit(1)
+	}
+
+	config := loadConfig()
+	if indexOfNetwork(config.Wallet.Networks, name) >= 0 {
+		fmt.Printf("⚠️  %s is already configured\n", name)
+		return
+	}
+
+	config.Wallet.Networks = append(config.Wallet.Networks, name)
+	saveConfig(config)
+	fmt.Printf("✅ Added %s\n", name)
+}
+
+func networksRemove(name string) {
+	config := loadConfig()
+	i := indexOfNetwork(config.Wallet.Networks, name)
+	if i < 0 {
+		fmt.Printf("⚠️  %s is not configured\n", name)
+		return
+	}
+
+	name = config.Wallet.Networks[i]
+	config.Wallet.Networks = append(config.Wallet.Networks[:i], config.Wallet.Networks[i+1:]...)
+	saveConfig(config)
+	fmt.Printf("✅ Removed %s\n", name)
+
+	// Per-network settings are kept in case the network is added back
+	if settings, ok := config.Networks[name]; ok && settings != (NetworkSettings{}) {
+		fmt.Printf("⚠️  networks.%s still has an RPC URL or daily limit configured\n", name)
+	}
+}
+
+func indexOfNetwork(networks []string, name string) int {
+	for i, entry := range networks {
+		if strings.EqualFold(entry, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
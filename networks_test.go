@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func (e *testEnv) networks() string {
+	e.t.Helper()
+	return strings.Join(strings.Fields(e.mustRun("networks", "list")), ",")
+}
+
+func TestNetworksAddRemove(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+
+	assertContains(t, e.mustRun("networks", "add", "Sepolia"), "Added sepolia")
+	if got := e.networks(); got != "ethereum,base,sepolia" {
+		t.Errorf("networks = %s", got)
+	}
+	assertContains(t, e.mustRun("networks", "add", "sepolia"), "sepolia is already configured")
+	if got := e.networks(); got != "ethereum,base,sepolia" {
+		t.Errorf("networks after a duplicate add = %s", got)
+	}
+	assertContains(t, e.mustFail("networks", "add", "atlantis"), "Unknown network: atlantis", "Known networks: arbitrum, base")
+
+	assertContains(t, e.mustRun("networks", "remove", "ethereum"), "Removed ethereum")
+	if got := e.networks(); got != "base,sepolia" {
+		t.Errorf("networks after remove = %s", got)
+	}
+	assertContains(t, e.mustRun("networks", "remove", "ethereum"), "ethereum is not configured")
+}
+
+func TestNetworksRemoveWarnsAboutSettings(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.editConfig(func(config map[string]interface{}) {
+		section(config, "networks")["base"] = map[string]interface{}{"daily_limit": 0.2}
+	})
+
+	out := e.mustRun("networks", "remove", "base")
+	assertContains(t, out, "Removed base", "networks.base still has an RPC URL or daily limit configured")
+	assertNotContains(t, e.mustRun("networks", "remove", "ethereum"), "still has")
+	// kept in case the network is added back
+	assertContains(t, e.read(e.configPath()), `"daily_limit": 0.2`)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var nginxUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// exportNginx renders an upstream and location block proxying to the local
+// dashboard, optionally behind basic auth
+func exportNginx(config AgentConfig, opts exportOptions) ([]exportFile, error) {
+	m := config.Monitoring
+	if !m.DashboardEnabled {
+		return nil, errors.New("the dashboard is disabled (monitoring.dashboard_enabled is false)")
+	}
+	if m.DashboardPort < 1 || m.DashboardPort > 65535 {
+		return nil, fmt.Errorf("invalid dashboard port %d", m.DashboardPort)
+	}
+
+	upstream := "agent_dashboard"
+	if id := nginxUnsafeChars.ReplaceAllString(config.Agent.ID, "_"); id != "" {
+		upstream = id + "_dashboard"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by agent-config-manager v%s\n", version)
+	fmt.Fprintf(&b, "# Dashboard for %s\n", strings.ReplaceAll(config.Agent.Name, "\n", " "))
+	b.WriteString("\n# Goes in the http {} block\n")
+	fmt.Fprintf(&b, "upstream %s {\n", upstream)
+	fmt.Fprintf(&b, "    server 127.0.0.1:%d;\n", m.DashboardPort)
+	b.WriteString("}\n\n")
+	b.WriteString("# Goes in your server {} block\n")
+	b.WriteString("location / {\n")
+	if opts.AuthRealm != "" {
+		fmt.Fprintf(&b, "    auth_basic %s;\n", nginxQuote(opts.AuthRealm))
+		fmt.Fprintf(&b, "    auth_basic_user_file %s;\n", nginxQuote(opts.Htpasswd))
+	}
+	fmt.Fprintf(&b, "    proxy_pass http://%s;\n", upstream)
+	b.WriteString("    proxy_http_version 1.1;\n")
+	b.WriteString("    proxy_set_header Host $host;\n")
+	b.WriteString("    proxy_set_header X-Real-IP $remote_addr;\n")
+	b.WriteString("    proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;\n")
+	b.WriteString("    proxy_set_header X-Forwarded-Proto $scheme;\n")
+	b.WriteString("    proxy_set_header Upgrade $http_upgrade;\n")
+	b.WriteString("    proxy_set_header Connection \"upgrade\";\n")
+	b.WriteString("}\n")
+
+	return []exportFile{{Name: "nginx-dashboard.conf", Data: []byte(b.String())}}, nil
+}
+
+// nginxQuote double-quotes s for an nginx directive argument
+func nginxQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// checkNginxSyntax fails unless braces balance and every directive ends
+// with a semicolon
+func checkNginxSyntax(t *testing.T, conf string) {
+	t.Helper()
+	depth := 0
+	for i, line := range strings.Split(conf, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasSuffix(line, "{"):
+			depth++
+		case line == "}":
+			depth--
+			if depth < 0 {
+				t.Errorf("line %d: unbalanced }", i+1)
+			}
+		case !strings.HasSuffix(line, ";"):
+			t.Errorf("line %d: directive without ;: %s", i+1, line)
+		}
+	}
+	if depth != 0 {
+		t.Errorf("%d unclosed block(s):\n%s", depth, conf)
+	}
+}
+
+func nginxConf(t *testing.T, config AgentConfig, opts exportOptions) string {
+	t.Helper()
+	files, err := exportNginx(config, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(files[0].Data)
+}
+
+func TestExportNginx(t *testing.T) {
+	config := defaultConfig()
+	config.Monitoring.DashboardEnabled = true
+	config.Monitoring.DashboardPort = 9123
+	conf := nginxConf(t, config, exportOptions{})
+
+	checkNginxSyntax(t, conf)
+	assertContains(t, conf,
+		"# Dashboard for Arithmos",
+		"upstream arithmos_quillsworth_dashboard {",
+		"server 127.0.0.1:9123;",
+		"proxy_pass http://arithmos_quillsworth_dashboard;",
+	)
+	assertNotContains(t, conf, "auth_basic")
+}
+
+func TestExportNginxBasicAuth(t *testing.T) {
+	config := defaultConfig()
+	config.Monitoring.DashboardEnabled = true
+	conf := nginxConf(t, config, exportOptions{AuthRealm: `Agent "ops"`, Htpasswd: "/etc/nginx/.htpasswd"})
+
+	checkNginxSyntax(t, conf)
+	assertContains(t, conf, `auth_basic "Agent \"ops\"";`, `auth_basic_user_file "/etc/nginx/.htpasswd";`)
+}
+
+func TestExportNginxNeedsDashboard(t *testing.T) {
+	config := defaultConfig()
+	config.Monitoring.DashboardEnabled = false
+	if _, err := exportNginx(config, exportOptions{}); err == nil {
+		t.Error("exported nginx config for a disabled dashboard")
+	}
+	config.Monitoring.DashboardEnabled = true
+	config.Monitoring.DashboardPort = 0
+	if _, err := exportNginx(config, exportOptions{}); err == nil {
+		t.Error("exported nginx config for port 0")
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// passPrefix marks an API key encrypted with a passphrase instead of the
+// key file: encp:<base64(salt || nonce || AES-256-GCM ciphertext)>. The
+// AES key is derived from the passphrase and salt with scrypt.
+const passPrefix = "encp:"
+
+const passSaltSize = 16
+
+var errNoPassphrase = errors.New("API keys are encrypted with a passphrase: set ACM_PASSPHRASE or run in a terminal")
+
+var (
+	cachedPassphrase []byte
+	// derivedKeys caches the scrypt output per salt, since deriving is slow
+	// on purpose and every key in a file shares one salt
+	derivedKeys = map[string][]byte{}
+	// sealSalt is the salt for values encrypted in this run
+	sealSalt []byte
+)
+
+// isEncryptedSecret reports whether value is stored encrypted, with the key
+// file or a passphrase
+func isEncryptedSecret(value string) bool {
+	return strings.HasPrefix(value, encPrefix) || strings.HasPrefix(value, passPrefix)
+}
+
+// loadPassphrase returns the passphrase from ACM_PASSPHRASE, or asks for it
+// once when stdin is a terminal
+func loadPassphrase() ([]byte, error) {
+	if cachedPassphrase != nil {
+		return cachedPassphrase, nil
+	}
+	if env := os.Getenv("ACM_PASSPHRASE"); env != "" {
+		cachedPassphrase = []byte(env)
+		return cachedPassphrase, nil
+	}
+	if !isTerminal(os.Stdin) {
+		return nil, errNoPassphrase
+	}
+	passphrase, err := readPassphrase("🔑 Passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	cachedPassphrase = passphrase
+	return passphrase, nil
+}
+
+// readPassphrase prompts on stderr without echoing what is typed
+func readPassphrase(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	return passphrase, err
+}
+
+// newPassphrase asks for a passphrase twice for `acm encrypt`, unless
+// ACM_PASSPHRASE is set
+func newPassphrase(prompt string) ([]byte, error) {
+	if env := os.Getenv("ACM_PASSPHRASE"); env != "" {
+		return []byte(env), nil
+	}
+	if !isTerminal(os.Stdin) {
+		return nil, errors.New("no passphrase: set ACM_PASSPHRASE or run in a terminal")
+	}
+	first, err := readPassphrase("🔑 " + prompt + ": ")
+	if err != nil {
+		return nil, err
+	}
+	if len(first) == 0 {
+		return nil, errors.New("the passphrase can't be empty")
+	}
+	again, err := readPassphrase("🔑 Repeat " + strings.ToLower(prompt[:1]) + prompt[1:] + ": ")
+	if err != nil {
+		return nil, err
+	}
+	if string(first) != string(again) {
+		return nil, errors.New("the passphrases don't match")
+	}
+	return first, nil
+}
+
+func passphraseKey(salt []byte) ([]byte, error) {
+	if key, ok := derivedKeys[string(salt)]; ok {
+		return key, nil
+	}
+	passphrase, err := loadPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(passphrase, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	derivedKeys[string(salt)] = key
+	return key, nil
+}
+
+func encryptWithPassphrase(name, value string) (string, error) {
+	if sealSalt == nil {
+		sealSalt = make([]byte, passSaltSize)
+		if _, err := rand.Read(sealSalt); err != nil {
+			return "", err
+		}
+	}
+	key, err := passphraseKey(sealSalt)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(append(append([]byte{}, sealSalt...), nonce...), nonce, []byte(value), []byte(name))
+	return passPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptWithPassphrase(name, value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, passPrefix))
+	if err != nil || len(sealed) < passSaltSize {
+		return "", errors.New("malformed encrypted value")
+	}
+	key, err := passphraseKey(sealed[:passSaltSize])
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed = sealed[passSaltSize:]
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(name))
+	if err != nil {
+		return "", errors.New("wrong passphrase or corrupted value")
+	}
+	return string(plain), nil
+}
+
+// encryptCommand handles `acm encrypt`, switching the config to API keys
+// encrypted with a passphrase. Run on a config already encrypted this way
+// it changes the passphrase. The config and the files it includes are
+// rewritten; a new passphrase is refused while base profiles or local
+// overrides it loads keys from are encrypted with the current one.
+func encryptCommand() {
+	config := loadConfig()
+	path := getConfigPath()
+	current := cachedPassphrase
+
+	prompt := "Passphrase"
+	if encryptedConfigs[path] == passPrefix {
+		prompt = "New passphrase"
+	}
+	passphrase, err := newPassphrase(prompt)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if current != nil && string(current) != string(passphrase) {
+		if files := passphraseLayers(path, config); len(files) > 0 {
+			fmt.Println("❌ These files hold API keys encrypted with the current passphrase, which would no longer load:")
+			for _, file := range files {
+				fmt.Printf("   %s\n", file)
+			}
+			fmt.Println("💡 Move the keys into this config, or store them in plaintext, first")
+			os.Exit(1)
+		}
+	}
+	if err := resealLayers(path); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	cachedPassphrase, sealSalt = passphrase, nil
+
+	count := 0
+	for _, env := range apiKeyEnvVars {
+		if apiKeyValue(config, env.Key) != "" {
+			count++
+		}
+	}
+	if encryptedConfigs[path] == encPrefix {
+		fmt.Println("⚠️  Switching from the key file to a passphrase; secret.key is no longer needed for this config")
+	}
+	encryptedConfigs[path] = passPrefix
+	saveConfig(config)
+	fmt.Printf("✅ Encrypted %d API key(s) with your passphrase; keys set later are encrypted too\n", count)
+	fmt.Println("   Set ACM_PASSPHRASE for non-interactive use. A lost passphrase can't be recovered")
+}
+
+// passphraseLayers lists the files other than path and its fragments with
+// API keys encrypted with a passphrase that loading path decrypts: the local
+// overrides and the base profiles secrets are inherited from
+func passphraseLayers(path string, config AgentConfig) []string {
+	seen := map[string]bool{path: true}
+	var files []string
+	for _, origin := range fileSecretOrigins(path, config) {
+		if strings.HasPrefix(origin.Raw, passPrefix) && !seen[origin.File] {
+			seen[origin.File] = true
+			files = append(files, origin.File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// decryptCommand handles `acm decrypt`, storing API keys encrypted with a
+// passphrase in plaintext again
+func decryptCommand() {
+	decryptSchemeCommand(passPrefix)
+}
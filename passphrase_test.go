@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// usePassphrase sets ACM_PASSPHRASE for in-process encryption, dropping
+// anything derived from an earlier one
+func usePassphrase(t *testing.T, passphrase string) {
+	t.Helper()
+	t.Setenv("ACM_PASSPHRASE", passphrase)
+	cachedPassphrase, derivedKeys, sealSalt = nil, map[string][]byte{}, nil
+	t.Cleanup(func() { cachedPassphrase, derivedKeys, sealSalt = nil, map[string][]byte{}, nil })
+}
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	usePassphrase(t, "correct horse battery staple")
+	sealed, err := encryptWithPassphrase("api_keys.etherscan", "etherscan-secret-value-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, passPrefix) || !isEncryptedSecret(sealed) || strings.Contains(sealed, "etherscan-secret-value-123") {
+		t.Fatalf("sealed = %s", sealed)
+	}
+	if plain, err := decryptWithPassphrase("api_keys.etherscan", sealed); err != nil || plain != "etherscan-secret-value-123" {
+		t.Errorf("decrypted %q, %v", plain, err)
+	}
+	if _, err := decryptWithPassphrase("api_keys.basescan", sealed); err == nil {
+		t.Error("decrypted a value under another key name")
+	}
+	if _, err := decryptWithPassphrase("api_keys.etherscan", passPrefix+"AAAA"); err == nil || err.Error() != "malformed encrypted value" {
+		t.Errorf("malformed value: %v", err)
+	}
+
+	usePassphrase(t, "wrong")
+	if _, err := decryptWithPassphrase("api_keys.etherscan", sealed); err == nil || err.Error() != "wrong passphrase or corrupted value" {
+		t.Errorf("wrong passphrase: %v", err)
+	}
+}
+
+func TestEncryptDecryptCommands(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	assertContains(t, e.mustFail("encrypt"), "no passphrase: set ACM_PASSPHRASE or run in a terminal")
+
+	e.setenv("ACM_PASSPHRASE", "correct horse battery staple")
+	assertContains(t, e.mustRun("encrypt"), "Encrypted 1 API key(s) with your passphrase")
+	e.mustRun("set", "api_keys.basescan", "basescan-secret-value-123")
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(e.read(e.configPath())), &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"etherscan", "basescan"} {
+		if s, _ := section(raw, "api_keys")[name].(string); !strings.HasPrefix(s, passPrefix) {
+			t.Errorf("api_keys.%s = %v, want it encrypted", name, s)
+		}
+	}
+
+	// loading decrypts transparently, and show still masks
+	e.mustRun("export")
+	assertContains(t, e.read(filepath.Join(e.exportDir(), "wallet-monitor.json")), "etherscan-secret-value-123", "basescan-secret-value-123")
+	assertNotContains(t, e.mustRun("show"), "etherscan-secret-value-123")
+
+	e.setenv("ACM_PASSPHRASE", "wrong")
+	assertContains(t, e.mustFail("show"), "cannot decrypt api_keys.etherscan: wrong passphrase or corrupted value")
+	e.setenv("ACM_PASSPHRASE", "")
+	assertContains(t, e.mustFail("get", "agent.name"), "set ACM_PASSPHRASE or run in a terminal")
+
+	e.setenv("ACM_PASSPHRASE", "correct horse battery staple")
+	assertContains(t, e.mustRun("--yes", "decrypt"), "Decrypted API keys")
+	assertContains(t, e.read(e.configPath()), `"etherscan": "etherscan-secret-value-123"`, `"basescan": "basescan-secret-value-123"`)
+}
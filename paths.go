@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// expandPath normalizes a user-supplied path: a leading ~ or ~user becomes
+// that home directory and relative paths are made absolute against the
+// working directory. Shells don't expand ~ in --flag=~/x or in environment
+// variables, so without this acm would create a directory literally named ~.
+func expandPath(path string) string {
+	if path == "" {
+		return path
+	}
+	if strings.HasPrefix(path, "~") {
+		name, rest, _ := strings.Cut(path[1:], string(filepath.Separator))
+		if home := homeDirFor(name); home != "" {
+			path = filepath.Join(home, rest)
+		}
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// homeDirFor returns the home directory of the named user, or of the
+// current user when name is empty. Unknown users are left unexpanded.
+func homeDirFor(name string) string {
+	if name == "" {
+		home, _ := os.UserHomeDir()
+		return home
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return ""
+	}
+	return u.HomeDir
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withoutHome drops HOME from the commands e runs
+func (e *testEnv) withoutHome() {
+	env := e.env[:0]
+	for _, kv := range e.env {
+		if !strings.HasPrefix(kv, "HOME=") {
+			env = append(env, kv)
+		}
+	}
+	e.env = env
+}
+
+func TestNoHomeFailsClearly(t *testing.T) {
+	e := newTestEnv(t)
+	e.withoutHome()
+	out := e.mustFail("show")
+	assertContains(t, out, "Cannot determine the config location", "--config")
+	if _, err := os.Stat(filepath.Join(e.home, ".config")); err == nil {
+		t.Error("created .config relative to the working directory")
+	}
+}
+
+func TestNoHomeUsesFallbacks(t *testing.T) {
+	e := newTestEnv(t)
+	e.withoutHome()
+	config := filepath.Join(t.TempDir(), "agent.json")
+	e.setenv("ACM_CONFIG", config)
+	e.setenv("XDG_STATE_HOME", t.TempDir())
+	e.mustRun("init")
+	if _, err := os.Stat(config); err != nil {
+		t.Errorf("init with ACM_CONFIG: %v", err)
+	}
+
+	e = newTestEnv(t)
+	e.withoutHome()
+	xdg := t.TempDir()
+	e.setenv("XDG_CONFIG_HOME", xdg)
+	e.setenv("XDG_STATE_HOME", t.TempDir())
+	e.mustRun("init")
+	if _, err := os.Stat(filepath.Join(xdg, "agent", "config.json")); err != nil {
+		t.Errorf("init with XDG_CONFIG_HOME: %v", err)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home := isolatePaths(t)
+	for in, want := range map[string]string{
+		"~/agent.json": filepath.Join(home, "agent.json"),
+		"~":            home,
+		"/etc/acm":     "/etc/acm",
+		"":             "",
+	} {
+		if got := expandPath(in); got != want {
+			t.Errorf("expandPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+	wd, _ := os.Getwd()
+	if got := expandPath("agent.json"); got != filepath.Join(wd, "agent.json") {
+		t.Errorf("relative path = %s", got)
+	}
+}
+
+func TestExpandUserHome(t *testing.T) {
+	u, err := user.Current()
+	if err != nil || u.HomeDir == "" {
+		t.Skip("no current user")
+	}
+	if got, want := expandPath("~"+u.Username+"/agent.json"), filepath.Join(u.HomeDir, "agent.json"); got != want {
+		t.Errorf("~%s expanded to %s, want %s", u.Username, got, want)
+	}
+	wd, _ := os.Getwd()
+	if got := expandPath("~no-such-user-acm/agent.json"); got != filepath.Join(wd, "~no-such-user-acm", "agent.json") {
+		t.Errorf("unknown user expanded to %s", got)
+	}
+}
+
+func TestConfigFlagExpandsPaths(t *testing.T) {
+	e := newTestEnv(t)
+	e.mustRun("--config", "~/agents/foo.json", "init")
+	if _, err := os.Stat(filepath.Join(e.home, "agents", "foo.json")); err != nil {
+		t.Errorf("--config ~/...: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(e.home, "~")); err == nil {
+		t.Error("created a directory named ~")
+	}
+
+	// commands run in the home directory, so relative paths resolve there
+	e.mustRun("--config=agents/bar.json", "init")
+	if _, err := os.Stat(filepath.Join(e.home, "agents", "bar.json")); err != nil {
+		t.Errorf("relative --config: %v", err)
+	}
+
+	e.setenv("ACM_CONFIG", "~/agents/foo.json")
+	assertContains(t, e.mustRun("info"), filepath.Join(e.home, "agents", "foo.json")+" (✅ found)")
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultProfile is the profile stored at the base config path
+const defaultProfile = "default"
+
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ProfileIndex holds per-profile metadata, keyed by profile name
+type ProfileIndex struct {
+	Profiles map[string]ProfileMeta `json:"profiles"`
+	// Linked means the canonical config path follows the active profile
+	Linked bool `json:"linked,omitempty"`
+}
+
+type ProfileMeta struct {
+	Description string `json:"description,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+// activeProfile resolves the profile in use: --profile, then ACM_PROFILE,
+// then the profile last selected with `acm profile use`
+func activeProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	if env := os.Getenv("ACM_PROFILE"); env != "" {
+		return env
+	}
+	if data, err := os.ReadFile(filepath.Join(getBaseDir(), "active_profile")); err == nil {
+		if name := strings.TrimSpace(string(data)); name != "" {
+			return name
+		}
+	}
+	return defaultProfile
+}
+
+func profileDir(name string) string {
+	return filepath.Join(getBaseDir(), "profiles", name)
+}
+
+// profileConfigPath is where a profile's config lives. The default profile
+// uses the canonical path until `profile use --link` moves it aside.
+func profileConfigPath(name string) string {
+	if name == defaultProfile && !defaultProfileMoved() {
+		return canonicalConfigPath()
+	}
+	return filepath.Join(profileDir(name), "config.json")
+}
+
+func profileExportDir(name string) string {
+	if name == defaultProfile {
+		return filepath.Join(exportBaseDir(), "exports")
+	}
+	return filepath.Join(exportBaseDir(), "exports", name)
+}
+
+// getExportDir is where `acm export` writes for the current config. An
+// explicit --config or ACM_CONFIG keeps its exports next to the file, or in
+// --config-dir when that is set.
+func getExportDir() string {
+	if path := explicitConfigPath(); path != "" {
+		if dir := explicitStateDir(); dir != "" {
+			return filepath.Join(dir, "exports")
+		}
+		return filepath.Join(filepath.Dir(path), "exports")
+	}
+	return profileExportDir(activeProfile())
+}
+
+func profileExists(name string) bool {
+	_, err := os.Stat(profileConfigPath(name))
+	return err == nil
+}
+
+// listProfiles returns every profile with a config on disk, sorted by name
+func listProfiles() []string {
+	var names []string
+	if profileExists(defaultProfile) {
+		names = append(names, defaultProfile)
+	}
+	entries, _ := os.ReadDir(filepath.Join(getBaseDir(), "profiles"))
+	for _, entry := range entries {
+		if entry.Name() == defaultProfile {
+			continue
+		}
+		if entry.IsDir() && profileExists(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func profileIndexPath() string {
+	return filepath.Join(getBaseDir(), "profiles.json")
+}
+
+func loadProfileIndex() ProfileIndex {
+	index := ProfileIndex{Profiles: map[string]ProfileMeta{}}
+	data, err := os.ReadFile(profileIndexPath())
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		fmt.Printf("⚠️  Ignoring unreadable profile index: %v\n", err)
+	}
+	if index.Profiles == nil {
+		index.Profiles = map[string]ProfileMeta{}
+	}
+	return index
+}
+
+func saveProfileIndex(index ProfileIndex) {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		fmt.Printf("❌ Failed to marshal profile index: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(profileIndexPath(), data, 0600); err != nil {
+		fmt.Printf("❌ Failed to write profile index: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func validateProfileName(name string) {
+	if !profileNamePattern.MatchString(name) {
+		fmt.Printf("❌ Invalid profile name: %s\n", name)
+		fmt.Println("   Use lowercase letters, digits, '-' and '_'")
+		os.Exit(1)
+	}
+}
+
+// profileCommand handles `acm profile <subcommand>`
+func profileCommand(args []string) {
+	if len(args) < 1 {
+		printProfileUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list":
+		profileList(args[1:])
+	case "create":
+		profileCreate(args[1:])
+	case "use":
+		profileUse(args[1:])
+	case "rename":
+		if len(args) < 3 {
+			fmt.Println("Usage: acm profile rename <old> <new>")
+			os.Exit(1)
+		}
+		profileRename(args[1], args[2])
+	case "delete":
+		profileDelete(args[1:])
+	default:
+		printProfileUsage()
+		os.Exit(1)
+	}
+}
+
+func printProfileUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  acm profile list [--long]")
+	fmt.Println("  acm profile create <name> [--description <text>] [--extends <base>]")
+	fmt.Println("  acm profile use <name> [--link]")
+	fmt.Println("  acm profile rename <old> <new>")
+	fmt.Println("  acm profile delete <name>")
+}
+
+func profileList(args []string) {
+	fs := flag.NewFlagSet("profile list", flag.ExitOnError)
+	long := fs.Bool("long", false, "show description, creation time and path")
+	parseFlags(fs, args)
+
+	names := listProfiles()
+	if len(names) == 0 {
+		fmt.Println("No profiles found. Run 'acm init' to create one.")
+		return
+	}
+
+	active := activeProfile()
+	index := loadProfileIndex()
+	for _, name := range names {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		if !*long {
+			fmt.Printf("%s %s\n", marker, name)
+			continue
+		}
+		meta := index.Profiles[name]
+		fmt.Printf("%s %s\n", marker, name)
+		if meta.Description != "" {
+			fmt.Printf("    Description: %s\n", meta.Description)
+		}
+		if meta.CreatedAt != "" {
+			fmt.Printf("    Created:     %s\n", meta.CreatedAt)
+		}
+		fmt.Printf("    Path:        %s\n", profileConfigPath(name))
+	}
+}
+
+func profileCreate(args []string) {
+	fs := flag.NewFlagSet("profile create", flag.ExitOnError)
+	description := fs.String("description", "", "what this profile is for")
+	extends := fs.String("extends", "", "inherit unset fields from this profile")
+	positional := parseFlags(fs, args)
+	if len(positional) < 1 {
+		fmt.Println("Usage: acm profile create <name> [--description <text>] [--extends <base>]")
+		os.Exit(1)
+	}
+
+	name := positional[0]
+	validateProfileName(name)
+	if profileExists(name) {
+		fmt.Printf("❌ Profile %s already exists\n", name)
+		os.Exit(1)
+	}
+
+	path := profileConfigPath(name)
+	os.MkdirAll(filepath.Dir(path), 0755)
+
+	if *extends != "" {
+		if !profileExists(*extends) {
+			fmt.Printf("❌ Base profile %s not found\n", *extends)
+			os.Exit(1)
+		}
+		// Start empty so every field is inherited until set
+		data, _ := json.MarshalIndent(map[string]string{"version": version, "extends": *extends}, "", "  ")
+		if err := writeFileAtomic(path, data, 0600); err != nil {
+			fmt.Printf("❌ Failed to write config: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		saveConfigTo(path, defaultConfig())
+	}
+
+	index := loadProfileIndex()
+	index.Profiles[name] = ProfileMeta{
+		Description: *description,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	saveProfileIndex(index)
+
+	fmt.Printf("✅ Created profile %s at %s\n", name, path)
+	fmt.Printf("   Switch to it with: acm profile use %s\n", name)
+}
+
+func profileUse(args []string) {
+	fs := flag.NewFlagSet("profile use", flag.ExitOnError)
+	link := fs.Bool("link", false, "point the canonical config path at this profile for external tools")
+	positional := parseFlags(fs, args)
+	if len(positional) != 1 {
+		fmt.Println("Usage: acm profile use <name> [--link]")
+		os.Exit(1)
+	}
+	name := positional[0]
+	if !profileExists(name) {
+		fmt.Printf("❌ Profile %s not found\n", name)
+		os.Exit(1)
+	}
+
+	if err := setActiveProfile(name); err != nil {
+		fmt.Printf("❌ Failed to switch profile: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Active profile is now %s\n", name)
+
+	// Once linked, every switch keeps the canonical path in step
+	if *link || loadProfileIndex().Linked {
+		linkCanonicalConfig(name)
+	}
+}
+
+func setActiveProfile(name string) error {
+	return os.WriteFile(filepath.Join(getBaseDir(), "active_profile"), []byte(name+"\n"), 0600)
+}
+
+// profileRename moves a profile's config and export directory and carries
+// its metadata over to the new name
+func profileRename(oldName, newName string) {
+	if oldName == defaultProfile || newName == defaultProfile {
+		fmt.Println("❌ The default profile cannot be renamed")
+		os.Exit(1)
+	}
+	validateProfileName(newName)
+	if !profileExists(oldName) {
+		fmt.Printf("❌ Profile %s not found\n", oldName)
+		os.Exit(1)
+	}
+	if profileExists(newName) {
+		fmt.Printf("❌ Profile %s already exists\n", newName)
+		os.Exit(1)
+	}
+
+	if err := os.Rename(profileDir(oldName), profileDir(newName)); err != nil {
+		fmt.Printf("❌ Failed to rename profile: %v\n", err)
+		os.Exit(1)
+	}
+
+	if _, err := os.Stat(profileExportDir(oldName)); err == nil {
+		if err := os.Rename(profileExportDir(oldName), profileExportDir(newName)); err != nil {
+			fmt.Printf("⚠️  Renamed profile but failed to move exports: %v\n", err)
+		}
+	}
+	if _, err := os.Stat(profileStateDir(oldName)); err == nil {
+		os.MkdirAll(filepath.Dir(profileStateDir(newName)), 0700)
+		if err := os.Rename(profileStateDir(oldName), profileStateDir(newName)); err != nil {
+			fmt.Printf("⚠️  Renamed profile but failed to move its snapshots and backups: %v\n", err)
+		}
+	}
+
+	index := loadProfileIndex()
+	if meta, ok := index.Profiles[oldName]; ok {
+		index.Profiles[newName] = meta
+		delete(index.Profiles, oldName)
+		saveProfileIndex(index)
+	}
+
+	if activeProfile() == oldName && profileOverride == "" && os.Getenv("ACM_PROFILE") == "" {
+		if err := setActiveProfile(newName); err != nil {
+			fmt.Printf("⚠️  Renamed profile but failed to update the active profile: %v\n", err)
+		} else if index.Linked {
+			linkCanonicalConfig(newName)
+		}
+	}
+
+	fmt.Printf("✅ Renamed profile %s to %s\n", oldName, newName)
+}
+
+func profileDelete(args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: acm profile delete <name>")
+		os.Exit(1)
+	}
+
+	name := args[0]
+	if name == defaultProfile {
+		fmt.Println("❌ The default profile cannot be deleted")
+		os.Exit(1)
+	}
+	if !profileExists(name) {
+		fmt.Printf("❌ Profile %s not found\n", name)
+		os.Exit(1)
+	}
+	confirmOrExit(fmt.Sprintf("Permanently delete profile %s with its exports, snapshots and backups?", name))
+
+	if err := os.RemoveAll(profileDir(name)); err != nil {
+		fmt.Printf("❌ Failed to delete profile: %v\n", err)
+		os.Exit(1)
+	}
+	os.RemoveAll(profileExportDir(name))
+	os.RemoveAll(profileStateDir(name))
+
+	index := loadProfileIndex()
+	if _, ok := index.Profiles[name]; ok {
+		delete(index.Profiles, name)
+		saveProfileIndex(index)
+	}
+
+	if activeProfile() == name {
+		os.Remove(filepath.Join(getBaseDir(), "active_profile"))
+		if index.Linked && profileExists(defaultProfile) {
+			linkCanonicalConfig(defaultProfile)
+		}
+	}
+
+	fmt.Printf("✅ Deleted profile %s\n", name)
+}
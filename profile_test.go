@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileRenameMovesExportsAndState(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "testnet", "--description", "Sepolia agent")
+	e.mustRun("--profile", "testnet", "export")
+	e.mustRun("--profile", "testnet", "snapshot", "s1")
+
+	e.mustRun("profile", "rename", "testnet", "sepolia")
+
+	exports := filepath.Join(e.home, ".config", "agent", "exports")
+	if _, err := os.Stat(filepath.Join(exports, "sepolia", "wallet-monitor.json")); err != nil {
+		t.Errorf("exports didn't move: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(exports, "testnet")); !os.IsNotExist(err) {
+		t.Errorf("old export dir still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(e.stateDir(), "profiles", "sepolia", "snapshots", "s1.json")); err != nil {
+		t.Errorf("snapshots didn't move: %v", err)
+	}
+
+	out := e.mustRun("profile", "list", "--long")
+	assertContains(t, out, "sepolia", "Description: Sepolia agent", "Created:")
+	assertNotContains(t, out, "testnet")
+}
+
+func TestProfileRenameCollision(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "testnet")
+	e.mustRun("profile", "create", "mainnet")
+
+	assertContains(t, e.mustFail("profile", "rename", "testnet", "mainnet"), "Profile mainnet already exists")
+	assertContains(t, e.mustFail("profile", "rename", "missing", "other"), "Profile missing not found")
+	assertContains(t, e.mustFail("profile", "rename", "default", "other"), "default profile cannot be renamed")
+	// Both profiles are untouched
+	out := e.mustRun("profile", "list")
+	assertContains(t, out, "testnet", "mainnet")
+}
+
+func TestProfileRenameFollowsActiveProfile(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "testnet")
+	e.mustRun("profile", "use", "testnet")
+	e.mustRun("profile", "rename", "testnet", "sepolia")
+	assertContains(t, e.mustRun("profile", "list"), "* sepolia")
+}
+
+func TestProfileUseLink(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "agent.name", "mainnet-agent")
+	e.mustRun("profile", "create", "testnet")
+	e.mustRun("--profile", "testnet", "set", "agent.name", "testnet-agent")
+
+	out := e.mustRun("profile", "use", "testnet", "--link")
+	assertContains(t, out, "now points at profile testnet")
+	canonical := e.configPath()
+	info, err := os.Lstat(canonical)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("%s is not a symlink: %v", canonical, err)
+	}
+	if target, _ := os.Readlink(canonical); target != filepath.Join(e.home, ".config", "agent", "profiles", "testnet", "config.json") {
+		t.Errorf("link target = %s", target)
+	}
+	assertContains(t, e.read(canonical), `"testnet-agent"`)
+
+	// the default profile moved aside rather than being overwritten
+	moved := filepath.Join(e.home, ".config", "agent", "profiles", "default", "config.json")
+	assertContains(t, e.read(moved), `"mainnet-agent"`)
+
+	// later switches keep the link in step without --link
+	e.mustRun("profile", "use", "default")
+	assertContains(t, e.read(canonical), `"mainnet-agent"`)
+	assertContains(t, e.mustRun("get", "agent.name"), "mainnet-agent")
+	e.mustRun("set", "agent.name", "renamed-agent")
+	assertContains(t, e.read(moved), `"renamed-agent"`)
+	if info, err := os.Lstat(canonical); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("saving replaced the link: %v", err)
+	}
+}
+
+func TestCloneProfile(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "mainnet", "--description", "Mainnet agent")
+	e.mustRun("--profile", "mainnet", "set", "agent.id", "agent-mainnet-1")
+	e.mustRun("--profile", "mainnet", "set", "wallet.daily_limit", "1.5")
+	e.mustRun("--profile", "mainnet", "set", "api_keys.etherscan", "mainnet-etherscan-key-123456")
+
+	out := e.mustRun("clone-profile", "mainnet", "staging")
+	assertContains(t, out, "Cloned profile mainnet to staging at "+e.profilePath("staging"), "are shared with mainnet")
+	assertContains(t, e.mustRun("--profile", "staging", "get", "wallet.daily_limit"), "1.5")
+	assertContains(t, e.mustRun("--profile", "staging", "get", "agent.id"), "agent-mainnet-1")
+	e.mustRun("--profile", "staging", "get", "--exists", "api_keys.etherscan")
+	assertContains(t, e.mustRun("profile", "list", "--long"), "staging", "Description: Mainnet agent")
+
+	assertContains(t, e.mustFail("clone-profile", "mainnet", "staging"), "Profile staging already exists")
+	assertContains(t, e.mustFail("clone-profile", "nope", "other"), "Profile nope not found")
+}
+
+func TestCloneProfileNoSecrets(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "mainnet")
+	e.mustRun("--profile", "mainnet", "set", "agent.id", "agent-mainnet-1")
+	e.mustRun("--profile", "mainnet", "set", "api_keys.etherscan", "mainnet-etherscan-key-123456")
+	e.mustRun("--profile", "mainnet", "set", "monitoring.webhook_secret", "whsec-mainnet-secret-123456")
+
+	out := e.mustRun("clone-profile", "mainnet", "fresh", "--no-secrets", "--new-identity")
+	assertContains(t, out, "Secrets were left out", "Set a new identity before using it: agent.id, agent.erc8004_id, wallet.address")
+	clone := e.read(e.profilePath("fresh"))
+	assertNotContains(t, clone, "mainnet-etherscan-key-123456", "whsec-mainnet-secret-123456", "agent-mainnet-1", defaultConfig().Wallet.Address)
+	if _, code := e.run("--profile", "fresh", "get", "--exists", "api_keys.etherscan"); code != 1 {
+		t.Errorf("get --exists on the stripped key exited %d, want 1", code)
+	}
+	out, _ = e.run("--profile", "fresh", "validate")
+	assertContains(t, out, "Wallet address not set")
+
+	// the source keeps everything
+	e.mustRun("--profile", "mainnet", "get", "--exists", "api_keys.etherscan")
+	assertContains(t, e.mustRun("--profile", "mainnet", "get", "agent.id"), "agent-mainnet-1")
+}
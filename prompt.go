@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// assumeYes is set by the global --yes/-y flag or ACM_ASSUME_YES
+var assumeYes bool
+
+var errNoTTY = errors.New("confirmation required but stdin is not a terminal (re-run with --yes)")
+
+// confirm asks a yes/no question. It answers yes without asking under
+// --yes, and fails instead of hanging when there is no terminal to ask on.
+func confirm(question string) (bool, error) {
+	if assumeYes || envBool("ACM_ASSUME_YES") {
+		return true, nil
+	}
+	if !isTerminal(os.Stdin) {
+		return false, errNoTTY
+	}
+
+	fmt.Printf("%s [y/N]: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// confirmOrExit asks question and exits unless the user agrees
+func confirmOrExit(question string) {
+	ok, err := confirm(question)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		os.Exit(1)
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+func envBool(name string) bool {
+	v, err := strconv.ParseBool(os.Getenv(name))
+	return err == nil && v
+}
@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestPromptWithoutTTYFails(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "agent.name", "Changed")
+
+	// answering "y" on a pipe isn't enough: without a terminal acm refuses
+	// rather than reading the answer or waiting for one
+	out, code := e.runInput("y\n", "init", "--force")
+	if code == 0 {
+		t.Fatalf("init --force without a terminal succeeded:\n%s", out)
+	}
+	assertContains(t, out, "stdin is not a terminal", "--yes")
+	assertContains(t, e.mustRun("get", "agent.name"), "Changed")
+}
+
+func TestYesConfirmsPrompts(t *testing.T) {
+	for _, flag := range []string{"--yes", "-y"} {
+		e := newTestEnv(t)
+		e.init()
+		e.mustRun("set", "agent.name", "Changed")
+		e.mustRun(flag, "init", "--force")
+		assertContains(t, e.mustRun("get", "agent.name"), "Arithmos")
+	}
+
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "agent.name", "Changed")
+	e.setenv("ACM_ASSUME_YES", "1")
+	e.mustRun("init", "--force")
+	assertContains(t, e.mustRun("get", "agent.name"), "Arithmos")
+}
+
+func TestYesAfterCommand(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "agent.name", "Changed")
+	e.mustRun("init", "--force", "--yes")
+	assertContains(t, e.mustRun("get", "agent.name"), "Arithmos")
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// secretOrigin is the file an on-disk secret was read from, with its value
+// as written there
+type secretOrigin struct {
+	File string
+	Raw  string
+}
+
+// secretProvenance loads the config at path and reports where each
+// effective secret comes from, in load precedence: the environment, then
+// the local overrides, the config itself and the base profiles it inherits
+// secrets from. The returned config has secrets resolved, so never save it.
+func secretProvenance(path string) (AgentConfig, map[string]secretSource, error) {
+	config, err := readConfig(path)
+	if err != nil {
+		return AgentConfig{}, nil, err
+	}
+	origins := fileSecretOrigins(path, config)
+	if err := expandConfigEnvRefs(&config); err != nil {
+		return AgentConfig{}, nil, err
+	}
+
+	sources := resolveSecrets(&config)
+	for key, source := range sources {
+		origin, ok := origins[key]
+		if !ok {
+			continue
+		}
+		switch source.Provider {
+		case "file":
+			source.File = origin.File
+			source.Encrypted = isEncryptedSecret(origin.Raw)
+			if hasEnvRefs(origin.Raw) {
+				source.Reference = origin.Raw
+			}
+		case "env":
+			source.Overrides = origin.File
+		}
+		sources[key] = source
+	}
+	return config, sources, nil
+}
+
+// fileSecretOrigins finds the file providing each API key that is set on
+// disk. Files are searched in precedence order, and base profiles only while
+// each child sets inherit_secrets.
+func fileSecretOrigins(path string, config AgentConfig) map[string]secretOrigin {
+	files := []string{}
+	if _, ok := localLayers[path]; ok {
+		files = append(files, localConfigPath(path))
+	}
+	files = append(files, path)
+
+	origins := map[string]secretOrigin{}
+	seen := map[string]bool{}
+	for i := 0; i < len(files); i++ {
+		file := files[i]
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		raw, err := rawConfigMap(file, data)
+		if err != nil {
+			continue
+		}
+		keys, _ := raw["api_keys"].(map[string]interface{})
+		for _, env := range apiKeyEnvVars {
+			value, _ := keys[strings.TrimPrefix(env.Key, "api_keys.")].(string)
+			if _, found := origins[env.Key]; !found && value != "" {
+				origins[env.Key] = secretOrigin{File: file, Raw: value}
+			}
+		}
+
+		// The local overrides file can't extend, so the chain continues
+		// from the config itself
+		if file == localConfigPath(path) {
+			continue
+		}
+		base, _ := raw["extends"].(string)
+		inherit, _ := raw["inherit_secrets"].(bool)
+		if base != "" && inherit && !seen[base] {
+			seen[base] = true
+			files = append(files, profileConfigPath(base))
+		}
+	}
+	return origins
+}
+
+// describeSource renders a secret's provenance on one line for people
+func describeSource(s secretSource) string {
+	switch s.Provider {
+	case "env":
+		if s.Overrides != "" {
+			return fmt.Sprintf("env %s (overrides %s)", s.EnvVar, s.Overrides)
+		}
+		return "env " + s.EnvVar
+	case "file":
+		desc := "file " + s.File
+		if s.Reference != "" {
+			desc += " via " + s.Reference
+		}
+		if s.Encrypted {
+			desc += " (encrypted)"
+		}
+		return desc
+	}
+	return s.Provider
+}
+
+// keysProvenanceCommand prints, per API key, where the effective value
+// comes from and the value masked
+func keysProvenanceCommand(args []string) {
+	fs := flag.NewFlagSet("keys provenance", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print as JSON")
+	parseFlags(fs, args)
+
+	path := getConfigPath()
+	config, sources, err := secretProvenance(path)
+	if err != nil {
+		exitWith(err, "❌ %v\n", err)
+	}
+
+	type keyProvenance struct {
+		secretSource
+		Masked string `json:"masked,omitempty"`
+	}
+	report := map[string]keyProvenance{}
+	for key, source := range sources {
+		report[key] = keyProvenance{secretSource: source, Masked: maskSecret(apiKeyValue(config, key))}
+	}
+	if *asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	keys := make([]string, 0, len(report))
+	for key := range report {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Printf("🔑 Secret sources for %s:\n", path)
+	fmt.Println()
+	for _, key := range keys {
+		p := report[key]
+		fmt.Println(strings.TrimRight(fmt.Sprintf("  %-20s %-14s %s", key, p.Masked, describeSource(p.secretSource)), " "))
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKeysProvenanceEnvOverridesFile(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-file-key-123456")
+	e.mustRun("set", "api_keys.basescan", "basescan-file-key-123456")
+	e.setenv("ETHERSCAN_API_KEY", "etherscan-env-key-654321")
+
+	var report map[string]secretSource
+	out := e.mustRun("keys", "provenance", "--json")
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	want := map[string]secretSource{
+		"api_keys.etherscan": {Provider: "env", EnvVar: "ETHERSCAN_API_KEY", Overrides: e.configPath()},
+		"api_keys.basescan":  {Provider: "file", File: e.configPath()},
+		"api_keys.discord":   {Provider: "unset"},
+	}
+	for key, source := range want {
+		if report[key] != source {
+			t.Errorf("%s = %+v, want %+v", key, report[key], source)
+		}
+	}
+	assertNotContains(t, out, "etherscan-env-key-654321", "etherscan-file-key-123456", "basescan-file-key-123456")
+
+	out = e.mustRun("keys", "provenance")
+	assertContains(t, out, "env ETHERSCAN_API_KEY (overrides "+e.configPath()+")", "file "+e.configPath())
+	assertNotContains(t, out, "etherscan-env-key-654321")
+
+	out = e.mustRun("info")
+	assertContains(t, out, "env ETHERSCAN_API_KEY (overrides "+e.configPath()+")")
+	var info diagnosticInfo
+	if err := json.Unmarshal([]byte(e.mustRun("info", "--json")), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Provenance["api_keys.etherscan"].secretSource != want["api_keys.etherscan"] {
+		t.Errorf("info provenance = %+v", info.Provenance["api_keys.etherscan"])
+	}
+}
+
+func TestKeysProvenanceLayers(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.write(e.localPath(), `{"api_keys": {"etherscan": "etherscan-local-key-123456"}}`)
+	e.setenv("BASESCAN_FROM_VAULT", "basescan-vault-key-123456")
+	e.mustRun("set", "--lazy", "api_keys.basescan", "${BASESCAN_FROM_VAULT}")
+
+	var report map[string]secretSource
+	if err := json.Unmarshal([]byte(e.mustRun("keys", "provenance", "--json")), &report); err != nil {
+		t.Fatal(err)
+	}
+	if source := report["api_keys.etherscan"]; source.Provider != "file" || source.File != e.localPath() {
+		t.Errorf("etherscan = %+v, want the local overrides file", source)
+	}
+	if source := report["api_keys.basescan"]; source.Provider != "file" || source.Reference != "${BASESCAN_FROM_VAULT}" {
+		t.Errorf("basescan = %+v, want the ${VAR} reference", source)
+	}
+}
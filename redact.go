@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const redactedValue = `"<redacted>"`
+
+// redactCommand writes a copy of a config file that is safe to share: every
+// secret is replaced by "<redacted>" and, with --mask-address, the wallet
+// address is partially masked. Everything else, including comments and key
+// order, is kept byte for byte.
+func redactCommand(args []string) {
+	fs := flag.NewFlagSet("redact", flag.ExitOnError)
+	maskAddress := fs.Bool("mask-address", false, "also mask the middle of wallet.address")
+	positional := parseFlags(fs, args)
+	if len(positional) != 2 {
+		fmt.Println("Usage: acm redact <in> <out> [--mask-address]")
+		os.Exit(1)
+	}
+	in, out := expandPath(positional[0]), expandPath(positional[1])
+	if in == out {
+		fmt.Println("❌ Refusing to redact a config in place; choose another output file")
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(in)
+	if err != nil {
+		exitWith(ioError(in, err), "❌ Failed to read %s: %v\n", in, err)
+	}
+	if _, err := parseConfig(in, data); err != nil {
+		exitWith(parseError(in, err), "❌ Invalid config in %s: %v\n", in, err)
+	}
+
+	redacted, count := redactConfig(data, isJSONC(in), *maskAddress)
+
+	if _, err := os.Stat(out); err == nil {
+		confirmOrExit(fmt.Sprintf("%s already exists. Overwrite it?", out))
+	}
+	if err := writeFileAtomic(out, redacted, 0600); err != nil {
+		exitWith(ioError(out, err), "❌ Failed to write %s: %v\n", out, err)
+	}
+	fmt.Printf("✅ Wrote %s (%d value(s) redacted)\n", out, count)
+}
+
+// redactConfig replaces secret string values in data and returns the result
+// with the number of values replaced
+func redactConfig(data []byte, jsonc, maskAddress bool) ([]byte, int) {
+	scan := data
+	if jsonc {
+		// Stripping keeps byte offsets, so positions found in the stripped
+		// copy apply to the original
+		scan = stripJSONComments(data)
+	}
+
+	type replacement struct {
+		start, end int
+		value      string
+	}
+	var replacements []replacement
+	walkJSON(scan, func(t jsonToken) {
+		value, ok := t.Token.(string)
+		if t.IsKey || !ok || value == "" {
+			return
+		}
+		switch {
+		case isRedactedKey(t.Path):
+			replacements = append(replacements, replacement{t.Start, t.End, redactedValue})
+		case maskAddress && t.Path == "wallet.address":
+			replacements = append(replacements, replacement{t.Start, t.End, `"` + maskWalletAddress(value) + `"`})
+		}
+	})
+
+	var out []byte
+	last := 0
+	for _, r := range replacements {
+		out = append(out, data[last:r.start]...)
+		out = append(out, r.value...)
+		last = r.end
+	}
+	out = append(out, data[last:]...)
+	return out, len(replacements)
+}
+
+// isRedactedKey reports whether the value at key must not leave the machine.
+// Every api_keys entry counts, including ones acm doesn't know about.
+func isRedactedKey(key string) bool {
+	return strings.HasPrefix(key, "api_keys.") || isSecretKey(key)
+}
+
+// maskWalletAddress keeps enough of an address to recognise it
+func maskWalletAddress(address string) string {
+	if len(address) <= 12 {
+		return strings.Repeat("*", len(address))
+	}
+	return address[:6] + strings.Repeat("*", len(address)-10) + address[len(address)-4:]
+}
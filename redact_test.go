@@ -0,0 +1,68 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const redactInput = `{
+  // shared with support
+  "version": "0.1.0",
+  "agent": {"name": "Arithmos"},
+  "wallet": {"address": "0x120e011fB8a12bfcB61e5c1d751C26A5D33Aae91", "daily_limit": 0.5},
+  "api_keys": {
+    "etherscan": "etherscan-secret-value-123",
+    "openai": "sk-openai-abcdefghijklmnop",
+    "discord": "",
+    "custom_service": "custom-secret-value-456"
+  },
+  "monitoring": {"webhook_secret": "whsec-value-9876543210"}
+}`
+
+func TestRedactConfig(t *testing.T) {
+	out, count := redactConfig([]byte(redactInput), true, false)
+	if count != 4 {
+		t.Errorf("redacted %d values, want 4", count)
+	}
+	want := redactInput
+	for _, secret := range []string{"etherscan-secret-value-123", "sk-openai-abcdefghijklmnop", "custom-secret-value-456", "whsec-value-9876543210"} {
+		want = strings.Replace(want, `"`+secret+`"`, redactedValue, 1)
+	}
+	// everything but the secrets is kept byte for byte
+	if string(out) != want {
+		t.Errorf("redacted:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestRedactMaskAddress(t *testing.T) {
+	out, count := redactConfig([]byte(redactInput), true, true)
+	if count != 5 {
+		t.Errorf("redacted %d values, want 5", count)
+	}
+	masked := maskWalletAddress("0x120e011fB8a12bfcB61e5c1d751C26A5D33Aae91")
+	if masked != "0x120e"+strings.Repeat("*", 32)+"ae91" {
+		t.Errorf("masked address = %s", masked)
+	}
+	assertContains(t, string(out), `"address": "`+masked+`"`)
+}
+
+func TestRedactCommand(t *testing.T) {
+	e := newTestEnv(t)
+	in := filepath.Join(e.home, "agent.jsonc")
+	out := filepath.Join(e.home, "shareable.jsonc")
+	e.write(in, redactInput)
+
+	assertContains(t, e.mustRun("redact", in, out), "4 value(s) redacted")
+	shared := e.read(out)
+	assertContains(t, shared, "// shared with support", `"etherscan": "<redacted>"`)
+	assertNotContains(t, shared, "etherscan-secret-value-123", "whsec-value-9876543210")
+	if e.read(in) != redactInput {
+		t.Error("redact changed its input")
+	}
+
+	assertContains(t, e.mustFail("redact", in, in), "Refusing to redact a config in place")
+	assertContains(t, e.mustFail("redact", in, out), "--yes")
+	e.mustRun("--yes", "redact", in, out, "--mask-address")
+	assertNotContains(t, e.read(out), "0x120e011fB8a12bfcB61e5c1d751C26A5D33Aae91")
+}
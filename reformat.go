@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// reformatCommand rewrites the config with the canonical formatting acm
+// itself saves with, leaving every value as it is. With --check it only
+// reports whether the file is canonical, like gofmt -l.
+func reformatCommand(args []string) {
+	fs := flag.NewFlagSet("reformat", flag.ExitOnError)
+	check := fs.Bool("check", false, "exit 1 if the config is not canonically formatted, without writing")
+	parseFlags(fs, args)
+
+	path := getConfigPath()
+	data, err := readConfigFile(path)
+	if err != nil {
+		exitWith(ioError(path, err), "❌ Failed to read %s: %v\n", path, err)
+	}
+	formatted, err := canonicalConfig(path, data)
+	if err != nil {
+		exitWith(parseError(path, err), "❌ Cannot reformat %s: %v\n", path, err)
+	}
+
+	if bytes.Equal(formatted, data) {
+		fmt.Printf("✅ %s is already canonically formatted\n", path)
+		return
+	}
+	if *check {
+		fmt.Printf("❌ %s is not canonically formatted (run 'acm reformat')\n", path)
+		os.Exit(1)
+	}
+
+	if err := checkGitWorkTree(path); err != nil {
+		exitWith(ioError(path, err), "❌ %v\n", err)
+	}
+	if backup, err := backupConfig(); err != nil {
+		fmt.Printf("❌ Failed to back up the current config: %v\n", err)
+		os.Exit(1)
+	} else if backup != "" {
+		fmt.Printf("💾 Backed up current config to %s\n", backup)
+	}
+	if err := writeFileAtomic(path, formatted, 0600); err != nil {
+		exitWith(ioError(path, err), "❌ Failed to write config: %v\n", err)
+	}
+	fmt.Printf("✅ Reformatted %s\n", path)
+}
+
+// canonicalConfig renders data the way writeConfig would save it: 2-space
+// indent, fields in AgentConfig order and map keys sorted. A config that
+// extends a base profile or includes fragments keeps only its own keys,
+// sorted. Encrypted secrets stay encrypted, and a JSONC file keeps its
+// leading comment block.
+func canonicalConfig(path string, data []byte) ([]byte, error) {
+	// Fields the struct doesn't know would be lost on rewrite
+	if err := checkUnknownFields(path, data); err != nil {
+		return nil, err
+	}
+	config, err := parseConfig(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	if config.Extends == "" && len(config.Include) == 0 {
+		out, err = json.MarshalIndent(config, "", "  ")
+	} else {
+		out, err = canonicalPartial(path, data)
+	}
+	if err == nil && isYAML(path) {
+		if out, err = jsonToYAML(out); err == nil {
+			out = append(splitYAMLHeader(data), out...)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isJSONC(path) {
+		header, bodyHasComments := splitJSONCHeader(data)
+		if bodyHasComments {
+			fmt.Fprintln(os.Stderr, "⚠️  Only the comment block at the top of the file is kept")
+		}
+		out = append(header, out...)
+	}
+	return out, nil
+}
+
+// canonicalPartial re-encodes a child config's own keys, keeping numbers
+// exactly as written
+func canonicalPartial(path string, data []byte) ([]byte, error) {
+	data, err := normalizeEncoding(path, data)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = configJSON(path, data); err != nil {
+		return nil, err
+	}
+	data, err = migrateAliases(path, data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	raw := map[string]interface{}{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(raw, "", "  ")
+}
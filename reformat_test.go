@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestReformatCanonicalizes(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "networks.ethereum.rpc_url", "https://eth.example.com")
+	e.mustRun("set", "networks.base.daily_limit", "0.25")
+	canonical := e.read(e.configPath())
+	values := e.mustRun("get", "--all")
+
+	// Same values, hand-edited layout: 4-space indent, keys in another order
+	raw := map[string]interface{}{}
+	json.Unmarshal([]byte(canonical), &raw)
+	scrambled, _ := json.MarshalIndent(raw, "", "    ")
+	e.write(e.configPath(), string(scrambled))
+
+	assertContains(t, e.mustFail("reformat", "--check"), "is not canonically formatted")
+	if e.read(e.configPath()) != string(scrambled) {
+		t.Fatal("reformat --check wrote the file")
+	}
+
+	out := e.mustRun("reformat")
+	assertContains(t, out, "Backed up current config to ", "Reformatted "+e.configPath())
+	if got := e.read(e.configPath()); got != canonical {
+		t.Errorf("reformatted config:\n%s\nwant the canonical serialization:\n%s", got, canonical)
+	}
+	if got := e.mustRun("get", "--all"); got != values {
+		t.Errorf("reformat changed values:\n%s\nwant:\n%s", got, values)
+	}
+	backups, _ := filepath.Glob(filepath.Join(e.stateDir(), "backups", "*.json"))
+	if len(backups) == 0 || e.read(backups[len(backups)-1]) != string(scrambled) {
+		t.Errorf("no backup of the hand-edited file: %v", backups)
+	}
+
+	assertContains(t, e.mustRun("reformat", "--check"), "already canonically formatted")
+	assertContains(t, e.mustRun("reformat"), "already canonically formatted")
+}
+
+func TestReformatRefusesUnknownFields(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.editConfig(func(config map[string]interface{}) {
+		config["custom_section"] = map[string]interface{}{"kept": true}
+		section(config, "wallet")["bogus"] = 1
+	})
+	before := e.read(e.configPath())
+	assertContains(t, e.mustFail("reformat"), `unknown field "bogus"`)
+	if e.read(e.configPath()) != before {
+		t.Error("reformat rewrote a config with an unknown field")
+	}
+
+	// a whole section this version doesn't know survives the rewrite
+	e.editConfig(func(config map[string]interface{}) {
+		delete(section(config, "wallet"), "bogus")
+	})
+	e.mustRun("reformat")
+	assertContains(t, e.read(e.configPath()), "\"custom_section\": {\n    \"kept\": true\n  }")
+}
+
+func TestCanonicalConfigChildKeepsOwnKeys(t *testing.T) {
+	data := []byte(`{"wallet": {"daily_limit": 0.10}, "extends": "default"}`)
+	out, err := canonicalConfig("/tmp/child/config.json", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"extends\": \"default\",\n  \"wallet\": {\n    \"daily_limit\": 0.10\n  }\n}"
+	if string(out) != want {
+		t.Errorf("canonicalConfig = %s, want %s", out, want)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// checkExports renders the export in memory and compares it with what is
+// in exportDir, exiting 1 when a file is missing or stale. Nothing is written.
+func checkExports(job exportJob, config AgentConfig, exportDir string) {
+	files, err := job.render(config)
+	if err != nil {
+		printExportFailure("", err)
+		os.Exit(exitCode(err))
+	}
+
+	fmt.Printf("🔍 Checking exports in %s/\n", exportDir)
+	fmt.Println()
+	stale := 0
+	for _, file := range files {
+		if file.Volatile {
+			fmt.Printf("⏭️  %s: differs on every export, not compared\n", file.Name)
+			continue
+		}
+		existing, err := os.ReadFile(filepath.Join(exportDir, file.Name))
+		switch {
+		case err != nil:
+			fmt.Printf("❌ %s: missing\n", file.Name)
+			stale++
+		case !bytes.Equal(existing, file.Data):
+			fmt.Printf("❌ %s: out of date\n", file.Name)
+			stale++
+		default:
+			fmt.Printf("✅ %s\n", file.Name)
+		}
+	}
+
+	fmt.Println()
+	if stale > 0 {
+		fmt.Printf("Found %d stale file(s); run 'acm export' to update them\n", stale)
+		os.Exit(1)
+	}
+	fmt.Println("Exports are up to date")
+}
+
+// selftestExport renders the export twice and exits 1 unless both runs
+// produce identical bytes, which --check relies on
+func selftestExport(job exportJob, config AgentConfig) {
+	first, err := job.render(config)
+	if err == nil {
+		var second []exportFile
+		second, err = job.render(config)
+		if err == nil {
+			err = compareRenders(first, second)
+		}
+	}
+	if err != nil {
+		fmt.Printf("❌ Export is not reproducible: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Two exports produced identical bytes (%d file(s))\n", len(first))
+}
+
+func compareRenders(a, b []exportFile) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("%d files, then %d", len(a), len(b))
+	}
+	for i := range a {
+		switch {
+		case a[i].Name != b[i].Name:
+			return fmt.Errorf("file %d is %s, then %s", i+1, a[i].Name, b[i].Name)
+		case !a[i].Volatile && !bytes.Equal(a[i].Data, b[i].Data):
+			return fmt.Errorf("%s differs between runs", a[i].Name)
+		}
+	}
+	return nil
+}
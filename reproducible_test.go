@@ -0,0 +1,84 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// mapHeavyConfig has several entries in every map, so an exporter iterating
+// a map directly would produce a different order between runs
+func mapHeavyConfig() AgentConfig {
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-key-abcd"
+	config.Wallet.DailyLimit = 0.1 + 0.2
+	config.Networks = map[string]NetworkSettings{}
+	for _, name := range []string{"ethereum", "base", "sepolia", "base-sepolia", "optimism", "arbitrum", "polygon"} {
+		config.Networks[name] = NetworkSettings{RPCURL: "https://" + name + ".example.com", DailyLimit: 0.05}
+	}
+	config.APIKeysMeta = map[string]APIKeyMeta{"etherscan": {ExpiresAt: "2099-01-01"}, "basescan": {ExpiresAt: "2099-02-01"}}
+	config.Monitoring.DashboardEnabled = true
+	return config
+}
+
+func TestExportersAreDeterministic(t *testing.T) {
+	config := mapHeavyConfig()
+	opts := exportOptions{KVPrefix: "agent", Secrets: "inline", Host: "dashboard.example.com", Htpasswd: "/etc/nginx/.htpasswd"}
+	for format, exporter := range exportFormats {
+		first, err := exporter(config, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		for i := 0; i < 20; i++ {
+			again, err := exporter(config, opts)
+			if err != nil {
+				t.Fatalf("%s: %v", format, err)
+			}
+			if err := compareRenders(first, again); err != nil {
+				t.Errorf("%s: %v", format, err)
+				break
+			}
+		}
+	}
+}
+
+func TestCompareRenders(t *testing.T) {
+	a := []exportFile{{Name: "a.json", Data: []byte("1")}, {Name: "vault", Data: []byte("x"), Volatile: true}}
+	b := []exportFile{{Name: "a.json", Data: []byte("1")}, {Name: "vault", Data: []byte("y"), Volatile: true}}
+	if err := compareRenders(a, b); err != nil {
+		t.Errorf("volatile files were compared: %v", err)
+	}
+	b[0].Data = []byte("2")
+	if err := compareRenders(a, b); err == nil || err.Error() != "a.json differs between runs" {
+		t.Errorf("changed file: %v", err)
+	}
+	if err := compareRenders(a, b[:1]); err == nil {
+		t.Error("a missing file wasn't reported")
+	}
+}
+
+func TestExportCheckAndSelftest(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "networks.base.rpc_url", "https://base.example.com")
+	e.mustRun("set", "networks.ethereum.rpc_url", "https://eth.example.com")
+
+	assertContains(t, e.mustFail("export", "--check"), "wallet-monitor.json: missing")
+	e.mustRun("export")
+	monitor := filepath.Join(e.exportDir(), "wallet-monitor.json")
+	before := e.read(monitor)
+	assertContains(t, e.mustRun("export", "--check"), "Exports are up to date")
+	assertContains(t, e.mustRun("export", "--selftest"), "Two exports produced identical bytes")
+
+	// exporting an unchanged config rewrites the same bytes
+	e.mustRun("export")
+	if e.read(monitor) != before {
+		t.Error("re-exporting an unchanged config changed wallet-monitor.json")
+	}
+
+	e.mustRun("set", "wallet.daily_limit", "0.9")
+	out := e.mustFail("export", "--check")
+	assertContains(t, out, "❌ wallet-monitor.json: out of date", "run 'acm export' to update them")
+	if e.read(monitor) != before {
+		t.Error("--check wrote the export")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// secretSource says where the effective value of a secret came from.
+// resolveSecrets fills in Provider and EnvVar; secretProvenance adds the
+// file details.
+type secretSource struct {
+	Provider string `json:"provider"`          // "env", "file" or "unset"
+	EnvVar   string `json:"env_var,omitempty"` // set when Provider is "env"
+	// File holds the value when Provider is "file": the config, its local
+	// overrides or a base profile
+	File string `json:"file,omitempty"`
+	// Reference is the ${VAR} reference the file value expands from
+	Reference string `json:"reference,omitempty"`
+	Encrypted bool   `json:"encrypted,omitempty"`
+	// Overrides is the file whose value an environment variable replaces
+	Overrides string `json:"overrides,omitempty"`
+}
+
+func (s secretSource) String() string {
+	if s.Provider == "env" {
+		return "env:" + s.EnvVar
+	}
+	return s.Provider
+}
+
+// resolveSecrets overlays API keys found in the environment (under the
+// names shown by `acm env-map`, or the aliases accepted by `keys import`)
+// onto config and reports the source of every secret. The overlay must never
+// be saved back to disk, so only read paths use it.
+func resolveSecrets(config *AgentConfig) map[string]secretSource {
+	sources := map[string]secretSource{}
+	for _, env := range apiKeyEnvVars {
+		source := secretSource{Provider: "unset"}
+		if apiKeyValue(*config, env.Key) != "" {
+			source.Provider = "file"
+		}
+		for _, name := range env.Names {
+			if value := os.Getenv(name); value != "" {
+				env.Set(&config.APIKeys, value)
+				source = secretSource{Provider: "env", EnvVar: name}
+				break
+			}
+		}
+		sources[env.Key] = source
+	}
+	return sources
+}
+
+// loadEffectiveConfig loads the config with ${VAR} references expanded and
+// environment secrets applied. Use it for commands that read the config;
+// commands that save must use loadConfig so environment values are not
+// persisted.
+func loadEffectiveConfig() AgentConfig {
+	config := loadConfig()
+	if err := expandConfigEnvRefs(&config); err != nil {
+		exitWith(err, "❌ %v\n", err)
+	}
+	resolveSecrets(&config)
+	return config
+}
+
+// requiredSecrets are the secrets the exported tools cannot run without:
+// their API keys. The webhook secret is optional.
+func requiredSecrets() []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, service := range toolServices {
+		for _, key := range service.Keys {
+			if isSecretKey(key) && strings.HasPrefix(key, "api_keys.") && !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// placeholderPattern matches values copied from documentation rather than
+// real credentials: YOUR_KEY, <api-key>, changeme, xxx, ...
+var placeholderPattern = regexp.MustCompile(`(?i)^(your[_-].*|<.*>|\.\.\.|changeme|change[_-]me|replace[_-]?me|placeholder|todo|secret|api[_-]?key|x+)$`)
+
+// isPlaceholderSecret reports whether value looks like a documentation
+// placeholder, including a single character repeated (0000..., ****...)
+func isPlaceholderSecret(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+	if placeholderPattern.MatchString(value) {
+		return true
+	}
+	return len(value) > 1 && strings.Trim(value, value[:1]) == ""
+}
+
+// warnPlaceholderSecret warns, without failing, when key is being set to a
+// placeholder value
+func warnPlaceholderSecret(key, value string) {
+	if isPlaceholderSecret(value) {
+		fmt.Printf("⚠️  %s looks like a placeholder, not a real key\n", key)
+	}
+}
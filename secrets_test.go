@@ -0,0 +1,93 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExportUsesEnvSecrets(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.setenv("ETHERSCAN_API_KEY", "etherscan-from-env-123456")
+	e.mustRun("export")
+
+	assertContains(t, e.read(filepath.Join(e.exportDir(), "wallet-monitor.json")), "etherscan-from-env-123456")
+	assertNotContains(t, e.read(e.configPath()), "etherscan-from-env-123456")
+}
+
+func TestExportEnvSecretOverridesFile(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-from-file-123456")
+	e.setenv("ETHERSCAN_API_KEY", "etherscan-from-env-123456")
+	e.mustRun("export", "--format", "env")
+
+	out := e.read(filepath.Join(e.exportDir(), "agent.env"))
+	assertContains(t, out, "ETHERSCAN_API_KEY=etherscan-from-env-123456")
+	assertNotContains(t, out, "etherscan-from-file-123456")
+}
+
+func TestExportRequireSecrets(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.setenv("ETHERSCAN_API_KEY", "etherscan-from-env-123456")
+
+	out := e.mustFail("export", "--require-secrets")
+	assertContains(t, out, "Required secrets are not set: api_keys.basescan")
+	assertNotContains(t, out, "api_keys.etherscan")
+
+	e.mustRun("set", "api_keys.basescan", "basescan-secret-value-123")
+	e.mustRun("export", "--require-secrets")
+}
+
+func TestResolveSecretsSources(t *testing.T) {
+	for _, env := range apiKeyEnvVars {
+		for _, name := range env.Names {
+			t.Setenv(name, "")
+		}
+	}
+	t.Setenv("BASESCAN_API_KEY", "basescan-from-env-123456")
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-from-file-123456"
+
+	sources := resolveSecrets(&config)
+	if sources["api_keys.etherscan"].Provider != "file" {
+		t.Errorf("etherscan source = %+v, want file", sources["api_keys.etherscan"])
+	}
+	if source := sources["api_keys.basescan"]; source.Provider != "env" || source.EnvVar != "BASESCAN_API_KEY" {
+		t.Errorf("basescan source = %+v, want env BASESCAN_API_KEY", source)
+	}
+	if config.APIKeys.Basescan != "basescan-from-env-123456" {
+		t.Errorf("basescan = %q, want the env value", config.APIKeys.Basescan)
+	}
+	if sources["api_keys.openai"].Provider != "unset" {
+		t.Errorf("openai source = %+v, want unset", sources["api_keys.openai"])
+	}
+}
+
+func TestIsPlaceholderSecret(t *testing.T) {
+	for _, value := range []string{
+		"YOUR_KEY", "your_etherscan_api_key", "YOUR-API-KEY",
+		"<api-key>", "changeme", "CHANGE_ME",
+		"xxx", "XXXXXXXX", "00000000", "aaaa",
+	} {
+		if !isPlaceholderSecret(value) {
+			t.Errorf("%q not detected as a placeholder", value)
+		}
+	}
+	for _, value := range []string{"", "etherscan-secret-value-123", "sk-ant-api03-abcdef123456", "ABCD1234EFGH5678"} {
+		if isPlaceholderSecret(value) {
+			t.Errorf("%q detected as a placeholder", value)
+		}
+	}
+}
+
+func TestSetPlaceholderSecretWarns(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	out := e.mustRun("set", "api_keys.etherscan", "YOUR_KEY")
+	assertContains(t, out, "Set api_keys.etherscan", "api_keys.etherscan looks like a placeholder")
+
+	assertNotContains(t, e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123"), "placeholder")
+	assertNotContains(t, e.mustRun("set", "agent.name", "xxx"), "placeholder")
+}
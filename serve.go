@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// socketPath is where `acm serve` listens: ACM_SOCKET, or acm.sock next to
+// the config
+func socketPath() string {
+	if path := os.Getenv("ACM_SOCKET"); path != "" {
+		return expandPath(path)
+	}
+	return filepath.Join(stateDir(), "acm.sock")
+}
+
+// configCache holds the parsed config for `acm serve`, reloading it when
+// the file's size or modification time changes. The mutex also serializes
+// access to the loader's package-level state.
+type configCache struct {
+	mu      sync.Mutex
+	path    string
+	config  AgentConfig
+	modTime time.Time
+	size    int64
+	loaded  bool
+}
+
+// current returns the cached config, reloading it first if the file changed
+// since it was read. A file that no longer loads keeps the last good config.
+func (c *configCache) current() (AgentConfig, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		if c.loaded {
+			return c.config, nil
+		}
+		return AgentConfig{}, err
+	}
+	if c.loaded && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.config, nil
+	}
+
+	config, err := readConfig(c.path)
+	if err != nil {
+		if c.loaded {
+			fmt.Fprintf(os.Stderr, "⚠️  Keeping the previous config: %v\n", err)
+			return c.config, nil
+		}
+		return AgentConfig{}, err
+	}
+	if findings := runValidation(config, validationContext{Dir: c.dir()}, serveValidationRules()); hasErrors(findings) {
+		fmt.Fprintln(os.Stderr, "⚠️  Loaded config has validation errors (see 'acm validate')")
+	}
+
+	c.config, c.modTime, c.size, c.loaded = config, info.ModTime(), info.Size(), true
+	return config, nil
+}
+
+func (c *configCache) dir() string {
+	return filepath.Dir(c.path)
+}
+
+// serveValidationRules are the rules a server runs: all but the external
+// hooks, which shouldn't run on every reload or request
+func serveValidationRules() []validationRule {
+	rules := make([]validationRule, 0, len(validationRules))
+	for _, rule := range validationRules {
+		if rule.ID != "hooks" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// get renders key for a client, with environment secrets applied and
+// secrets masked like `acm get`
+func (c *configCache) get(key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config, err := c.current()
+	if err != nil {
+		return "", err
+	}
+	if err := expandConfigEnvRefs(&config); err != nil {
+		return "", err
+	}
+	resolveSecrets(&config)
+	return valueString(&config, key)
+}
+
+// set stores value under key and writes the config file
+func (c *configCache) set(key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config, err := c.current()
+	if err != nil {
+		return err
+	}
+	if err := assignValue(&config, key, value, true); err != nil {
+		return err
+	}
+	if err := writeConfig(c.path, config); err != nil {
+		return err
+	}
+	// Force a reload so the cache matches what is on disk
+	c.loaded = false
+	_, err = c.current()
+	return err
+}
+
+// serveCommand keeps the config loaded and answers get/set requests over a
+// unix socket, so scripts that call `acm get` in a loop skip re-parsing.
+// With --http it serves the JSON API of httpHandler over TCP instead.
+func serveCommand(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	httpAddr := fs.String("http", "", "serve the HTTP API on this address instead of the socket (a bare port binds 127.0.0.1)")
+	readonly := fs.Bool("readonly", false, "refuse requests that change the config")
+	allowWrites := fs.Bool("allow-writes", false, "with --http, accept PUT requests carrying the bearer token printed at startup")
+	parseFlags(fs, args)
+	if *allowWrites && (*readonly || *httpAddr == "") {
+		fmt.Println("❌ --allow-writes only applies to --http and can't be combined with --readonly")
+		os.Exit(1)
+	}
+
+	cache := &configCache{path: getConfigPath()}
+	if _, err := cache.current(); err != nil {
+		fmt.Printf("❌ Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	var listener net.Listener
+	var handler http.Handler
+	var path string
+	if *httpAddr != "" {
+		addr, loopback, err := httpListenAddr(*httpAddr)
+		if err != nil {
+			fmt.Printf("❌ Invalid --http address %s: %v\n", *httpAddr, err)
+			os.Exit(1)
+		}
+		if !loopback && *allowWrites {
+			fmt.Println("❌ --allow-writes requires a loopback address")
+			os.Exit(1)
+		}
+		opts := httpOptions{AllowWrites: *allowWrites, Loopback: loopback}
+		if opts.AllowWrites {
+			if opts.Token, err = newServeToken(); err != nil {
+				fmt.Printf("❌ Failed to generate a token: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if listener, err = net.Listen("tcp", addr); err != nil {
+			fmt.Printf("❌ Failed to listen on %s: %v\n", addr, err)
+			os.Exit(1)
+		}
+		handler = httpHandler(cache, opts)
+		path = "http://" + listener.Addr().String()
+		*readonly = !opts.AllowWrites
+		if opts.AllowWrites {
+			fmt.Printf("🔑 Write token: %s (send it as Authorization: Bearer <token>)\n", opts.Token)
+		}
+	} else {
+		path = socketPath()
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			fmt.Printf("❌ Another acm serve is already listening on %s\n", path)
+			os.Exit(1)
+		}
+		os.Remove(path)
+		os.MkdirAll(filepath.Dir(path), 0700)
+
+		var err error
+		if listener, err = net.Listen("unix", path); err != nil {
+			fmt.Printf("❌ Failed to listen on %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		os.Chmod(path, 0600)
+		defer os.Remove(path)
+		handler = socketHandler(cache, *readonly)
+	}
+
+	server := &http.Server{Handler: handler}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	mode := ""
+	if *readonly {
+		mode = " read-only"
+	}
+	fmt.Printf("✅ Serving %s%s on %s (Ctrl-C to stop)\n", cache.path, mode, path)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Printf("❌ %v\n", err)
+	}
+}
+
+// socketHandler implements the socket API:
+//
+//	GET  /get?key=<key>           the value as `acm get` prints it
+//	POST /set  key=<key>&value=   set a value and save the config, unless --readonly
+func socketHandler(cache *configCache, readonly bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+		key, err := resolveKey(r.URL.Query().Get("key"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		value, err := cache.get(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, value)
+	})
+	mux.HandleFunc("/set", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "use POST", http.StatusMethodNotAllowed)
+			return
+		}
+		if readonly {
+			http.Error(w, errReadonly.Error(), http.StatusForbidden)
+			return
+		}
+		key, err := resolveKey(r.PostFormValue("key"))
+		if err == nil {
+			err = cache.set(key, r.PostFormValue("value"))
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// socketClient talks HTTP to `acm serve` over its unix socket
+func socketClient(path string) *http.Client {
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// getViaSocket asks a running server for key. It reports false when no
+// server is reachable so the caller can read the file instead; errors from
+// the server itself are fatal.
+func getViaSocket(key string) (string, bool) {
+	resp, err := socketClient(socketPath()).Get("http://acm/get?key=" + url.QueryEscape(key))
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("❌ %s\n", strings.TrimSpace(string(body)))
+		os.Exit(1)
+	}
+	return strings.TrimSuffix(string(body), "\n"), true
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// serveTestConfig writes the default config and returns a cache for it
+func serveTestConfig(t *testing.T) *configCache {
+	t.Helper()
+	isolatePaths(t)
+	writeTestConfig(t, defaultConfig())
+	return &configCache{path: getConfigPath()}
+}
+
+// readResponse returns the status and trimmed body of a request's response
+func readResponse(t *testing.T, resp *http.Response, err error) (int, string) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, strings.TrimSpace(string(body))
+}
+
+func httpGet(t *testing.T, target string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(target)
+	return readResponse(t, resp, err)
+}
+
+func httpPostForm(t *testing.T, target string, values url.Values) (int, string) {
+	t.Helper()
+	resp, err := http.PostForm(target, values)
+	return readResponse(t, resp, err)
+}
+
+func TestSocketGetSet(t *testing.T) {
+	cache := serveTestConfig(t)
+	server := httptest.NewServer(socketHandler(cache, false))
+	defer server.Close()
+
+	if status, body := httpGet(t, server.URL+"/get?key=dailyLimit"); status != 200 || body != "0.5" {
+		t.Errorf("get = %d %q", status, body)
+	}
+	status, body := httpPostForm(t, server.URL+"/set", url.Values{"key": {"wallet.daily_limit"}, "value": {"0.75"}})
+	if status != 200 || body != "ok" {
+		t.Fatalf("set = %d %q", status, body)
+	}
+	if _, body := httpGet(t, server.URL+"/get?key=wallet.daily_limit"); body != "0.75" {
+		t.Errorf("get after set = %q", body)
+	}
+	if config, err := readConfig(cache.path); err != nil || config.Wallet.DailyLimit != 0.75 {
+		t.Errorf("file has daily_limit %v, %v", config.Wallet.DailyLimit, err)
+	}
+
+	if status, _ := httpPostForm(t, server.URL+"/set", url.Values{"key": {"wallet.daily_limit"}, "value": {"abc"}}); status != http.StatusBadRequest {
+		t.Errorf("invalid set = %d", status)
+	}
+	if status, _ := httpGet(t, server.URL+"/set"); status != http.StatusMethodNotAllowed {
+		t.Errorf("GET /set = %d", status)
+	}
+	if status, _ := httpGet(t, server.URL+"/get?key=no.such.key"); status != http.StatusNotFound {
+		t.Errorf("unknown key = %d", status)
+	}
+}
+
+func TestSocketReadonly(t *testing.T) {
+	cache := serveTestConfig(t)
+	server := httptest.NewServer(socketHandler(cache, true))
+	defer server.Close()
+
+	status, _ := httpPostForm(t, server.URL+"/set", url.Values{"key": {"agent.name"}, "value": {"X"}})
+	if status != http.StatusForbidden {
+		t.Errorf("set on a readonly server = %d", status)
+	}
+}
+
+func TestConfigCacheInvalidation(t *testing.T) {
+	cache := serveTestConfig(t)
+	if value, err := cache.get("agent.name"); err != nil || value != "Arithmos" {
+		t.Fatalf("get = %q, %v", value, err)
+	}
+
+	config := defaultConfig()
+	config.Agent.Name = "Reloaded agent"
+	writeTestConfig(t, config)
+	if value, _ := cache.get("agent.name"); value != "Reloaded agent" {
+		t.Errorf("after an edit get = %q, want the new value", value)
+	}
+
+	// a broken edit keeps the last good config
+	os.WriteFile(cache.path, []byte("{broken"), 0600)
+	if value, err := cache.get("agent.name"); err != nil || value != "Reloaded agent" {
+		t.Errorf("after a broken edit get = %q, %v", value, err)
+	}
+}
+
+func TestGetViaSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+	cache := serveTestConfig(t)
+	dir, err := os.MkdirTemp("", "acm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "acm.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: socketHandler(cache, false)}
+	go server.Serve(l)
+	defer server.Close()
+
+	t.Setenv("ACM_SOCKET", path)
+	if value, ok := getViaSocket("agent.name"); !ok || value != "Arithmos" {
+		t.Errorf("getViaSocket = %q, %v", value, ok)
+	}
+
+	t.Setenv("ACM_SOCKET", filepath.Join(dir, "missing.sock"))
+	if _, ok := getViaSocket("agent.name"); ok {
+		t.Error("getViaSocket reached a server that isn't running")
+	}
+}
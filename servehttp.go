@@ -0,0 +1,253 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// errReadonly is returned for writes to a server started without
+// --allow-writes
+var errReadonly = errors.New("this server is read-only (start it with --allow-writes)")
+
+// httpOptions configures the HTTP API of `acm serve --http`
+type httpOptions struct {
+	// AllowWrites enables PUT, authenticated with Token
+	AllowWrites bool
+	Token       string
+	// Loopback is set when the server listens on a loopback address. Such a
+	// server only answers requests addressed to a loopback Host, so a web
+	// page can't reach it through DNS rebinding.
+	Loopback bool
+}
+
+// newServeToken generates the bearer token writes must carry
+func newServeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// checkHTTPRequest rejects requests a browser could send on a web page's
+// behalf: anything with an Origin header, and on a loopback server anything
+// not addressed to a loopback Host
+func checkHTTPRequest(r *http.Request, opts httpOptions) (int, error) {
+	if r.Header.Get("Origin") != "" {
+		return http.StatusForbidden, errors.New("cross-origin requests are not allowed")
+	}
+	if opts.Loopback && !isLoopbackHost(r.Host) {
+		return http.StatusForbidden, fmt.Errorf("host %q is not a loopback address", r.Host)
+	}
+	return 0, nil
+}
+
+func isLoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkWriteRequest authorizes a PUT: writes must be enabled, carry the
+// bearer token and a JSON body, and not touch validation hooks, which
+// acm validate executes
+func checkWriteRequest(r *http.Request, key string, opts httpOptions) (int, error) {
+	if !opts.AllowWrites {
+		return http.StatusForbidden, errReadonly
+	}
+	want := "Bearer " + opts.Token
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(want)) != 1 {
+		return http.StatusUnauthorized, errors.New("missing or wrong bearer token")
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return http.StatusUnsupportedMediaType, errors.New("the body must be JSON (Content-Type: application/json)")
+	}
+	if key == "validation" || strings.HasPrefix(key, "validation.") {
+		return http.StatusForbidden, errors.New("validation hooks can't be changed over HTTP")
+	}
+	return 0, nil
+}
+
+// httpListenAddr completes addr for `acm serve --http`: a bare port or
+// ":port" binds to 127.0.0.1. It also reports whether the host is loopback.
+func httpListenAddr(addr string) (string, bool, error) {
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", false, err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	ip := net.ParseIP(host)
+	loopback := host == "localhost" || (ip != nil && ip.IsLoopback())
+	return net.JoinHostPort(host, port), loopback, nil
+}
+
+// effective returns the cached config with ${VAR} references expanded and
+// environment secrets applied, as the read commands see it
+func (c *configCache) effective() (AgentConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config, err := c.current()
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	if err := expandConfigEnvRefs(&config); err != nil {
+		return AgentConfig{}, err
+	}
+	resolveSecrets(&config)
+	return config, nil
+}
+
+// maskConfigSecrets masks every secret in config like `acm get` does
+func maskConfigSecrets(config *AgentConfig) {
+	for _, leaf := range configLeaves(config) {
+		if isSecretKey(leaf.Key) {
+			leaf.Value.SetString(maskSecret(leaf.Value.String()))
+		}
+	}
+}
+
+// httpHandler implements the HTTP API of `acm serve --http`. Every response
+// is JSON with secrets masked.
+//
+//	GET /config          the effective config
+//	GET /config/<key>    {"key": ..., "value": ...} for a dotted key or section
+//	PUT /config/<key>    set a value (the JSON request body) and save, with --allow-writes
+//	GET /validate        the report of `acm validate --json`
+func httpHandler(cache *configCache, opts httpOptions) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, errors.New("use GET"))
+			return
+		}
+		config, err := cache.effective()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		maskConfigSecrets(&config)
+		writeJSON(w, http.StatusOK, config)
+	})
+	mux.HandleFunc("/config/", func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/config/")
+		switch r.Method {
+		case http.MethodGet:
+			serveConfigKey(w, cache, key)
+		case http.MethodPut:
+			serveSetKey(w, r, cache, key, opts)
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, errors.New("use GET or PUT"))
+		}
+	})
+	mux.HandleFunc("/validate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, errors.New("use GET"))
+			return
+		}
+		config, err := cache.effective()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		findings := runValidation(config, validationContext{Dir: cache.dir()}, serveValidationRules())
+		writeJSON(w, http.StatusOK, validationReport{Valid: !hasErrors(findings), Findings: findings})
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status, err := checkHTTPRequest(r, opts); err != nil {
+			writeJSONError(w, status, err)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func serveConfigKey(w http.ResponseWriter, cache *configCache, key string) {
+	config, err := cache.effective()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	maskConfigSecrets(&config)
+
+	// Sections are served whole; leaves resolve loosely like `acm get`
+	v, err := lookupField(&config, key)
+	if err != nil {
+		if key, err = resolveKey(key); err == nil {
+			v, err = lookupField(&config, key)
+		}
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"key": key, "value": v.Interface()})
+}
+
+// serveSetKey sets key to the JSON request body: a string is stored as is,
+// any other JSON value is passed on as written, like `acm set` would get it
+func serveSetKey(w http.ResponseWriter, r *http.Request, cache *configCache, key string, opts httpOptions) {
+	key, err := resolveKey(key)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err)
+		return
+	}
+	if status, err := checkWriteRequest(r, key, opts); err != nil {
+		writeJSONError(w, status, err)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxConfigBytes()))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	var raw json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid JSON body: %v", err))
+		return
+	}
+	value := string(raw)
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		value = s
+	}
+	if err := cache.set(key, value); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"key": key, "status": "ok"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		status, data = http.StatusInternalServerError, []byte(fmt.Sprintf(`{"error": %q}`, err.Error()))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testEtherscanKey = "etherscan-test-key-1234567890"
+
+// httpTestServer serves the HTTP API for a default config with an API key set
+func httpTestServer(t *testing.T, opts httpOptions) (*httptest.Server, *configCache) {
+	t.Helper()
+	isolatePaths(t)
+	config := defaultConfig()
+	config.APIKeys.Etherscan = testEtherscanKey
+	writeTestConfig(t, config)
+	cache := &configCache{path: getConfigPath()}
+	server := httptest.NewServer(httpHandler(cache, opts))
+	t.Cleanup(server.Close)
+	return server, cache
+}
+
+// httpPut sends body as JSON to target with an optional bearer token
+func httpPut(t *testing.T, target, token, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPut, target, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	return readResponse(t, resp, err)
+}
+
+func TestHTTPConfigMasksSecrets(t *testing.T) {
+	server, _ := httpTestServer(t, httpOptions{Loopback: true})
+
+	status, body := httpGet(t, server.URL+"/config")
+	if status != http.StatusOK {
+		t.Fatalf("GET /config = %d %s", status, body)
+	}
+	assertNotContains(t, body, testEtherscanKey)
+	var config AgentConfig
+	if err := json.Unmarshal([]byte(body), &config); err != nil {
+		t.Fatalf("GET /config is not a config: %v\n%s", err, body)
+	}
+	if config.APIKeys.Etherscan != maskSecret(testEtherscanKey) || config.Agent.Name != "Arithmos" {
+		t.Errorf("etherscan = %q, agent.name = %q", config.APIKeys.Etherscan, config.Agent.Name)
+	}
+
+	_, body = httpGet(t, server.URL+"/config/api_keys")
+	assertNotContains(t, body, testEtherscanKey)
+	_, body = httpGet(t, server.URL+"/config/api_keys.etherscan")
+	assertNotContains(t, body, testEtherscanKey)
+}
+
+func TestHTTPConfigKey(t *testing.T) {
+	server, _ := httpTestServer(t, httpOptions{Loopback: true})
+
+	status, body := httpGet(t, server.URL+"/config/wallet.daily_limit")
+	var got struct {
+		Key   string      `json:"key"`
+		Value interface{} `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(body), &got); err != nil || status != http.StatusOK {
+		t.Fatalf("GET /config/wallet.daily_limit = %d %s", status, body)
+	}
+	if got.Key != "wallet.daily_limit" || got.Value != 0.5 {
+		t.Errorf("got %+v", got)
+	}
+	// leaves resolve loosely like acm get
+	if _, body := httpGet(t, server.URL+"/config/dailyLimit"); !strings.Contains(body, `"key": "wallet.daily_limit"`) {
+		t.Errorf("GET /config/dailyLimit = %s", body)
+	}
+	if _, body := httpGet(t, server.URL+"/config/monitoring"); !strings.Contains(body, `"dashboard_port": 8080`) {
+		t.Errorf("GET /config/monitoring = %s", body)
+	}
+	if status, _ := httpGet(t, server.URL+"/config/no.such.key"); status != http.StatusNotFound {
+		t.Errorf("unknown key = %d", status)
+	}
+}
+
+func TestHTTPValidate(t *testing.T) {
+	server, _ := httpTestServer(t, httpOptions{Loopback: true})
+
+	status, body := httpGet(t, server.URL+"/validate")
+	var report validationReport
+	if err := json.Unmarshal([]byte(body), &report); err != nil || status != http.StatusOK {
+		t.Fatalf("GET /validate = %d %s", status, body)
+	}
+	if !report.Valid {
+		t.Errorf("default config is invalid: %+v", report.Findings)
+	}
+	if len(report.Findings) == 0 || report.Findings[0].Rule != "api-keys" {
+		t.Errorf("findings = %+v, want the Basescan api-keys warning", report.Findings)
+	}
+}
+
+func TestHTTPReadonlyRefusesWrites(t *testing.T) {
+	server, cache := httpTestServer(t, httpOptions{Loopback: true})
+
+	status, body := httpPut(t, server.URL+"/config/agent.name", "", `"X"`)
+	if status != http.StatusForbidden || !strings.Contains(body, "read-only") {
+		t.Errorf("PUT on a readonly server = %d %s", status, body)
+	}
+	if status, _ := httpPostForm(t, server.URL+"/config", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("POST /config = %d", status)
+	}
+	if config, err := readConfig(cache.path); err != nil || config.Agent.Name != "Arithmos" {
+		t.Errorf("agent.name = %q, %v", config.Agent.Name, err)
+	}
+}
+
+func TestHTTPWrites(t *testing.T) {
+	server, cache := httpTestServer(t, httpOptions{AllowWrites: true, Token: "s3cret", Loopback: true})
+
+	if status, _ := httpPut(t, server.URL+"/config/wallet.daily_limit", "", "0.75"); status != http.StatusUnauthorized {
+		t.Errorf("PUT without a token = %d", status)
+	}
+	if status, _ := httpPut(t, server.URL+"/config/wallet.daily_limit", "wrong", "0.75"); status != http.StatusUnauthorized {
+		t.Errorf("PUT with a wrong token = %d", status)
+	}
+	if status, body := httpPut(t, server.URL+"/config/wallet.daily_limit", "s3cret", "0.75"); status != http.StatusOK {
+		t.Fatalf("PUT = %d %s", status, body)
+	}
+	if status, _ := httpPut(t, server.URL+"/config/validation.hooks", "s3cret", `["./hook.sh"]`); status != http.StatusForbidden {
+		t.Errorf("PUT validation.hooks = %d", status)
+	}
+	if status, _ := httpPut(t, server.URL+"/config/wallet.daily_limit", "s3cret", "abc"); status != http.StatusBadRequest {
+		t.Errorf("PUT invalid JSON = %d", status)
+	}
+	if config, err := readConfig(cache.path); err != nil || config.Wallet.DailyLimit != 0.75 {
+		t.Errorf("file has daily_limit %v, %v", config.Wallet.DailyLimit, err)
+	}
+}
+
+func TestHTTPRejectsBrowserRequests(t *testing.T) {
+	server, _ := httpTestServer(t, httpOptions{Loopback: true})
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/config", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	resp, err := http.DefaultClient.Do(req)
+	if status, _ := readResponse(t, resp, err); status != http.StatusForbidden {
+		t.Errorf("cross-origin GET = %d", status)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/config", nil)
+	req.Host = "rebind.example.com"
+	resp, err = http.DefaultClient.Do(req)
+	if status, _ := readResponse(t, resp, err); status != http.StatusForbidden {
+		t.Errorf("GET with a non-loopback Host = %d", status)
+	}
+}
+
+func TestHTTPListenAddr(t *testing.T) {
+	for _, tc := range []struct {
+		in, addr string
+		loopback bool
+	}{
+		{"8080", "127.0.0.1:8080", true},
+		{":8080", "127.0.0.1:8080", true},
+		{"localhost:9000", "localhost:9000", true},
+		{"[::1]:9000", "[::1]:9000", true},
+		{"0.0.0.0:8080", "0.0.0.0:8080", false},
+	} {
+		addr, loopback, err := httpListenAddr(tc.in)
+		if err != nil || addr != tc.addr || loopback != tc.loopback {
+			t.Errorf("httpListenAddr(%s) = %s, %t, %v; want %s, %t", tc.in, addr, loopback, err, tc.addr, tc.loopback)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// numericKeys lists every number-valued config key
+func numericKeys() []string {
+	config := defaultConfig()
+	var keys []string
+	for _, leaf := range configLeaves(&config) {
+		switch leaf.Value.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Float64:
+			keys = append(keys, leaf.Key)
+		}
+	}
+	return keys
+}
+
+func TestAssignNumericRejectsGarbage(t *testing.T) {
+	keys := numericKeys()
+	if len(keys) < 5 {
+		t.Fatalf("only found numeric keys %v", keys)
+	}
+	for _, key := range keys {
+		for _, value := range []string{"abc", "0.5xyz", "1 2", "", "NaN"} {
+			config := defaultConfig()
+			before, _ := lookupField(&config, key)
+			want := leafString(before)
+			if err := assignValue(&config, key, value, false); err == nil {
+				t.Errorf("set %s %q accepted", key, value)
+			}
+			if after, _ := lookupField(&config, key); leafString(after) != want {
+				t.Errorf("set %s %q changed the value to %s", key, value, leafString(after))
+			}
+		}
+	}
+}
+
+func TestAssignNumericValid(t *testing.T) {
+	for _, key := range numericKeys() {
+		config := defaultConfig()
+		if err := assignValue(&config, key, " 1 ", false); err != nil {
+			t.Errorf("set %s 1: %v", key, err)
+			continue
+		}
+		if value, _ := lookupField(&config, key); leafString(value) != "1" {
+			t.Errorf("set %s 1 stored %s", key, leafString(value))
+		}
+	}
+}
+
+func TestSetInvalidNumberKeepsFile(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	before := e.read(e.configPath())
+	out := e.mustFail("set", "wallet.daily_limit", "abc")
+	assertContains(t, out, `"abc" is not a number`)
+	if e.read(e.configPath()) != before {
+		t.Error("config file changed after a failed set")
+	}
+
+	assertContains(t, e.mustFail("set", "monitoring.dashboard_port", "80.5"), "invalid value for monitoring.dashboard_port")
+	e.mustRun("set", "wallet.daily_limit", "0.75")
+	if got := strings.TrimSpace(e.mustRun("get", "wallet.daily_limit")); got != "0.75" {
+		t.Errorf("daily_limit = %s, want 0.75", got)
+	}
+}
+
+func TestAssignFieldAutovivifies(t *testing.T) {
+	config := defaultConfig()
+	if err := assignField(&config, "networks.arbitrum.rpc_url", "https://arb1.example.com", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Networks["arbitrum"].RPCURL; got != "https://arb1.example.com" {
+		t.Errorf("networks.arbitrum.rpc_url = %q", got)
+	}
+	// an existing entry keeps its other fields
+	if err := assignField(&config, "networks.arbitrum.daily_limit", "0.2", false); err != nil {
+		t.Fatal(err)
+	}
+	if entry := config.Networks["arbitrum"]; entry.RPCURL != "https://arb1.example.com" || entry.DailyLimit != 0.2 {
+		t.Errorf("networks.arbitrum = %+v", entry)
+	}
+
+	// the leaf is still type-checked, and a failed set creates nothing
+	if err := assignField(&config, "networks.base.daily_limit", "lots", true); err == nil {
+		t.Error("a non-numeric daily_limit was accepted")
+	}
+	if err := assignField(&config, "networks.base.nonsense", "1", true); err != errUnknownKey {
+		t.Errorf("unknown leaf error = %v, want errUnknownKey", err)
+	}
+	if _, ok := config.Networks["base"]; ok {
+		t.Error("a failed set created networks.base")
+	}
+}
+
+func TestSetNoCreate(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	out := e.mustFail("set", "--no-create", "networks.arbitrum.rpc_url", "https://arb1.example.com")
+	assertContains(t, out, "networks.arbitrum does not exist (--no-create)")
+	assertNotContains(t, e.read(e.configPath()), "arbitrum")
+
+	e.mustRun("set", "networks.arbitrum.rpc_url", "https://arb1.example.com")
+	assertContains(t, e.mustRun("get", "networks.arbitrum.rpc_url"), "https://arb1.example.com")
+	e.mustRun("set", "--no-create", "networks.arbitrum.rpc_url", "https://arb2.example.com")
+	assertContains(t, e.mustRun("get", "networks.arbitrum.rpc_url"), "https://arb2.example.com")
+}
+
+func TestSetIfUnset(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "wallet.daily_limit", "0")
+
+	for key, value := range map[string]string{
+		"monitoring.webhook_url": "https://hooks.example.com/alert",
+		"wallet.daily_limit":     "2.5",
+	} {
+		e.mustRun("set", "--if-unset", key, value)
+		assertContains(t, e.mustRun("get", key), value)
+	}
+
+	out := e.mustRun("set", "--if-unset", "monitoring.webhook_url", "https://other.example.com")
+	assertContains(t, out, "monitoring.webhook_url is already set, left unchanged")
+	assertContains(t, e.mustRun("get", "monitoring.webhook_url"), "https://hooks.example.com/alert")
+	e.mustRun("set", "--if-unset", "wallet.daily_limit", "9")
+	assertContains(t, e.mustRun("get", "wallet.daily_limit"), "2.5")
+
+	// a key from the environment isn't stored in the config
+	e.setenv("ETHERSCAN_API_KEY", "env-etherscan-key-123456")
+	e.mustRun("set", "--if-unset", "api_keys.etherscan", "stored-etherscan-key-123456")
+	assertContains(t, e.read(e.configPath()), "stored-etherscan-key-123456")
+}
+
+func TestSetJSONValues(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+
+	// an object is decoded over the section, keeping the fields it leaves out
+	e.mustRun("set", "security", `{"firewall_enabled": false, "whitelisted_addresses": ["`+testAddrA+`"]}`)
+	assertContains(t, e.mustRun("get", "security.firewall_enabled"), "false")
+	assertContains(t, e.mustRun("get", "security.honeypot_enabled"), "true")
+	assertContains(t, e.mustRun("get", "security.whitelisted_addresses"), testAddrA)
+
+	// a list replaces the whole list
+	e.mustRun("set", "wallet.networks", `["base"]`)
+	assertContains(t, e.mustRun("get", "wallet.networks"), `["base"]`)
+	e.mustRun("set", "networks", `{"arbitrum": {"rpc_url": "https://arb1.example.com"}}`)
+	assertContains(t, e.mustRun("get", "networks.arbitrum.rpc_url"), "https://arb1.example.com")
+
+	before := e.read(e.configPath())
+	for value, want := range map[string]string{
+		`{"firewall_enabled": tru`:                "invalid value for security: expected a JSON object: ",
+		`{"firewall": true}`:                      `unknown field "firewall"`,
+		`{"whitelisted_addresses": ["0x12"]}`:     "invalid value for security.whitelisted_addresses",
+		`{"firewall_enabled": true} {"extra": 1}`: "trailing data after the JSON value",
+	} {
+		assertContains(t, e.mustFail("set", "security", value), want)
+	}
+	assertContains(t, e.mustFail("set", "monitoring", `{"dashboard_port": 99999}`), "invalid value for monitoring.dashboard_port")
+	if e.read(e.configPath()) != before {
+		t.Error("a rejected JSON value changed the config")
+	}
+
+	// scalar keys still take plain strings
+	e.mustRun("set", "agent.name", `{"not": "json"}`)
+	assertContains(t, e.mustRun("get", "agent.name"), `{"not": "json"}`)
+}
+
+func TestAssignJSONValueLeavesConfigOnError(t *testing.T) {
+	config := defaultConfig()
+	config.Security.WhitelistedAddresses = []string{testAddrA}
+	config.Networks = map[string]NetworkSettings{"base": {RPCURL: "https://base.example.com"}}
+
+	if err := assignValue(&config, "networks", `{"base": {"rpc_url": "https://other.example.com", "daily_limit": "lots"}}`, false); err == nil {
+		t.Fatal("a string daily_limit accepted")
+	}
+	if err := assignValue(&config, "security", `{"whitelisted_addresses": ["0x12"]}`, false); err == nil {
+		t.Fatal("invalid address accepted")
+	}
+	if config.Networks["base"].RPCURL != "https://base.example.com" || config.Security.WhitelistedAddresses[0] != testAddrA {
+		t.Errorf("networks = %v, whitelist = %v; want them unchanged", config.Networks, config.Security.WhitelistedAddresses)
+	}
+}
+
+func TestAssignEveryLeaf(t *testing.T) {
+	// every leaf can be set by its key, to the value it has in a full config
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-key-123456"
+	config.Security.WhitelistedAddresses = []string{testAddrA, testAddrB}
+	config.Monitoring.WebhookURL = "https://hooks.example.com/alert"
+	leafValue := func(v reflect.Value) string {
+		if list, ok := v.Interface().([]string); ok {
+			return strings.Join(list, ",")
+		}
+		return envString(v)
+	}
+	for _, leaf := range configLeaves(&config) {
+		if leaf.Value.Kind() == reflect.Map || leaf.Value.Kind() == reflect.Struct {
+			continue
+		}
+		value := leafValue(leaf.Value)
+		scratch := AgentConfig{}
+		if err := assignValue(&scratch, leaf.Key, value, false); err != nil {
+			t.Errorf("set %s %q: %v", leaf.Key, value, err)
+			continue
+		}
+		if got, _ := lookupField(&scratch, leaf.Key); leafValue(got) != value {
+			t.Errorf("set %s %q stored %q", leaf.Key, value, leafValue(got))
+		}
+	}
+}
+
+func TestSetByDottedKey(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	for key, want := range map[string]string{
+		"agent.website":                 "https://agent.example.com",
+		"security.prompt_guard_enabled": "false",
+		"wallet.address":                testAddrB,
+		"observability.log_level":       "debug",
+	} {
+		e.mustRun("set", key, want)
+		assertContains(t, e.mustRun("get", key), want)
+	}
+	// lists take comma-separated items
+	e.mustRun("set", "wallet.networks", "ethereum, optimism")
+	assertContains(t, e.mustRun("get", "wallet.networks"), `["ethereum","optimism"]`)
+	e.mustRun("set", "security.blacklisted_addresses", testAddrA+","+testAddrB)
+	assertContains(t, e.mustRun("get", "security.blacklisted_addresses"), testAddrA, testAddrB)
+
+	for _, args := range [][]string{
+		{"security.prompt_guard_enabled", "maybe"},
+		{"agent.erc8004_id", "1.5"},
+		{"monitoring.rate_limit.rps", "fast"},
+	} {
+		assertContains(t, e.mustFail("set", args[0], args[1]), args[0], `"`+args[1]+`"`)
+	}
+	assertContains(t, e.mustFail("set", "agent.nope", "x"), "unknown key: agent.nope")
+	assertContains(t, e.mustFail("set", "wallet.daily_limit.extra", "x"), "unknown key: wallet.daily_limit.extra")
+}
+
+func TestSetWalletAddress(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "wallet.address", testAddrA)
+	assertContains(t, e.mustRun("get", "wallet.address"), testAddrA)
+
+	before := e.read(e.configPath())
+	for _, tt := range []struct{ value, msg string }{
+		{testAddrB[:20], "is not a 0x-prefixed 40 hex digit address"},
+		{strings.TrimPrefix(testAddrB, "0x"), "is not a 0x-prefixed 40 hex digit address"},
+		{strings.ToLower(testAddrB), "fails the EIP-55 checksum (expected " + testAddrB + ")"},
+	} {
+		out, code := e.run("set", "wallet.address", tt.value)
+		if code == 0 {
+			t.Errorf("set wallet.address %s succeeded", tt.value)
+		}
+		assertContains(t, out, "invalid value for wallet.address: \""+tt.value+"\" "+tt.msg)
+	}
+	if e.read(e.configPath()) != before {
+		t.Error("a rejected wallet.address was written")
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// compositeField finds the section, list or map that key names, such as
+// security or wallet.networks. It only walks struct fields; ok is false for
+// scalar leaves and keys through a map, which take plain values.
+func compositeField(config *AgentConfig, key string) (reflect.Value, bool) {
+	v := reflect.ValueOf(config).Elem()
+	for _, part := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		field, found := fieldByTag(v, part)
+		if !found {
+			return reflect.Value{}, false
+		}
+		v = field
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		return v, true
+	}
+	return reflect.Value{}, false
+}
+
+// assignJSONValue sets a section, list or map field from JSON. An object is
+// decoded over the current value, so fields it leaves out keep their values;
+// a list replaces the whole list. Every leaf the key covers then goes
+// through the same validators as `acm set` on that leaf, and config is only
+// changed when all of them pass.
+func assignJSONValue(config *AgentConfig, key, value string) error {
+	scratch := *config
+	field, _ := compositeField(&scratch, key)
+	want := "a JSON object"
+	if field.Kind() == reflect.Slice {
+		want = "a JSON list"
+	}
+
+	// Decode over a deep copy: the scratch config still shares its lists and
+	// maps with config, which must stay untouched if the value fails
+	target := reflect.New(field.Type())
+	if field.Kind() != reflect.Slice {
+		current, err := json.Marshal(field.Interface())
+		if err == nil {
+			err = json.Unmarshal(current, target.Interface())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(target.Interface()); err != nil {
+		return fmt.Errorf("invalid value for %s: expected %s: %v", key, want, err)
+	}
+	if dec.More() {
+		return fmt.Errorf("invalid value for %s: expected %s: trailing data after the JSON value", key, want)
+	}
+	if field.Kind() == reflect.Slice && target.Elem().IsNil() {
+		// Keep lists as [] rather than null in the saved file
+		target.Elem().Set(reflect.MakeSlice(field.Type(), 0, 0))
+	}
+	field.Set(target.Elem())
+
+	for _, leaf := range configLeaves(&scratch) {
+		if leaf.Key != key && !strings.HasPrefix(leaf.Key, key+".") {
+			continue
+		}
+		if err := validateFieldValue(leaf.Key, envString(leaf.Value)); err != nil {
+			return err
+		}
+		if leaf.Key == "monitoring.webhook_template" {
+			if err := validateWebhookTemplate(leaf.Value.String()); err != nil {
+				return fmt.Errorf("invalid value for %s: %v", leaf.Key, err)
+			}
+		}
+	}
+	*config = scratch
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// The web3.js accounts.sign example: "Some data" signed with this key
+const (
+	web3TestKey       = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	web3TestAddress   = "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"
+	web3TestSignature = "0xb91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+)
+
+// personalSign signs message like a wallet's personal_sign, as r || s || v
+func personalSign(t *testing.T, keyHex, message string) string {
+	t.Helper()
+	data, err := hex.DecodeString(keyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact := ecdsa.SignCompact(secp256k1.PrivKeyFromBytes(data), personalMessageHash(message), false)
+	return "0x" + hex.EncodeToString(append(compact[1:], compact[0]))
+}
+
+func TestPersonalMessageHash(t *testing.T) {
+	want := "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655"
+	if got := hex.EncodeToString(personalMessageHash("Some data")); got != want {
+		t.Errorf("hash = %s, want %s", got, want)
+	}
+}
+
+func TestRecoverPersonalSigner(t *testing.T) {
+	if signer, err := recoverPersonalSigner("Some data", web3TestSignature); err != nil || signer != web3TestAddress {
+		t.Errorf("signer = %s, %v; want %s", signer, err, web3TestAddress)
+	}
+	// v may also be given as 0/1
+	zeroBased := web3TestSignature[:len(web3TestSignature)-2] + "01"
+	if signer, _ := recoverPersonalSigner("Some data", zeroBased); signer != web3TestAddress {
+		t.Errorf("signer with v=1 = %s", signer)
+	}
+	if signer, _ := recoverPersonalSigner("Other data", web3TestSignature); signer == web3TestAddress {
+		t.Error("a signature over another message recovered the same signer")
+	}
+
+	for _, sig := range []string{"", "0x1234", web3TestSignature + "00", web3TestSignature[:len(web3TestSignature)-2] + "05"} {
+		if _, err := recoverPersonalSigner("Some data", sig); err == nil {
+			t.Errorf("signature %q accepted", sig)
+		}
+	}
+}
+
+func TestWalletValidateSigner(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "wallet.address", web3TestAddress)
+
+	challenge := "acm signer check\nNonce: 42"
+	sig := personalSign(t, web3TestKey, challenge)
+	out := e.mustRun("wallet", "validate-signer", "--challenge", challenge, "--signature", sig)
+	assertContains(t, out, "Signature is from "+web3TestAddress)
+
+	// interactive: the challenge is printed and the signature read from stdin
+	out, code := e.runInput(sig+"\n", "wallet", "validate-signer", "--challenge", challenge)
+	if code != 0 {
+		t.Fatalf("exit %d:\n%s", code, out)
+	}
+	assertContains(t, out, "Sign this message with "+web3TestAddress, challenge, "Paste the signature")
+}
+
+func TestWalletValidateSignerMismatch(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+
+	// signed by the web3 test key, not the wallet in the default config
+	challenge := "acm signer check\nNonce: 42"
+	sig := personalSign(t, web3TestKey, challenge)
+	out := e.mustFail("wallet", "validate-signer", "--challenge", challenge, "--signature", sig)
+	assertContains(t, out, "Signed by "+web3TestAddress+", not wallet.address "+defaultConfig().Wallet.Address)
+
+	out, _ = e.runInput("not a signature\n", "wallet", "validate-signer")
+	assertContains(t, out, "Invalid signature: expected 65 bytes of hex")
+	if strings.Contains(out, "Signature is from") {
+		t.Errorf("garbage accepted:\n%s", out)
+	}
+}
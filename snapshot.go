@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshot is a named savepoint of the config file. Content is the file
+// exactly as it was on disk, so comments and extends survive a restore.
+type Snapshot struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	Message   string `json:"message,omitempty"`
+	Content   string `json:"content"`
+}
+
+// snapshotDir keeps snapshots with the config's other state, so each
+// profile has its own
+func snapshotDir() string {
+	return filepath.Join(stateDir(), "snapshots")
+}
+
+func snapshotPath(name string) string {
+	return filepath.Join(snapshotDir(), name+".json")
+}
+
+// backupDir holds the copies taken before a config is replaced wholesale
+func backupDir() string {
+	return filepath.Join(stateDir(), "backups")
+}
+
+// snapshotCommand saves the current config as a named snapshot
+func snapshotCommand(args []string) {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	message := fs.String("m", "", "describe the snapshot")
+	positional := parseFlags(fs, args)
+	if len(positional) != 1 {
+		fmt.Println("Usage: acm snapshot <name> [-m message]")
+		os.Exit(1)
+	}
+	name := positional[0]
+	validateSnapshotName(name)
+
+	configPath := getConfigPath()
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		fmt.Printf("❌ Config not found at %s\n", configPath)
+		os.Exit(1)
+	}
+
+	path := snapshotPath(name)
+	if _, err := os.Stat(path); err == nil {
+		confirmOrExit(fmt.Sprintf("Snapshot %s already exists. Overwrite it?", name))
+	}
+
+	snapshot := Snapshot{
+		Name:      name,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Message:   *message,
+		Content:   string(data),
+	}
+	out, _ := json.MarshalIndent(snapshot, "", "  ")
+	os.MkdirAll(snapshotDir(), 0700)
+	if err := writeFileAtomic(path, out, 0600); err != nil {
+		fmt.Printf("❌ Failed to write snapshot: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Saved snapshot %s\n", name)
+}
+
+// snapshotsCommand lists snapshots, oldest first
+func snapshotsCommand() {
+	entries, _ := os.ReadDir(snapshotDir())
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() {
+			continue
+		}
+		snapshot, err := readSnapshot(name)
+		if err != nil {
+			fmt.Printf("⚠️  Skipping %s: %v\n", entry.Name(), err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if len(snapshots) == 0 {
+		fmt.Println("No snapshots yet. Create one with 'acm snapshot <name>'")
+		return
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt < snapshots[j].CreatedAt
+	})
+	for _, s := range snapshots {
+		fmt.Printf("  %-20s %s  %s\n", s.Name, s.CreatedAt, s.Message)
+	}
+}
+
+// restoreSnapshotCommand replaces the config with a snapshot, backing up
+// the current file first
+func restoreSnapshotCommand(args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: acm restore-snapshot <name>")
+		os.Exit(1)
+	}
+	name := args[0]
+	validateSnapshotName(name)
+
+	snapshot, err := readSnapshot(name)
+	if err != nil {
+		fmt.Printf("❌ Snapshot %s: %v\n", name, err)
+		os.Exit(1)
+	}
+	configPath := getConfigPath()
+	if _, err := parseConfig(configPath, []byte(snapshot.Content)); err != nil {
+		fmt.Printf("❌ Snapshot %s holds an invalid config: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	if backup, err := backupConfig(); err != nil {
+		fmt.Printf("❌ Failed to back up the current config: %v\n", err)
+		os.Exit(1)
+	} else if backup != "" {
+		fmt.Printf("💾 Backed up current config to %s\n", backup)
+	}
+
+	if err := writeFileAtomic(configPath, []byte(snapshot.Content), 0600); err != nil {
+		fmt.Printf("❌ Failed to write config: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Restored snapshot %s (%s)\n", name, snapshot.CreatedAt)
+}
+
+func readSnapshot(name string) (Snapshot, error) {
+	data, err := os.ReadFile(snapshotPath(name))
+	if os.IsNotExist(err) {
+		return Snapshot{}, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// backupConfig copies the current config into the backup directory under a
+// timestamped name and returns its path, or "" when there is nothing to back
+// up. The copy is read back before older backups are rotated out.
+func backupConfig() (string, error) {
+	configPath := getConfigPath()
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(backupDir(), 0700); err != nil {
+		return "", err
+	}
+	// Keep the extension so a .jsonc backup still parses with its comments
+	ext := filepath.Ext(configPath)
+	if ext == "" {
+		ext = ".json"
+	}
+	path := filepath.Join(backupDir(), "config-"+time.Now().UTC().Format("20060102T150405.000Z")+ext)
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return "", err
+	}
+	if err := verifyBackup(path, data); err != nil {
+		return "", err
+	}
+	rotateBackups()
+	return path, nil
+}
+
+func validateSnapshotName(name string) {
+	if !profileNamePattern.MatchString(name) {
+		fmt.Printf("❌ Invalid snapshot name: %s\n", name)
+		fmt.Println("   Use lowercase letters, digits, '-' and '_'")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotCreateAndList(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustRun("snapshots"), "No snapshots yet")
+
+	e.mustRun("snapshot", "before-upgrade", "-m", "known good")
+	e.mustRun("snapshot", "second")
+
+	var snapshot Snapshot
+	data := e.read(filepath.Join(e.stateDir(), "snapshots", "before-upgrade.json"))
+	if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Name != "before-upgrade" || snapshot.Message != "known good" || snapshot.CreatedAt == "" {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+	if snapshot.Content != e.read(e.configPath()) {
+		t.Error("snapshot content differs from the config file")
+	}
+
+	out := e.mustRun("snapshots")
+	assertContains(t, out, "before-upgrade", "known good", snapshot.CreatedAt, "second")
+	if strings.Index(out, "before-upgrade") > strings.Index(out, "second") {
+		t.Errorf("snapshots not listed oldest first:\n%s", out)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("snapshot", "good")
+	original := e.read(e.configPath())
+	e.mustRun("set", "wallet.daily_limit", "9")
+
+	out := e.mustRun("restore-snapshot", "good")
+	assertContains(t, out, "Backed up current config", "Restored snapshot good")
+	if e.read(e.configPath()) != original {
+		t.Error("config not restored byte for byte")
+	}
+
+	backups, _ := os.ReadDir(filepath.Join(e.stateDir(), "backups"))
+	if len(backups) != 1 {
+		t.Fatalf("want one backup, got %d", len(backups))
+	}
+	assertContains(t, e.read(filepath.Join(e.stateDir(), "backups", backups[0].Name())), `"daily_limit": 9`)
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustFail("restore-snapshot", "missing"), "Snapshot missing: not found")
+	assertContains(t, e.mustFail("snapshot", "../escape"), "Invalid snapshot name")
+
+	e.mustRun("snapshot", "s1")
+	assertContains(t, e.mustFail("snapshot", "s1"), "--yes")
+	e.mustRun("--yes", "snapshot", "s1", "-m", "replaced")
+	assertContains(t, e.mustRun("snapshots"), "replaced")
+
+	path := filepath.Join(e.stateDir(), "snapshots", "broken.json")
+	e.write(path, `{"name": "broken", "content": "not a config"}`)
+	assertContains(t, e.mustFail("restore-snapshot", "broken"), "holds an invalid config")
+}
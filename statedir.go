@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// configDirOverride is the global --config-dir flag
+var configDirOverride string
+
+// explicitStateDir is the directory named by --config-dir or ACM_CONFIG_DIR
+func explicitStateDir() string {
+	if configDirOverride != "" {
+		return expandPath(configDirOverride)
+	}
+	return expandPath(os.Getenv("ACM_CONFIG_DIR"))
+}
+
+// stateDir holds the current config's backups, snapshots and serve socket.
+// By default that's $XDG_STATE_HOME/acm (~/.local/state/acm); --config-dir
+// moves it, so the config can sit on a read-only mount with its state
+// somewhere writable. Profiles other than the default get their own
+// directory under it, and so does each file named with --config.
+func stateDir() string {
+	if path := explicitConfigPath(); path != "" {
+		if dir := explicitStateDir(); dir != "" {
+			return dir
+		}
+		return filepath.Join(stateBaseDir(), "configs", configStateName(path))
+	}
+	return profileStateDir(activeProfile())
+}
+
+// profileStateDir is the state directory of the named profile
+func profileStateDir(name string) string {
+	if name == defaultProfile {
+		return stateBaseDir()
+	}
+	return filepath.Join(stateBaseDir(), "profiles", name)
+}
+
+func stateBaseDir() string {
+	if dir := explicitStateDir(); dir != "" {
+		return dir
+	}
+	return filepath.Join(xdgDir("XDG_STATE_HOME", ".local/state"), "acm")
+}
+
+// configStateName names the state directory of a config given by path, such
+// as config-3f2a9c0d1b4e5f60: the file name keeps it readable and a hash of
+// the absolute path keeps two config.json files apart
+func configStateName(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha256.Sum256([]byte(path))
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return name + "-" + hex.EncodeToString(sum[:8])
+}
+
+// exportBaseDir is the directory profile exports are written under
+func exportBaseDir() string {
+	if dir := explicitStateDir(); dir != "" {
+		return dir
+	}
+	return getBaseDir()
+}
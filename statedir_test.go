@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultPathsUnderHome(t *testing.T) {
+	home := isolatePaths(t)
+	if got, want := getConfigPath(), filepath.Join(home, ".config", "agent", "config.json"); got != want {
+		t.Errorf("config path = %s, want %s", got, want)
+	}
+	if got, want := backupDir(), filepath.Join(home, ".local", "state", "acm", "backups"); got != want {
+		t.Errorf("backup dir = %s, want %s", got, want)
+	}
+}
+
+func TestXDGDirsHonored(t *testing.T) {
+	isolatePaths(t)
+	config, state := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("XDG_STATE_HOME", state)
+
+	if got, want := getConfigPath(), filepath.Join(config, "agent", "config.json"); got != want {
+		t.Errorf("config path = %s, want %s", got, want)
+	}
+	if got, want := backupDir(), filepath.Join(state, "acm", "backups"); got != want {
+		t.Errorf("backup dir = %s, want %s", got, want)
+	}
+	if got, want := snapshotDir(), filepath.Join(state, "acm", "snapshots"); got != want {
+		t.Errorf("snapshot dir = %s, want %s", got, want)
+	}
+	if got, want := socketPath(), filepath.Join(state, "acm", "acm.sock"); got != want {
+		t.Errorf("socket = %s, want %s", got, want)
+	}
+}
+
+func TestRelativeXDGDirIgnored(t *testing.T) {
+	home := isolatePaths(t)
+	t.Setenv("XDG_CONFIG_HOME", "relative/config")
+	t.Setenv("XDG_STATE_HOME", "relative/state")
+	if got, want := getConfigPath(), filepath.Join(home, ".config", "agent", "config.json"); got != want {
+		t.Errorf("config path = %s, want %s", got, want)
+	}
+	if got, want := stateDir(), filepath.Join(home, ".local", "state", "acm"); got != want {
+		t.Errorf("state dir = %s, want %s", got, want)
+	}
+}
+
+func TestConfigOverrideWinsOverXDG(t *testing.T) {
+	isolatePaths(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	path := filepath.Join(t.TempDir(), "agent.json")
+	configOverride = path
+
+	if got := getConfigPath(); got != path {
+		t.Errorf("config path = %s, want %s", got, path)
+	}
+	// Each explicit config gets its own state directory
+	dir := stateDir()
+	if filepath.Dir(dir) != filepath.Join(state, "acm", "configs") || !strings.HasPrefix(filepath.Base(dir), "agent-") {
+		t.Errorf("state dir = %s, want configs/agent-<hash> under %s", dir, state)
+	}
+	configOverride = filepath.Join(t.TempDir(), "agent.json")
+	if stateDir() == dir {
+		t.Errorf("two configs named agent.json share the state dir %s", dir)
+	}
+}
+
+func TestProfileStateDir(t *testing.T) {
+	isolatePaths(t)
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	t.Setenv("ACM_PROFILE", "testnet")
+	if got, want := stateDir(), filepath.Join(state, "acm", "profiles", "testnet"); got != want {
+		t.Errorf("state dir = %s, want %s", got, want)
+	}
+}
+
+func TestProfileStateFollowsRenameAndDelete(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("profile", "create", "testnet")
+	e.mustRun("--profile", "testnet", "snapshot", "before")
+
+	e.mustRun("profile", "rename", "testnet", "sepolia")
+	assertContains(t, e.mustRun("--profile", "sepolia", "snapshots"), "before")
+
+	e.mustRun("--yes", "profile", "delete", "sepolia")
+	e.mustRun("profile", "create", "sepolia")
+	assertNotContains(t, e.mustRun("--profile", "sepolia", "snapshots"), "before")
+}
+
+func TestConfigDirHoldsStateAndExports(t *testing.T) {
+	e := newTestEnv(t)
+	configFile := filepath.Join(e.home, "etc", "agent", "config.json")
+	state := filepath.Join(e.home, "var", "acm")
+	run := func(args ...string) string {
+		return e.mustRun(append([]string{"--config", configFile, "--config-dir", state}, args...)...)
+	}
+	run("init")
+	run("set", "agent.name", "split-agent")
+	run("export")
+	run("snapshot", "s1")
+	run("restore-snapshot", "s1")
+
+	backups, _ := filepath.Glob(filepath.Join(state, "backups", "*.json"))
+	if len(backups) == 0 {
+		t.Error("no backup in the --config-dir")
+	}
+	for _, path := range []string{
+		filepath.Join(state, "exports", "wallet-monitor.json"),
+		filepath.Join(state, "snapshots", "s1.json"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Error(err)
+		}
+	}
+	// nothing but the config lands next to it, nor in the default state dir
+	entries, _ := os.ReadDir(filepath.Dir(configFile))
+	if len(entries) != 1 {
+		t.Errorf("config directory holds %v, want only config.json", entries)
+	}
+	if _, err := os.Stat(e.stateDir()); !os.IsNotExist(err) {
+		t.Errorf("the default state directory was used: %v", err)
+	}
+
+	// ACM_CONFIG_DIR does the same
+	e.setenv("ACM_CONFIG_DIR", state)
+	e.mustRun("--config", configFile, "restore-snapshot", "s1")
+	after, _ := filepath.Glob(filepath.Join(state, "backups", "*.json"))
+	if len(after) != len(backups)+1 {
+		t.Errorf("ACM_CONFIG_DIR backups: %d, want %d", len(after), len(backups)+1)
+	}
+}
+
+func TestConfigDirFlagOverridesEnv(t *testing.T) {
+	isolatePaths(t)
+	flagDir, envDir := t.TempDir(), t.TempDir()
+	t.Setenv("ACM_CONFIG_DIR", envDir)
+	if got := backupDir(); got != filepath.Join(envDir, "backups") {
+		t.Errorf("backup dir = %s, want it under ACM_CONFIG_DIR", got)
+	}
+	configDirOverride = flagDir
+	if got := backupDir(); got != filepath.Join(flagDir, "backups") {
+		t.Errorf("backup dir = %s, want it under --config-dir", got)
+	}
+	if got := exportBaseDir(); got != flagDir {
+		t.Errorf("export base = %s, want --config-dir", got)
+	}
+}
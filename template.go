@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are the helpers available to `acm template render`
+var templateFuncs = template.FuncMap{
+	"mask":  maskSecret,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+	"chainID": func(network string) (int64, error) {
+		info, ok := knownNetworks[network]
+		if !ok {
+			return 0, fmt.Errorf("unknown network %q", network)
+		}
+		return info.ChainID, nil
+	},
+	"explorer": func(network string) (string, error) {
+		info, ok := knownNetworks[network]
+		if !ok {
+			return "", fmt.Errorf("unknown network %q", network)
+		}
+		return info.Explorer, nil
+	},
+}
+
+// templateCommand handles `acm template <subcommand>`
+func templateCommand(args []string) {
+	if len(args) < 1 || args[0] != "render" {
+		fmt.Println("Usage: acm template render <template-file> [--out <file>]")
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("template render", flag.ExitOnError)
+	out := fs.String("out", "", "write the result to this file instead of stdout")
+	positional := parseFlags(fs, args[1:])
+	if len(positional) != 1 {
+		fmt.Println("Usage: acm template render <template-file> [--out <file>]")
+		os.Exit(1)
+	}
+
+	path := expandPath(positional[0])
+	data, err := renderTemplate(path, loadEffectiveConfig())
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	// The template may have printed secrets, so keep the result private
+	*out = expandPath(*out)
+	if err := writeFileAtomic(*out, data, 0600); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", *out, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Rendered %s to %s\n", path, *out)
+}
+
+// renderTemplate executes the Go text/template at path with config as its
+// data. Secrets are only in the output if the template prints them.
+func renderTemplate(path string, config AgentConfig) ([]byte, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(path).Funcs(templateFuncs).Option("missingkey=error").Parse(string(src))
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, config); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
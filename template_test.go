@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTemplate saves src to a file and returns its path
+func writeTemplate(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tool.tmpl")
+	if err := os.WriteFile(path, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRenderTemplateHelpers(t *testing.T) {
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-secret-value-123"
+	path := writeTemplate(t, `name={{upper .Agent.Name}}
+networks={{join .Wallet.Networks ","}}
+{{range .Wallet.Networks}}{{.}}={{chainID .}}
+{{end}}key={{mask .APIKeys.Etherscan}}
+`)
+
+	out, err := renderTemplate(path, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name=ARITHMOS\nnetworks=ethereum,base\nethereum=1\nbase=8453\nkey=" + maskSecret("etherscan-secret-value-123") + "\n"
+	if string(out) != want {
+		t.Errorf("rendered:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestRenderTemplateErrors(t *testing.T) {
+	config := defaultConfig()
+	for name, src := range map[string]string{
+		"unknown network": `{{chainID "atlantis"}}`,
+		"unknown field":   `{{.Agent.Nickname}}`,
+		"parse error":     `{{if}}`,
+	} {
+		if _, err := renderTemplate(writeTemplate(t, src), config); err == nil {
+			t.Errorf("%s: rendered without an error", name)
+		}
+	}
+}
+
+func TestTemplateRenderCommand(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-value-123")
+	path := writeTemplate(t, "port={{.Monitoring.DashboardPort}}\n")
+
+	out := e.mustRun("template", "render", path)
+	if out != "port=8080\n" {
+		t.Errorf("stdout = %q", out)
+	}
+	assertNotContains(t, out, "etherscan-secret-value-123")
+
+	dest := filepath.Join(e.home, "out", "tool.conf")
+	os.MkdirAll(filepath.Dir(dest), 0700)
+	e.mustRun("template", "render", path, "--out", dest)
+	if e.read(dest) != "port=8080\n" {
+		t.Errorf("--out wrote %q", e.read(dest))
+	}
+	if info, err := os.Stat(dest); err != nil {
+		t.Error(err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("--out file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// unifiedContext is the number of unchanged lines shown around each change
+const unifiedContext = 3
+
+// maskSecretPair renders two values of the same secret for display: never
+// the values themselves, but enough to see that one was set, unset or changed
+func maskSecretPair(oldValue, newValue string) (string, string) {
+	changed := oldValue != newValue
+	oldValue, newValue = secretDiffState(oldValue), secretDiffState(newValue)
+	if changed && oldValue == newValue {
+		newValue = "(changed)"
+	}
+	return oldValue, newValue
+}
+
+// maskedConfigJSON returns the canonical JSON of a and b with every secret
+// masked by maskSecretPair, as lines ready for unifiedDiff
+func maskedConfigJSON(a, b AgentConfig) ([]string, []string) {
+	right := map[string]configLeaf{}
+	for _, leaf := range configLeaves(&b) {
+		right[leaf.Key] = leaf
+	}
+	for _, leaf := range configLeaves(&a) {
+		if !isSecretKey(leaf.Key) {
+			continue
+		}
+		other := right[leaf.Key].Value
+		oldValue, newValue := maskSecretPair(leaf.Value.String(), other.String())
+		leaf.Value.SetString(oldValue)
+		other.SetString(newValue)
+	}
+
+	left, _ := json.MarshalIndent(a, "", "  ")
+	other, _ := json.MarshalIndent(b, "", "  ")
+	return strings.Split(string(left), "\n"), strings.Split(string(other), "\n")
+}
+
+// unifiedDiff renders the differences between two line slices in unified
+// diff format, or "" when they are equal
+func unifiedDiff(fromName, toName string, a, b []string) string {
+	ops := diffLines(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change and the extent of its hunk
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end > 2*unifiedContext {
+				break
+			}
+		}
+		from := max(start-unifiedContext, 0)
+		to := min(end+unifiedContext, len(ops))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		hunk := ops[from:to]
+		oldStart, newStart := ops[from].oldLine, ops[from].newLine
+		oldCount, newCount := 0, 0
+		for _, op := range hunk {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, op := range hunk {
+			fmt.Fprintf(&out, "%c%s\n", op.kind, op.text)
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// hunkRange formats a hunk header range; an empty range names the line
+// before it, as diff(1) does
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// lineOp is one line of a diff: ' ' kept, '-' removed or '+' added, with
+// its 1-based line number in each input
+type lineOp struct {
+	kind    byte
+	text    string
+	oldLine int
+	newLine int
+}
+
+// diffLines computes a minimal line diff through the longest common
+// subsequence. Configs are a few hundred lines at most, so the quadratic
+// table is fine.
+func diffLines(a, b []string) []lineOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []lineOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, lineOp{' ', a[i], i + 1, j + 1})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, lineOp{'+', b[j], i + 1, j + 1})
+			j++
+		default:
+			ops = append(ops, lineOp{'-', a[i], i + 1, j + 1})
+			i++
+		}
+	}
+	return ops
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weiPerETH is 10^18
+var weiPerETH = new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+
+// ethToWei converts an ETH amount to a decimal wei string, rounding down to
+// a whole wei. It goes through the float's shortest decimal form, so 0.1
+// becomes exactly 100000000000000000 rather than carrying binary float error.
+func ethToWei(eth float64) string {
+	amount, ok := new(big.Rat).SetString(strconv.FormatFloat(eth, 'g', -1, 64))
+	if !ok {
+		return "0"
+	}
+	amount.Mul(amount, weiPerETH)
+	return new(big.Int).Quo(amount.Num(), amount.Denom()).String()
+}
+
+// formatETHAmount renders an ETH amount for display with up to 6 decimals,
+// trailing zeros trimmed. An amount too small to show that way is printed
+// in full instead of as 0.
+func formatETHAmount(eth float64) string {
+	s := strings.TrimRight(strconv.FormatFloat(eth, 'f', 6, 64), "0")
+	s = strings.TrimSuffix(s, ".")
+	if eth != 0 && (s == "0" || s == "-0") {
+		return strconv.FormatFloat(eth, 'f', -1, 64)
+	}
+	return s
+}
+
+// isETHAmountKey reports whether key holds an ETH amount: the wallet and
+// per-network limits and thresholds
+func isETHAmountKey(key string) bool {
+	return strings.HasSuffix(key, ".daily_limit") || strings.HasSuffix(key, ".alert_threshold")
+}
+
+// parseETHInto parses an ETH amount like parseFloatInto, warning when the
+// value can't be stored or exported exactly
+func parseETHInto(dst *float64, key, value string) error {
+	if err := parseFloatInto(dst, key, value); err != nil {
+		return err
+	}
+	warnETHPrecision(key, strings.TrimSpace(value), *dst)
+	return nil
+}
+
+func warnETHPrecision(key, value string, eth float64) {
+	want, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return
+	}
+	stored := strconv.FormatFloat(eth, 'g', -1, 64)
+	if got, _ := new(big.Rat).SetString(stored); want.Cmp(got) != 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  %s can't hold %s exactly; it is stored as %s\n", key, value, stored)
+		return
+	}
+	if !new(big.Rat).Mul(want, weiPerETH).IsInt() {
+		fmt.Fprintf(os.Stderr, "⚠️  %s %s is finer than 1 wei; exports round it down to %s wei\n", key, value, ethToWei(eth))
+	}
+}
+
+// weiToETH formats a decimal wei amount as ETH without losing precision,
+// trimming trailing zeros
+func weiToETH(wei string) (string, error) {
+	amount, ok := new(big.Rat).SetString(wei)
+	if !ok || !amount.IsInt() {
+		return "", fmt.Errorf("invalid wei amount %q", wei)
+	}
+	eth := new(big.Rat).Quo(amount, weiPerETH).FloatString(18)
+	eth = strings.TrimRight(eth, "0")
+	return strings.TrimSuffix(eth, "."), nil
+}
+
+// derivedExportKeys are values exports carry with explicit units, computed
+// from the human-facing config (minutes, ETH) so downstream tools never have
+// to guess the unit
+var derivedExportKeys = []struct {
+	Key   string
+	Value func(AgentConfig) string
+}{
+	{"monitoring.check_interval_seconds", func(c AgentConfig) string {
+		return strconv.Itoa(c.Monitoring.CheckInterval * 60)
+	}},
+	{"wallet.daily_limit_wei", func(c AgentConfig) string { return ethToWei(c.Wallet.DailyLimit) }},
+	{"wallet.alert_threshold_wei", func(c AgentConfig) string { return ethToWei(c.Wallet.AlertThreshold) }},
+}
+
+// exportValue renders key, a config key or one of derivedExportKeys, as an
+// environment value
+func exportValue(config AgentConfig, key string) (string, error) {
+	for _, derived := range derivedExportKeys {
+		if derived.Key == key {
+			return derived.Value(config), nil
+		}
+	}
+	v, err := lookupField(&config, key)
+	if err != nil {
+		return "", err
+	}
+	return envString(v), nil
+}
+
+// parseDuration reads a duration such as 30s, 5m, 1h30m or 2d. A unit is
+// required, since a bare number could mean seconds or minutes, and the
+// duration must be positive.
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimSpace(value)
+	var days time.Duration
+	if i := strings.Index(s, "d"); i > 0 {
+		n, err := strconv.Atoi(s[:i])
+		if err == nil {
+			days, s = time.Duration(n)*24*time.Hour, s[i+1:]
+		}
+	}
+	d := days
+	if s != "" {
+		rest, err := time.ParseDuration(s)
+		if err != nil || rest < 0 {
+			return 0, fmt.Errorf("%q is not a duration (e.g. 30s, 5m, 1h)", value)
+		}
+		d += rest
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q is not a positive duration", value)
+	}
+	return d, nil
+}
+
+// sizeUnits are the suffixes parseSize accepts. K, M and G are binary
+// multiples with or without a B or iB, matching how the limits are documented.
+var sizeUnits = []struct {
+	Suffixes   []string
+	Multiplier int64
+}{
+	{[]string{"GiB", "GB", "G"}, 1 << 30},
+	{[]string{"MiB", "MB", "M"}, 1 << 20},
+	{[]string{"KiB", "KB", "K"}, 1 << 10},
+	{[]string{"B", ""}, 1},
+}
+
+// parseSize reads a byte size such as 512, 64KB, 1MB or 1.5MiB; a bare
+// number is bytes. The size must be positive.
+func parseSize(value string) (int64, error) {
+	s := strings.TrimSpace(value)
+	for _, unit := range sizeUnits {
+		for _, suffix := range unit.Suffixes {
+			if len(s) <= len(suffix) || !strings.EqualFold(s[len(s)-len(suffix):], suffix) {
+				continue
+			}
+			n, err := strconv.ParseFloat(strings.TrimSpace(s[:len(s)-len(suffix)]), 64)
+			if err != nil || n <= 0 || n*float64(unit.Multiplier) >= math.MaxInt64 {
+				return 0, fmt.Errorf("%q is not a size (e.g. 512KB, 1MB)", value)
+			}
+			if size := int64(n * float64(unit.Multiplier)); size > 0 {
+				return size, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%q is not a size (e.g. 512KB, 1MB)", value)
+}
+
+// parseMinutes reads a whole number of minutes, given bare (15) or as a
+// duration (15m, 2h)
+func parseMinutes(value string) (int, error) {
+	if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		if n < 1 {
+			return 0, fmt.Errorf("%q is not a whole number of minutes of at least 1", value)
+		}
+		return n, nil
+	}
+	d, err := parseDuration(value)
+	if err != nil || d%time.Minute != 0 {
+		return 0, fmt.Errorf("%q is not a whole number of minutes of at least 1", value)
+	}
+	return int(d / time.Minute), nil
+}
+
+// durationFlag is a flag.Value that parses with parseDuration
+type durationFlag struct{ d *time.Duration }
+
+func (f durationFlag) String() string {
+	if f.d == nil {
+		return ""
+	}
+	return f.d.String()
+}
+
+func (f durationFlag) Set(value string) error {
+	d, err := parseDuration(value)
+	if err != nil {
+		return err
+	}
+	*f.d = d
+	return nil
+}
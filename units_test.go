@@ -0,0 +1,173 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEthToWei(t *testing.T) {
+	for eth, want := range map[float64]string{
+		0.5:       "500000000000000000",
+		0.1:       "100000000000000000",
+		1:         "1000000000000000000",
+		0.000001:  "1000000000000",
+		1e-19:     "0",
+		123.45678: "123456780000000000000",
+	} {
+		if got := ethToWei(eth); got != want {
+			t.Errorf("ethToWei(%v) = %s, want %s", eth, got, want)
+		}
+	}
+}
+
+func TestWeiToETH(t *testing.T) {
+	for wei, want := range map[string]string{
+		"500000000000000000":  "0.5",
+		"1000000000000000000": "1",
+		"1":                   "0.000000000000000001",
+	} {
+		got, err := weiToETH(wei)
+		if err != nil || got != want {
+			t.Errorf("weiToETH(%s) = %s, %v, want %s", wei, got, err, want)
+		}
+	}
+	if _, err := weiToETH("1.5"); err == nil {
+		t.Error("weiToETH accepted a fractional wei amount")
+	}
+}
+
+func TestExportUnitExplicit(t *testing.T) {
+	config := defaultConfig()
+	config.Monitoring.CheckInterval = 5
+	config.Wallet.DailyLimit = 0.5
+
+	monitor := toolConfigs(t, config)["wallet-monitor.json"]
+	if monitor["check_interval_seconds"] != 300.0 {
+		t.Errorf("check_interval_seconds = %v, want 300", monitor["check_interval_seconds"])
+	}
+	if monitor["check_interval"] != 5.0 {
+		t.Errorf("check_interval = %v, want the minutes kept", monitor["check_interval"])
+	}
+	if monitor["daily_limit_wei"] != "500000000000000000" {
+		t.Errorf("daily_limit_wei = %v", monitor["daily_limit_wei"])
+	}
+
+	files, err := exportEnv(config, exportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := string(files[0].Data)
+	assertContains(t, env, "MONITORING_CHECK_INTERVAL_SECONDS=300\n", "WALLET_DAILY_LIMIT_WEI=500000000000000000\n")
+
+	// the human-facing config stays in minutes and ETH
+	for key, want := range map[string]string{"monitoring.check_interval_minutes": "5", "wallet.daily_limit": "0.5"} {
+		if got, err := exportValue(config, key); err != nil || got != want {
+			t.Errorf("%s = %s, %v, want %s", key, got, err, want)
+		}
+	}
+}
+
+func TestFormatETHAmount(t *testing.T) {
+	for eth, want := range map[float64]string{
+		0.5:         "0.5",
+		0.001:       "0.001",
+		1:           "1",
+		0.123456789: "0.123457",
+		0.0000001:   "0.0000001",
+		0:           "0",
+	} {
+		if got := formatETHAmount(eth); got != want {
+			t.Errorf("formatETHAmount(%v) = %s, want %s", eth, got, want)
+		}
+	}
+}
+
+func TestSmallLimitRoundTrip(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	out := e.mustRun("set", "wallet.daily_limit", "0.001")
+	assertNotContains(t, out, "⚠️")
+	assertContains(t, e.read(e.configPath()), `"daily_limit": 0.001,`)
+	assertContains(t, e.mustRun("get", "wallet.daily_limit"), "0.001")
+	assertContains(t, e.mustRun("show"), "Daily Limit:     0.001 ETH")
+	monitor := filepath.Join(e.exportDir(), "wallet-monitor.json")
+	e.mustRun("export")
+	assertContains(t, e.read(monitor), `"daily_limit_wei": "1000000000000000"`)
+}
+
+func TestSetWarnsOnLossyAmount(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	out := e.mustRun("set", "wallet.daily_limit", "0.1000000000000000000001")
+	assertContains(t, out, "wallet.daily_limit can't hold 0.1000000000000000000001 exactly; it is stored as 0.1")
+
+	out = e.mustRun("set", "wallet.alert_threshold", "0.0000000000000000001")
+	assertContains(t, out, "wallet.alert_threshold 0.0000000000000000001 is finer than 1 wei; exports round it down to 0 wei")
+}
+
+func TestParseDuration(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"30s":    30 * time.Second,
+		"5m":     5 * time.Minute,
+		"1h30m":  90 * time.Minute,
+		"2d":     48 * time.Hour,
+		"1d12h":  36 * time.Hour,
+		" 250ms": 250 * time.Millisecond,
+	} {
+		if got, err := parseDuration(value); err != nil || got != want {
+			t.Errorf("parseDuration(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "30", "0s", "-5m", "5 minutes", "d", "1x"} {
+		if got, err := parseDuration(value); err == nil {
+			t.Errorf("parseDuration(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for value, want := range map[string]int64{
+		"512":    512,
+		"512B":   512,
+		"64KB":   64 << 10,
+		"64k":    64 << 10,
+		"1MB":    1 << 20,
+		"1.5MiB": 3 << 19,
+		"2G":     2 << 30,
+	} {
+		if got, err := parseSize(value); err != nil || got != want {
+			t.Errorf("parseSize(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "0", "-1MB", "MB", "1TB", "lots", "1e30GB"} {
+		if got, err := parseSize(value); err == nil {
+			t.Errorf("parseSize(%q) = %d, want an error", value, got)
+		}
+	}
+}
+
+func TestDurationFieldsAndFlags(t *testing.T) {
+	for value, want := range map[string]int{"15": 15, "10m": 10, "2h": 120} {
+		if got, err := parseMinutes(value); err != nil || got != want {
+			t.Errorf("parseMinutes(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"0", "90s", "-5", "soon"} {
+		if _, err := parseMinutes(value); err == nil {
+			t.Errorf("parseMinutes(%q) succeeded", value)
+		}
+	}
+
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.check_interval", "2h")
+	assertContains(t, e.mustRun("get", "monitoring.check_interval_minutes"), "120")
+	assertContains(t, e.mustFail("set", "monitoring.check_interval", "90s"), "not a whole number of minutes")
+	assertContains(t, e.mustFail("verify-keys", "--timeout", "5"), `"5" is not a duration`)
+
+	e.setenv("ACM_MAX_CONFIG_BYTES", "1KB")
+	e.write(e.configPath(), `{"padding": "`+strings.Repeat("x", 2000)+`"}`)
+	assertContains(t, e.mustFail("show"), "over the 1024 byte limit")
+}
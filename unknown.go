@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// dropUnknown is the global --drop-unknown flag: unknown top-level sections
+// are removed on the next save instead of being preserved
+var dropUnknown bool
+
+// plainAgentConfig has AgentConfig's fields without its JSON methods
+type plainAgentConfig AgentConfig
+
+// knownSections are the top-level keys AgentConfig defines
+var knownSections = func() map[string]bool {
+	known := map[string]bool{}
+	t := reflect.TypeOf(AgentConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			known[name] = true
+		}
+	}
+	return known
+}()
+
+// UnmarshalJSON decodes the known fields and keeps every other top-level
+// section in Unknown, so a config written by a newer acm loses nothing when
+// an older one saves it
+func (c *AgentConfig) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*plainAgentConfig)(c)); err != nil {
+		return err
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+	c.Unknown = nil
+	for name, value := range sections {
+		if !knownSections[name] {
+			if c.Unknown == nil {
+				c.Unknown = map[string]json.RawMessage{}
+			}
+			c.Unknown[name] = value
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the known fields followed by the Unknown sections,
+// sorted
+func (c AgentConfig) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(plainAgentConfig(c))
+	if err != nil || len(c.Unknown) == 0 {
+		return data, err
+	}
+	var b bytes.Buffer
+	b.Write(data[:len(data)-1])
+	for _, name := range sortedKeys(c.Unknown) {
+		key, _ := json.Marshal(name)
+		b.WriteByte(',')
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(c.Unknown[name])
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+var warnedUnknown = map[string]bool{}
+
+// handleUnknownSections warns once per config about the unknown sections
+// it carries, or with --drop-unknown removes them from config and from the
+// file's layers so the next save leaves them out
+func handleUnknownSections(path string, config *AgentConfig) {
+	if len(config.Unknown) == 0 {
+		return
+	}
+	names := strings.Join(sortedKeys(config.Unknown), ", ")
+	if dropUnknown {
+		for name := range config.Unknown {
+			for _, layers := range []map[string]*inheritedLayer{inheritedLayers, localLayers} {
+				if layer, ok := layers[path]; ok {
+					delete(layer.raw, name)
+					layer.resolved.Unknown = nil
+				}
+			}
+		}
+		config.Unknown = nil
+		if !warnedUnknown[path] {
+			fmt.Fprintf(os.Stderr, "🗑️  Dropping unknown section(s) %s; they're left out when the config is next saved\n", names)
+		}
+	} else if !warnedUnknown[path] {
+		fmt.Fprintf(os.Stderr, "⚠️  Preserving unknown section(s) %s as is; acm doesn't check them (written by a newer version?)\n", names)
+	}
+	warnedUnknown[path] = true
+}
+
+// unknownSectionNames removes the top-level keys AgentConfig doesn't define
+// from raw and returns them, sorted
+func unknownSectionNames(raw map[string]interface{}) []string {
+	var names []string
+	for _, name := range sortedKeys(raw) {
+		if !knownSections[name] {
+			names = append(names, name)
+			delete(raw, name)
+		}
+	}
+	return names
+}
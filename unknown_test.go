@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const notificationsSection = `{"channels": ["email", "sms"], "quiet_hours": {"from": 22, "to": 7}}`
+
+func TestUnknownSectionSurvivesSave(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.editConfig(func(config map[string]interface{}) {
+		var notifications interface{}
+		json.Unmarshal([]byte(notificationsSection), &notifications)
+		config["notifications"] = notifications
+	})
+
+	out := e.mustRun("set", "agent.name", "newer-agent")
+	assertContains(t, out, "Preserving unknown section(s) notifications as is")
+
+	saved := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(e.read(e.configPath())), &saved); err != nil {
+		t.Fatal(err)
+	}
+	var got, want interface{}
+	json.Unmarshal(saved["notifications"], &got)
+	json.Unmarshal([]byte(notificationsSection), &want)
+	if string(mustJSON(t, got)) != string(mustJSON(t, want)) {
+		t.Errorf("notifications = %s, want %s", saved["notifications"], notificationsSection)
+	}
+	assertContains(t, e.mustRun("get", "agent.name"), "newer-agent")
+
+	out = e.mustRun("--drop-unknown", "set", "agent.name", "older-agent")
+	assertContains(t, out, "Dropping unknown section(s) notifications")
+	assertNotContains(t, e.read(e.configPath()), "notifications")
+	assertNotContains(t, e.mustRun("show"), "unknown section")
+}
+
+func TestAgentConfigUnknownRoundTrip(t *testing.T) {
+	data := []byte(`{"agent": {"name": "A"}, "zeta": [1, 2], "notifications": ` + notificationsSection + `}`)
+	var config AgentConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Agent.Name != "A" || len(config.Unknown) != 2 {
+		t.Fatalf("agent.name = %q, unknown = %v", config.Agent.Name, config.Unknown)
+	}
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again AgentConfig
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("re-decoding %s: %v", out, err)
+	}
+	if string(again.Unknown["zeta"]) != "[1,2]" || string(again.Unknown["notifications"]) != `{"channels":["email","sms"],"quiet_hours":{"from":22,"to":7}}` {
+		t.Errorf("unknown after a round trip = %s", out)
+	}
+
+	// a config with nothing unknown encodes as before
+	plain, _ := json.Marshal(plainAgentConfig(defaultConfig()))
+	if out, _ := json.Marshal(defaultConfig()); string(out) != string(plain) {
+		t.Errorf("encoding changed:\n%s\nwant\n%s", out, plain)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// finding is one validation result. Rule is the stable ID of the rule that
+// produced it, usable with --only/--skip and reported by --json. Field is
+// the config key it is about, when there is one.
+type finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // "error" or "warning"
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (f finding) String() string {
+	if f.Severity == "error" {
+		return "❌ " + f.Message
+	}
+	return "⚠️  " + f.Message
+}
+
+func errorf(rule, format string, args ...interface{}) finding {
+	return finding{Rule: rule, Severity: "error", Message: fmt.Sprintf(format, args...)}
+}
+
+func warnf(rule, format string, args ...interface{}) finding {
+	return finding{Rule: rule, Severity: "warning", Message: fmt.Sprintf(format, args...)}
+}
+
+// on records the config key f is about
+func (f finding) on(field string) finding {
+	f.Field = field
+	return f
+}
+
+// validationReport is the output of `acm validate --json`
+type validationReport struct {
+	Valid    bool      `json:"valid"`
+	Findings []finding `json:"findings"`
+	// Suppressed counts the findings hidden by --baseline
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// validationContext is what rules may need besides the config itself
+type validationContext struct {
+	// Dir is the directory of the config file; hooks run there
+	Dir string
+}
+
+// validationRule is one check run by `acm validate`
+type validationRule struct {
+	ID          string
+	Description string
+	Check       func(config AgentConfig, ctx validationContext) []finding
+}
+
+// validationRules is the registry of checks, in the order they run. IDs are
+// part of the CLI contract: don't rename them.
+var validationRules = []validationRule{
+	{"wallet-address", "wallet.address is set and well-formed", checkWalletAddress},
+	{"wallet-networks", "wallet.networks lists known networks", checkWalletNetworks},
+	{"daily-limit", "wallet.daily_limit is positive", checkDailyLimit},
+	{"api-keys", "explorer API keys needed for monitoring are set", checkAPIKeys},
+	{"key-expiry", "no API key in api_keys_meta has expired or expires soon", checkKeyExpiry},
+	{"rate-limit", "monitoring.rate_limit is usable", checkRateLimit},
+	{"observability", "observability settings are valid", checkObservability},
+	{"monitoring", "monitoring.webhook_url, check_interval_minutes and webhook_template are valid, and alerts are signed", checkMonitoring},
+	{"dashboard-port", "the dashboard port can be bound, when the dashboard is enabled", checkDashboardPort},
+	{"agent-links", "agent.website and agent.github are valid links", checkAgentLinks},
+	{"address-lists", "whitelisted and blacklisted addresses are well-formed, checksummed and on one list only", checkAddressLists},
+	{"security-features", "at least one of the firewall and honeypot is enabled", checkSecurityFeatures},
+	{"hooks", "external validation.hooks pass", checkHooks},
+}
+
+// selectRules returns the rules to run given comma-separated --only and
+// --skip lists, rejecting unknown IDs
+func selectRules(only, skip string) ([]validationRule, error) {
+	known := map[string]bool{}
+	for _, rule := range validationRules {
+		known[rule.ID] = true
+	}
+	parse := func(list string) (map[string]bool, error) {
+		ids := map[string]bool{}
+		for _, id := range strings.Split(list, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			if !known[id] {
+				return nil, fmt.Errorf("unknown rule %q (see 'acm validate --list-rules')", id)
+			}
+			ids[id] = true
+		}
+		return ids, nil
+	}
+
+	onlyIDs, err := parse(only)
+	if err != nil {
+		return nil, err
+	}
+	skipIDs, err := parse(skip)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []validationRule
+	for _, rule := range validationRules {
+		if (len(onlyIDs) > 0 && !onlyIDs[rule.ID]) || skipIDs[rule.ID] {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// runValidation runs rules against config and collects their findings
+func runValidation(config AgentConfig, ctx validationContext, rules []validationRule) []finding {
+	findings := []finding{}
+	for _, rule := range rules {
+		findings = append(findings, rule.Check(config, ctx)...)
+	}
+	return findings
+}
+
+// runValidationToFirstError runs rules in order and stops at the first one
+// that reports an error, returning just that error. Without errors it
+// returns every finding, like runValidation.
+func runValidationToFirstError(config AgentConfig, ctx validationContext, rules []validationRule) []finding {
+	findings := []finding{}
+	for _, rule := range rules {
+		for _, f := range rule.Check(config, ctx) {
+			if f.Severity == "error" {
+				return []finding{f}
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+func hasErrors(findings []finding) bool {
+	for _, f := range findings {
+		if f.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// severities lists each finding severity in display order with its color
+var severities = []struct {
+	Name  string
+	Color string
+}{
+	{"error", ansiRed},
+	{"warning", ansiYellow},
+}
+
+// colored renders f for the terminal in its severity's color
+func (f finding) colored() string {
+	for _, s := range severities {
+		if s.Name == f.Severity {
+			return colorize(s.Color, f.String())
+		}
+	}
+	return f.String()
+}
+
+// sortFindings orders findings by severity, errors first, keeping rule
+// order within each severity
+func sortFindings(findings []finding) []finding {
+	rank := func(f finding) int {
+		for i, s := range severities {
+			if s.Name == f.Severity {
+				return i
+			}
+		}
+		return len(severities)
+	}
+	sorted := append([]finding(nil), findings...)
+	sort.SliceStable(sorted, func(i, j int) bool { return rank(sorted[i]) < rank(sorted[j]) })
+	return sorted
+}
+
+// printFindings prints errors before warnings, then a count per severity
+func printFindings(findings []finding) {
+	if len(findings) == 0 {
+		fmt.Println("✅ Configuration is valid!")
+		return
+	}
+	for _, f := range sortFindings(findings) {
+		fmt.Println(f.colored())
+	}
+	var counts []string
+	for _, s := range severities {
+		n := 0
+		for _, f := range findings {
+			if f.Severity == s.Name {
+				n++
+			}
+		}
+		counts = append(counts, fmt.Sprintf("%d %s(s)", n, s.Name))
+	}
+	fmt.Println()
+	fmt.Printf("Found %d issue(s): %s\n", len(findings), strings.Join(counts, ", "))
+}
+
+func checkWalletAddress(config AgentConfig, _ validationContext) []finding {
+	if config.Wallet.Address == "" {
+		return []finding{errorf("wallet-address", "Wallet address not set").on("wallet.address")}
+	}
+	return checkFields(config, "wallet-address", "wallet.address")
+}
+
+func checkWalletNetworks(config AgentConfig, _ validationContext) []finding {
+	return checkFields(config, "wallet-networks", "wallet.networks")
+}
+
+func checkAgentLinks(config AgentConfig, _ validationContext) []finding {
+	return checkFields(config, "agent-links", "agent.website", "agent.github")
+}
+
+func checkAddressLists(config AgentConfig, _ validationContext) []finding {
+	findings := checkFields(config, "address-lists", "security.whitelisted_addresses", "security.blacklisted_addresses")
+	for _, addr := range config.Security.WhitelistedAddresses {
+		if indexOfAddress(config.Security.BlacklistedAddresses, addr) >= 0 {
+			findings = append(findings, errorf("address-lists", "%s is both whitelisted and blacklisted", addr).on("security.whitelisted_addresses"))
+		}
+	}
+	return findings
+}
+
+func checkDailyLimit(config AgentConfig, _ validationContext) []finding {
+	if config.Wallet.DailyLimit <= 0 {
+		return []finding{warnf("daily-limit", "Daily limit should be positive").on("wallet.daily_limit")}
+	}
+	return nil
+}
+
+func checkAPIKeys(config AgentConfig, _ validationContext) []finding {
+	var findings []finding
+	if config.APIKeys.Etherscan == "" {
+		findings = append(findings, warnf("api-keys", "Etherscan API key not set (needed for monitoring)").on("api_keys.etherscan"))
+	}
+	if config.APIKeys.Basescan == "" {
+		findings = append(findings, warnf("api-keys", "Basescan API key not set (needed for monitoring)").on("api_keys.basescan"))
+	}
+	return findings
+}
+
+func checkRateLimit(config AgentConfig, _ validationContext) []finding {
+	r := config.Monitoring.RateLimit
+	var findings []finding
+	if r.RPS <= 0 {
+		findings = append(findings, errorf("rate-limit", "Rate limit rps must be greater than 0").on("monitoring.rate_limit.rps"))
+	}
+	if r.Burst < 1 {
+		findings = append(findings, errorf("rate-limit", "Rate limit burst must be at least 1").on("monitoring.rate_limit.burst"))
+	}
+	return append(findings, checkFields(config, "rate-limit", "monitoring.rate_limit.backoff")...)
+}
+
+// checkMonitoring checks the webhook and check interval, warning when a
+// webhook that can verify signatures gets unsigned alerts. Discord webhooks
+// don't check signatures.
+func checkMonitoring(config AgentConfig, _ validationContext) []finding {
+	findings := checkFields(config, "monitoring", "monitoring.webhook_url", "monitoring.check_interval_minutes")
+	m := config.Monitoring
+	if m.WebhookURL != "" && m.WebhookSecret == "" && detectWebhookFormat(m.WebhookURL) == "generic" {
+		findings = append(findings, warnf("monitoring", "monitoring.webhook_secret is not set, so alerts to the webhook are unsigned").on("monitoring.webhook_secret"))
+	}
+	if m.WebhookTemplate != "" {
+		if err := validateWebhookTemplate(m.WebhookTemplate); err != nil {
+			findings = append(findings, errorf("monitoring", "monitoring.webhook_template %v", err).on("monitoring.webhook_template"))
+		}
+	}
+	return findings
+}
+
+// checkObservability checks the OTLP endpoint, sample rate and log level
+func checkObservability(config AgentConfig, _ validationContext) []finding {
+	return checkFields(config, "observability", "observability.otlp_endpoint", "observability.sample_rate", "observability.log_level")
+}
+
+// checkDashboardPort checks the dashboard port, but only when the dashboard
+// is enabled: tools never bind the port otherwise, so its value doesn't matter
+func checkDashboardPort(config AgentConfig, _ validationContext) []finding {
+	m := config.Monitoring
+	if !m.DashboardEnabled {
+		return nil
+	}
+
+	port := m.DashboardPort
+	switch {
+	case port == 0:
+		return []finding{warnf("dashboard-port", "Dashboard is enabled but dashboard_port is not set").on("monitoring.dashboard_port")}
+	case port < 0 || port > 65535:
+		return checkFields(config, "dashboard-port", "monitoring.dashboard_port")
+	}
+
+	var findings []finding
+	if port < 1024 {
+		findings = append(findings, warnf("dashboard-port", "Dashboard port %d is privileged and needs root to bind", port).on("monitoring.dashboard_port"))
+	}
+	if portInUse(port) {
+		findings = append(findings, warnf("dashboard-port", "Dashboard port %d is already in use", port).on("monitoring.dashboard_port"))
+	}
+	return findings
+}
+
+// portInUse reports whether something is already listening on port
+func portInUse(port int) bool {
+	l, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		// Not being allowed to bind a privileged port doesn't mean it's taken
+		return port >= 1024
+	}
+	l.Close()
+	return false
+}
+
+func checkSecurityFeatures(config AgentConfig, _ validationContext) []finding {
+	if !config.Security.FirewallEnabled && !config.Security.HoneypotEnabled {
+		return []finding{warnf("security-features", "All security features disabled").on("security")}
+	}
+	return nil
+}
+
+func checkHooks(config AgentConfig, ctx validationContext) []finding {
+	return runValidationHooks(config, ctx.Dir)
+}
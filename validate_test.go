@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+)
+
+// findingFields returns the fields of findings of rule, in order
+func findingFields(findings []finding, rule string) []string {
+	var fields []string
+	for _, f := range findings {
+		if f.Rule == rule {
+			fields = append(fields, f.Field)
+		}
+	}
+	return fields
+}
+
+func TestCheckRateLimit(t *testing.T) {
+	config := defaultConfig()
+	if findings := checkRateLimit(config, validationContext{}); len(findings) != 0 {
+		t.Errorf("default rate limit has findings: %+v", findings)
+	}
+
+	config.Monitoring.RateLimit = RateLimitConfig{RPS: 0, Burst: 0, Backoff: "random"}
+	got := strings.Join(findingFields(checkRateLimit(config, validationContext{}), "rate-limit"), ",")
+	if want := "monitoring.rate_limit.rps,monitoring.rate_limit.burst,monitoring.rate_limit.backoff"; got != want {
+		t.Errorf("rate-limit findings on %s, want %s", got, want)
+	}
+}
+
+func TestMissingRateLimitGetsDefaults(t *testing.T) {
+	config, err := parseConfig("config.json", []byte(`{"version": "0.1.0", "monitoring": {"check_interval_minutes": 5}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Monitoring.RateLimit != defaultRateLimit() {
+		t.Errorf("rate_limit = %+v, want the defaults", config.Monitoring.RateLimit)
+	}
+}
+
+func TestSetRateLimitBackoff(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.rate_limit.backoff", "linear")
+	assertContains(t, e.mustFail("set", "monitoring.rate_limit.backoff", "random"), "monitoring.rate_limit.backoff")
+}
+
+func TestCheckDashboardPort(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	busy := l.Addr().(*net.TCPAddr).Port
+
+	config := defaultConfig()
+	for _, port := range []int{0, 80, busy, 70000} {
+		config.Monitoring.DashboardEnabled = false
+		config.Monitoring.DashboardPort = port
+		if findings := checkDashboardPort(config, validationContext{}); len(findings) != 0 {
+			t.Errorf("disabled dashboard on port %d has findings: %+v", port, findings)
+		}
+	}
+
+	config.Monitoring.DashboardEnabled = true
+	for port, want := range map[int]string{
+		0:     "dashboard_port is not set",
+		80:    "is privileged",
+		busy:  "is already in use",
+		70000: "monitoring.dashboard_port",
+	} {
+		config.Monitoring.DashboardPort = port
+		findings := checkDashboardPort(config, validationContext{})
+		if len(findings) == 0 {
+			t.Errorf("enabled dashboard on port %d has no findings", port)
+			continue
+		}
+		if f := findings[0]; !strings.Contains(f.Message, want) && f.Field != want {
+			t.Errorf("port %d: finding %+v, want %q", port, f, want)
+		}
+	}
+}
+
+func TestCheckObservability(t *testing.T) {
+	config := defaultConfig()
+	config.Observability = ObservabilityConfig{OTLPEndpoint: "https://otel.example.com:4318", SampleRate: 0.25, LogLevel: "debug"}
+	if findings := checkObservability(config, validationContext{}); len(findings) != 0 {
+		t.Errorf("valid observability has findings: %+v", findings)
+	}
+
+	config.Observability = ObservabilityConfig{OTLPEndpoint: "otel:4318", SampleRate: 1.5, LogLevel: "verbose"}
+	got := strings.Join(findingFields(checkObservability(config, validationContext{}), "observability"), ",")
+	if want := "observability.otlp_endpoint,observability.sample_rate,observability.log_level"; got != want {
+		t.Errorf("observability findings on %s, want %s", got, want)
+	}
+}
+
+func TestSetObservability(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "observability.otlp_endpoint", "http://localhost:4318")
+	e.mustRun("set", "observability.sample_rate", "0")
+	assertContains(t, e.mustRun("get", "observability.otlp_endpoint"), "http://localhost:4318")
+	assertContains(t, e.mustFail("set", "observability.sample_rate", "-0.1"), "observability.sample_rate")
+	assertContains(t, e.mustFail("set", "observability.log_level", "trace"), "observability.log_level")
+	assertContains(t, e.mustFail("set", "observability.otlp_endpoint", "ftp://otel"), "observability.otlp_endpoint")
+}
+
+func ruleIDs(rules []validationRule) string {
+	ids := make([]string, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSelectRules(t *testing.T) {
+	rules, err := selectRules("daily-limit, wallet-address", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// registry order, not the order asked for
+	if got := ruleIDs(rules); got != "wallet-address,daily-limit" {
+		t.Errorf("--only selected %s", got)
+	}
+
+	rules, _ = selectRules("", "api-keys,hooks")
+	if got := ruleIDs(rules); strings.Contains(got, "api-keys") || strings.Contains(got, "hooks") || len(rules) != len(validationRules)-2 {
+		t.Errorf("--skip left %s", got)
+	}
+
+	rules, _ = selectRules("api-keys,daily-limit", "api-keys")
+	if got := ruleIDs(rules); got != "daily-limit" {
+		t.Errorf("--only with --skip selected %s", got)
+	}
+
+	if _, err := selectRules("no-such-rule", ""); err == nil || !strings.Contains(err.Error(), `unknown rule "no-such-rule"`) {
+		t.Errorf("unknown --only rule: %v", err)
+	}
+	if _, err := selectRules("", "typo"); err == nil {
+		t.Error("unknown --skip rule accepted")
+	}
+}
+
+func TestValidateOnlySkip(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "wallet.daily_limit", "0")
+
+	out := e.mustRun("validate", "--skip", "api-keys")
+	assertContains(t, out, "Daily limit should be positive")
+	assertNotContains(t, out, "Etherscan API key")
+
+	out = e.mustRun("validate", "--only", "api-keys", "--json")
+	var report validationReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, out)
+	}
+	for _, f := range report.Findings {
+		if f.Rule != "api-keys" {
+			t.Errorf("--only api-keys reported %s", f.Rule)
+		}
+	}
+	if len(report.Findings) == 0 {
+		t.Error("--only api-keys found nothing")
+	}
+
+	assertContains(t, e.mustFail("validate", "--only", "port-range"), `unknown rule "port-range"`)
+	assertContains(t, e.mustRun("validate", "--list-rules"), "wallet-address", "dashboard-port")
+}
+
+func TestValidateProfileMatrix(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "etherscan-secret-key-abcd")
+	e.mustRun("set", "api_keys.basescan", "basescan-secret-key-abcd")
+	e.mustRun("profile", "create", "testnet")
+	e.mustRun("--profile", "testnet", "set", "security.firewall_enabled", "false")
+	e.mustRun("--profile", "testnet", "set", "security.honeypot_enabled", "false")
+	e.write(e.profilePath("testnet"), strings.Replace(e.read(e.profilePath("testnet")), defaultConfig().Wallet.Address, "0x123", 1))
+	e.mustRun("profile", "create", "broken")
+	e.write(e.profilePath("broken"), "{not json")
+
+	out := e.mustRun("validate", "--profile-matrix", "--only", "wallet-address,api-keys,security-features", "--json")
+	var report matrixReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if strings.Join(report.Rules, ",") != "wallet-address,api-keys,security-features" {
+		t.Errorf("rules = %v", report.Rules)
+	}
+	rows := map[string]matrixRow{}
+	for _, row := range report.Rows {
+		rows[row.Profile] = row
+	}
+	for profile, want := range map[string]map[string]string{
+		"default": {"wallet-address": "pass", "api-keys": "pass", "security-features": "pass"},
+		"testnet": {"wallet-address": "fail", "api-keys": "warn", "security-features": "warn"},
+	} {
+		for rule, cell := range want {
+			if got := rows[profile].Cells[rule]; got != cell {
+				t.Errorf("%s %s = %q, want %q", profile, rule, got, cell)
+			}
+		}
+	}
+	if rows["broken"].Error == "" || rows["broken"].Cells != nil {
+		t.Errorf("broken row = %+v, want a load error", rows["broken"])
+	}
+
+	out = e.mustRun("validate", "--profile-matrix", "--only", "wallet-address,security-features")
+	assertContains(t, out,
+		"PROFILE  wallet-address  security-features\n",
+		"default  pass            pass\n",
+		"testnet  fail            warn\n",
+		"broken   -               -\n",
+		"❌ broken failed to load:",
+	)
+}
+
+func TestRunValidationToFirstError(t *testing.T) {
+	var ran []string
+	rule := func(id string, findings ...finding) validationRule {
+		return validationRule{ID: id, Check: func(AgentConfig, validationContext) []finding {
+			ran = append(ran, id)
+			return findings
+		}}
+	}
+	rules := []validationRule{
+		rule("a", warnf("a", "just a warning")),
+		rule("b", errorf("b", "first error"), errorf("b", "second error")),
+		rule("c", errorf("c", "never checked")),
+	}
+
+	findings := runValidationToFirstError(defaultConfig(), validationContext{}, rules)
+	if len(findings) != 1 || findings[0].Message != "first error" {
+		t.Errorf("findings = %+v, want only the first error", findings)
+	}
+	if strings.Join(ran, ",") != "a,b" {
+		t.Errorf("ran rules %v, want it to stop at b", ran)
+	}
+
+	ran = nil
+	if findings := runValidation(defaultConfig(), validationContext{}, rules); len(findings) != 4 || len(ran) != 3 {
+		t.Errorf("collect-all found %d and ran %v", len(findings), ran)
+	}
+}
+
+func TestValidateExitOnFirst(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.editConfig(func(config map[string]interface{}) {
+		section(config, "wallet")["address"] = "0x123"
+		section(config, "wallet")["daily_limit"] = -1
+	})
+
+	out, code := e.run("validate")
+	if code != 0 {
+		t.Errorf("collect-all validate exited %d", code)
+	}
+	assertContains(t, out, "0x123", "Daily limit should be positive")
+
+	out, code = e.run("validate", "--exit-on", "first")
+	if code != 1 {
+		t.Errorf("--exit-on first exited %d, want 1", code)
+	}
+	assertContains(t, out, "0x123")
+	assertNotContains(t, out, "Daily limit should be positive")
+
+	assertContains(t, e.mustFail("validate", "--exit-on", "last"), "Unknown --exit-on value: last")
+	assertContains(t, e.mustFail("validate", "--exit-on", "first", "--all-profiles"), "--exit-on first works on a single config")
+}
+
+func TestSortFindingsBySeverity(t *testing.T) {
+	findings := []finding{
+		warnf("a", "warning 1"),
+		errorf("b", "error 1"),
+		warnf("c", "warning 2"),
+		errorf("d", "error 2"),
+	}
+	var got []string
+	for _, f := range sortFindings(findings) {
+		got = append(got, f.Message)
+	}
+	if strings.Join(got, ", ") != "error 1, error 2, warning 1, warning 2" {
+		t.Errorf("sorted = %v, want errors first, rule order kept", got)
+	}
+	if findings[0].Message != "warning 1" {
+		t.Error("sortFindings reordered its argument")
+	}
+}
+
+func TestValidateOutputOrderAndSummary(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	// api-keys warns before address-lists fails in rule order
+	e.editConfig(func(config map[string]interface{}) {
+		security := section(config, "security")
+		security["whitelisted_addresses"] = []string{testAddrA}
+		security["blacklisted_addresses"] = []string{testAddrA}
+	})
+
+	out, _ := e.run("validate")
+	errorAt := strings.Index(out, testAddrA+" is both whitelisted and blacklisted")
+	warningAt := strings.Index(out, "Etherscan API key not set")
+	if errorAt < 0 || warningAt < 0 || errorAt > warningAt {
+		t.Errorf("errors aren't listed before warnings:\n%s", out)
+	}
+	assertContains(t, out, "Found 3 issue(s): 1 error(s), 2 warning(s)")
+	assertNotContains(t, out, "\x1b[")
+
+	out, _ = e.run("--color", "always", "validate")
+	assertContains(t, out, ansiRed, ansiYellow)
+	out, _ = e.run("--color", "never", "validate")
+	assertNotContains(t, out, "\x1b[")
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// fieldValidator checks a value in the text form `acm set` takes. Its error
+// finishes the sentence `"<value>" ...`, so every field reports bad values
+// the same way.
+type fieldValidator func(value string) error
+
+// validateFieldValue runs the validator declared for key in configFields,
+// if any. Validators of optional fields accept "", which unsets them. A
+// lazy ${VAR} reference is checked as it expands now.
+func validateFieldValue(key, value string) error {
+	for _, alias := range fieldAliases {
+		if key == alias.Old {
+			key = alias.New
+		}
+	}
+	for _, field := range configFields {
+		if field.Key != key || field.Validate == nil {
+			continue
+		}
+		check := value
+		if hasEnvRefs(value) {
+			expanded, err := expandEnvRefs(value)
+			if err != nil {
+				return err
+			}
+			check = expanded
+		}
+		if err := field.Validate(check); err != nil {
+			return fmt.Errorf("invalid value for %s: %q %v", key, value, err)
+		}
+	}
+	return nil
+}
+
+// checkFields runs the declared validators over the current values of keys,
+// reporting failures under rule
+func checkFields(config AgentConfig, rule string, keys ...string) []finding {
+	var findings []finding
+	for _, key := range keys {
+		v, err := lookupField(&config, key)
+		if err != nil {
+			continue
+		}
+		if err := validateFieldValue(key, envString(v)); err != nil {
+			msg := err.Error()
+			findings = append(findings, errorf(rule, "%s", strings.ToUpper(msg[:1])+msg[1:]).on(key))
+		}
+	}
+	return findings
+}
+
+// validateAddress accepts "", leaving wallet-address to report it missing
+func validateAddress(value string) error {
+	switch {
+	case value == "" || isValidEthereumAddress(value):
+		return nil
+	case !hexAddressPattern.MatchString(value):
+		return errors.New("is not a 0x-prefixed 40 hex digit address")
+	}
+	return fmt.Errorf("fails the EIP-55 checksum (expected %s)", checksumAddress(value))
+}
+
+// validateAddressList checks a comma-separated list of addresses
+func validateAddressList(value string) error {
+	for _, addr := range strings.Split(value, ",") {
+		if err := validateAddress(strings.TrimSpace(addr)); err != nil {
+			return fmt.Errorf("lists %s, which %v", strings.TrimSpace(addr), err)
+		}
+	}
+	return nil
+}
+
+func validateHTTPURL(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("is not an http(s) URL")
+	}
+	return nil
+}
+
+// githubHandlePattern matches a GitHub user or organization name: letters,
+// digits and single hyphens, at most 39 characters
+var githubHandlePattern = regexp.MustCompile(`^[A-Za-z0-9](-?[A-Za-z0-9]){0,38}$`)
+
+// validateGitHub accepts a GitHub handle or an http(s) URL of a GitHub user,
+// organization or repository
+func validateGitHub(value string) error {
+	if value == "" || githubHandlePattern.MatchString(value) {
+		return nil
+	}
+	if err := validateHTTPURL(value); err != nil {
+		return errors.New("is not a GitHub handle or URL")
+	}
+	u, _ := url.Parse(value)
+	owner := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)[0]
+	if host := strings.ToLower(u.Hostname()); (host != "github.com" && host != "www.github.com") || !githubHandlePattern.MatchString(owner) {
+		return errors.New("is not a GitHub handle or URL")
+	}
+	return nil
+}
+
+func validatePort(value string) error {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > 65535 {
+		return errors.New("is not a port (1-65535)")
+	}
+	return nil
+}
+
+// validateMinutes checks an interval given in whole minutes, bare or as a
+// duration
+func validateMinutes(value string) error {
+	if _, err := parseMinutes(value); err != nil {
+		return errors.New("is not a whole number of minutes of at least 1")
+	}
+	return nil
+}
+
+// validateNetworkList checks a comma-separated list of knownNetworks names
+func validateNetworkList(value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, name := range strings.Split(value, ",") {
+		if _, ok := knownNetworks[strings.TrimSpace(name)]; !ok {
+			return fmt.Errorf("names unknown network %q (known: %s)", strings.TrimSpace(name), strings.Join(sortedKeys(knownNetworks), ", "))
+		}
+	}
+	return nil
+}
+
+func validateOneOf(allowed []string) fieldValidator {
+	return func(value string) error {
+		if !containsString(allowed, value) {
+			return fmt.Errorf("is not one of %s", strings.Join(allowed, ", "))
+		}
+		return nil
+	}
+}
+
+func validateRange(min, max float64) fieldValidator {
+	return func(value string) error {
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || f < min || f > max {
+			return fmt.Errorf("is not a number between %g and %g", min, max)
+		}
+		return nil
+	}
+}
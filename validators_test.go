@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldValidators(t *testing.T) {
+	key := "0x120e011fB8a12bfcB61e5c1d751C26A5D33Aae91"
+	for _, tt := range []struct {
+		key, good, bad, msg string
+	}{
+		{"wallet.address", key, "0x123", "is not a 0x-prefixed 40 hex digit address"},
+		{"wallet.address", key, strings.ToLower(key[:3]) + strings.ToUpper(key[3:]), "fails the EIP-55 checksum"},
+		{"wallet.networks", "ethereum, base", "ethereum,solana", `names unknown network "solana"`},
+		{"security.whitelisted_addresses", key, key + ",0xnope", "lists 0xnope, which is not"},
+		{"monitoring.webhook_url", "https://hooks.example.com/a", "ftp://hooks.example.com", "is not an http(s) URL"},
+		{"agent.website", "https://arithmos.dev", "arithmos.dev", "is not an http(s) URL"},
+		{"agent.github", "https://github.com/arithmosquillsworth/agent", "https://gitlab.com/arithmos", "is not a GitHub handle or URL"},
+		{"agent.github", "arithmosquillsworth", "-bad-handle", "is not a GitHub handle or URL"},
+		{"monitoring.dashboard_port", "8080", "70000", "is not a port (1-65535)"},
+		{"monitoring.check_interval_minutes", "1h", "90s", "is not a whole number of minutes of at least 1"},
+		{"monitoring.rate_limit.backoff", "linear", "random", "is not one of"},
+		{"observability.sample_rate", "0.25", "1.5", "is not a number between 0 and 1"},
+		{"observability.log_level", "debug", "loud", "is not one of"},
+	} {
+		if err := validateFieldValue(tt.key, tt.good); err != nil {
+			t.Errorf("%s %q: %v", tt.key, tt.good, err)
+		}
+		err := validateFieldValue(tt.key, tt.bad)
+		if err == nil {
+			t.Errorf("%s accepted %q", tt.key, tt.bad)
+			continue
+		}
+		if want := "invalid value for " + tt.key + ": "; !strings.HasPrefix(err.Error(), want) || !strings.Contains(err.Error(), tt.msg) {
+			t.Errorf("%s %q: error %q, want %q ... %q", tt.key, tt.bad, err, want, tt.msg)
+		}
+	}
+
+	// optional fields can be unset, and deprecated names use the new field's validator
+	for _, key := range []string{"agent.website", "agent.github", "monitoring.webhook_url"} {
+		if err := validateFieldValue(key, ""); err != nil {
+			t.Errorf("unsetting %s: %v", key, err)
+		}
+	}
+	if err := validateFieldValue("monitoring.check_interval", "0"); err == nil {
+		t.Error("the deprecated check_interval key skipped validation")
+	}
+}
+
+func TestSetRunsFieldValidator(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	before := e.read(e.configPath())
+	out := e.mustFail("set", "monitoring.dashboard_port", "99999")
+	assertContains(t, out, `invalid value for monitoring.dashboard_port: "99999" is not a port (1-65535)`)
+	if e.read(e.configPath()) != before {
+		t.Error("a rejected value was saved")
+	}
+	e.mustRun("set", "monitoring.dashboard_port", "9000")
+
+	// validate reports the same message for a value edited in by hand
+	e.editConfig(func(config map[string]interface{}) {
+		section(config, "agent")["github"] = "https://gitlab.com/arithmos"
+	})
+	out, _ = e.run("validate")
+	assertContains(t, out, `Invalid value for agent.github: "https://gitlab.com/arithmos" is not a GitHub handle or URL`)
+}
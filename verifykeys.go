@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// keyProbe asks a provider whether an API key is accepted, using a cheap
+// read-only endpoint
+type keyProbe struct {
+	Key   string
+	Label string
+	Build func(ctx context.Context, secret string) (*http.Request, error)
+	// Check interprets a 200 response body; nil means any 200 is a pass
+	Check func(body []byte) error
+}
+
+var keyProbes = []keyProbe{
+	{Key: "api_keys.etherscan", Label: "Etherscan", Build: explorerKeyRequest("https://api.etherscan.io/api"), Check: checkExplorerResponse},
+	{Key: "api_keys.basescan", Label: "Basescan", Build: explorerKeyRequest("https://api.basescan.org/api"), Check: checkExplorerResponse},
+	{Key: "api_keys.openai", Label: "OpenAI", Build: func(ctx context.Context, secret string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.openai.com/v1/models", nil)
+		if err == nil {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		return req, err
+	}},
+	{Key: "api_keys.anthropic", Label: "Anthropic", Build: func(ctx context.Context, secret string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.anthropic.com/v1/models", nil)
+		if err == nil {
+			req.Header.Set("x-api-key", secret)
+			req.Header.Set("anthropic-version", "2023-06-01")
+		}
+		return req, err
+	}},
+	{Key: "api_keys.discord", Label: "Discord", Build: func(ctx context.Context, secret string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://discord.com/api/v10/users/@me", nil)
+		if err == nil {
+			req.Header.Set("Authorization", "Bot "+secret)
+		}
+		return req, err
+	}},
+}
+
+func explorerKeyRequest(endpoint string) func(context.Context, string) (*http.Request, error) {
+	return func(ctx context.Context, secret string) (*http.Request, error) {
+		query := url.Values{"module": {"stats"}, "action": {"ethprice"}, "apikey": {secret}}
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	}
+}
+
+// checkExplorerResponse reads the Etherscan-style envelope, which reports a
+// bad key with status "0" and HTTP 200
+func checkExplorerResponse(body []byte) error {
+	var resp struct {
+		Status string `json:"status"`
+		Result any    `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("unexpected response: %v", err)
+	}
+	if resp.Status != "1" {
+		return fmt.Errorf("rejected: %v", resp.Result)
+	}
+	return nil
+}
+
+// keyChecks builds a network check for every API key set in config
+func keyChecks(config AgentConfig, timeout time.Duration) []networkCheck {
+	client := newHTTPClient(timeout)
+	var checks []networkCheck
+	for _, probe := range keyProbes {
+		probe := probe
+		secret := apiKeyValue(config, probe.Key)
+		if secret == "" {
+			continue
+		}
+		checks = append(checks, networkCheck{Name: probe.Key, Run: func(ctx context.Context) error {
+			req, err := probe.Build(ctx, secret)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if urlErr, ok := err.(*url.Error); ok {
+				// The URL may carry the key as a query parameter
+				return urlErr.Err
+			} else if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+			switch {
+			case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+				return fmt.Errorf("%s rejected the key (%d)", probe.Label, resp.StatusCode)
+			case resp.StatusCode != http.StatusOK:
+				return fmt.Errorf("%s responded %d %s", probe.Label, resp.StatusCode, http.StatusText(resp.StatusCode))
+			case probe.Check != nil:
+				return probe.Check(body)
+			}
+			return nil
+		}})
+	}
+	return checks
+}
+
+// checkFlags registers the flags shared by commands that run network checks
+func checkFlags(fs *flag.FlagSet) (*time.Duration, *int, *bool) {
+	timeout := timeoutFlag(fs)
+	workers := fs.Int("workers", defaultCheckWorkers, "number of checks to run at once")
+	failFast := fs.Bool("fail-fast", false, "stop at the first failing check")
+	return timeout, workers, failFast
+}
+
+// verifyKeysCommand checks every configured API key against its provider
+func verifyKeysCommand(args []string) {
+	fs := flag.NewFlagSet("verify-keys", flag.ExitOnError)
+	timeout, workers, failFast := checkFlags(fs)
+	parseFlags(fs, args)
+
+	config := loadEffectiveConfig()
+	checks := keyChecks(config, *timeout)
+	if len(checks) == 0 {
+		fmt.Println("No API keys configured")
+		return
+	}
+
+	fmt.Printf("🔑 Verifying %d API key(s)...\n", len(checks))
+	fmt.Println()
+	ctx, cancel := networkContext(*timeout)
+	defer cancel()
+	if !printCheckResults(runNetworkChecks(ctx, checks, *workers, *failFast), *timeout) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata, injected at build time:
+//
+//	go build -ldflags "-X main.buildCommit=$(git rev-parse --short HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// The version constant stays the source of truth for the release version.
+var (
+	buildCommit string
+	buildDate   string
+)
+
+// versionInfo is the output of `acm version --json`
+type versionInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+	Go      string `json:"go"`
+}
+
+// currentVersion fills in whatever ldflags didn't set from the VCS stamp Go
+// records in the binary, and "unknown" past that
+func currentVersion() versionInfo {
+	info := versionInfo{Version: version, Commit: buildCommit, Date: buildDate, Go: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+				if len(info.Commit) > 12 {
+					info.Commit = info.Commit[:12]
+				}
+			case s.Key == "vcs.time" && info.Date == "":
+				info.Date = s.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}
+
+func versionCommand(args []string) {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print version, commit, build date and Go version as JSON")
+	parseFlags(fs, args)
+
+	if *asJSON {
+		data, _ := json.Marshal(currentVersion())
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Printf("agent-config-manager v%s\n", version)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+)
+
+func TestVersionJSON(t *testing.T) {
+	out := newTestEnv(t).mustRun("version", "--json")
+	var info map[string]string
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if info["version"] != version || info["go"] != runtime.Version() {
+		t.Errorf("version = %q, go = %q, want %q and %q", info["version"], info["go"], version, runtime.Version())
+	}
+	if info["commit"] == "" || info["date"] == "" {
+		t.Errorf("commit = %q, date = %q, want a value or unknown", info["commit"], info["date"])
+	}
+	assertContains(t, newTestEnv(t).mustRun("version"), "agent-config-manager v"+version+"\n")
+}
+
+func TestCurrentVersionLdflags(t *testing.T) {
+	saved := [...]string{buildCommit, buildDate}
+	t.Cleanup(func() { buildCommit, buildDate = saved[0], saved[1] })
+	buildCommit, buildDate = "6305b7f", "2026-10-14T09:00:00Z"
+
+	info := currentVersion()
+	if info.Commit != "6305b7f" || info.Date != "2026-10-14T09:00:00Z" {
+		t.Errorf("currentVersion = %+v, want the injected commit and date", info)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// walletCommand handles `acm wallet <subcommand>`
+func walletCommand(args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: acm wallet balance [--json] [--timeout 10s] | acm wallet validate-signer")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "balance":
+		walletBalance(args[1:])
+	case "validate-signer":
+		walletValidateSigner(args[1:])
+	default:
+		fmt.Printf("❌ Unknown wallet command: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// networkBalance is the balance of wallet.address on one network
+type networkBalance struct {
+	Network    string `json:"network"`
+	Status     string `json:"status"` // "ok", "skipped" or "error"
+	BalanceWei string `json:"balance_wei,omitempty"`
+	BalanceETH string `json:"balance_eth,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// balanceReport is the output of `acm wallet balance --json`
+type balanceReport struct {
+	Address  string           `json:"address"`
+	Networks []networkBalance `json:"networks"`
+}
+
+// walletBalance fetches the native balance of wallet.address on every
+// configured network, concurrently, from each chain's explorer API
+func walletBalance(args []string) {
+	fs := flag.NewFlagSet("wallet balance", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print balances as JSON")
+	timeout := timeoutFlag(fs)
+	parseFlags(fs, args)
+
+	config := loadEffectiveConfig()
+	if config.Wallet.Address == "" {
+		fmt.Println("❌ No wallet address configured")
+		fmt.Println("   Set one with: acm set wallet.address <address>")
+		os.Exit(1)
+	}
+	if len(config.Wallet.Networks) == 0 {
+		fmt.Println("No networks configured")
+		return
+	}
+
+	report := balanceReport{Address: config.Wallet.Address}
+	client := newHTTPClient(*timeout)
+	var checks []networkCheck
+	var pending []int
+	for _, name := range config.Wallet.Networks {
+		balance := networkBalance{Network: name, Status: "skipped"}
+		info, known := knownNetworks[name]
+		switch {
+		case !known:
+			balance.Reason = "unknown network"
+		case info.ExplorerAPI == "":
+			balance.Reason = "no explorer API key is configurable for this network"
+		case apiKeyValue(config, info.APIKey) == "":
+			balance.Reason = info.APIKey + " not set"
+		default:
+			i := len(report.Networks)
+			apiKey := apiKeyValue(config, info.APIKey)
+			checks = append(checks, networkCheck{Name: name, Run: func(ctx context.Context) error {
+				wei, err := fetchBalance(ctx, client, info.ExplorerAPI, apiKey, config.Wallet.Address)
+				report.Networks[i].BalanceWei = wei
+				return err
+			}})
+			pending = append(pending, i)
+		}
+		report.Networks = append(report.Networks, balance)
+	}
+
+	ctx, cancel := networkContext(*timeout)
+	defer cancel()
+	failed := false
+	for n, result := range runNetworkChecks(ctx, checks, defaultCheckWorkers, false) {
+		balance := &report.Networks[pending[n]]
+		if result.Err != nil {
+			balance.Status, balance.BalanceWei = "error", ""
+			balance.Reason = describeNetworkError(result.Err, *timeout)
+			failed = true
+			continue
+		}
+		eth, err := weiToETH(balance.BalanceWei)
+		if err != nil {
+			balance.Status, balance.BalanceWei, balance.Reason = "error", "", err.Error()
+			failed = true
+			continue
+		}
+		balance.Status, balance.BalanceETH = "ok", eth
+	}
+
+	if *asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("💰 Balances for %s\n", report.Address)
+		fmt.Println()
+		for _, b := range report.Networks {
+			switch b.Status {
+			case "ok":
+				fmt.Printf("  %-14s %s ETH\n", b.Network, b.BalanceETH)
+			case "skipped":
+				fmt.Printf("  %-14s ⏭️  skipped: %s\n", b.Network, b.Reason)
+			default:
+				fmt.Printf("  %-14s ❌ %s\n", b.Network, b.Reason)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// fetchBalance asks an Etherscan-style API for address's balance in wei
+func fetchBalance(ctx context.Context, client *http.Client, api, apiKey, address string) (string, error) {
+	query := url.Values{
+		"module":  {"account"},
+		"action":  {"balance"},
+		"address": {address},
+		"tag":     {"latest"},
+		"apikey":  {apiKey},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if urlErr, ok := err.(*url.Error); ok {
+		// Don't print the key from the query string
+		return "", urlErr.Err
+	} else if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("explorer responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var reply struct {
+		Status string `json:"status"`
+		Result string `json:"result"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&reply); err != nil {
+		return "", fmt.Errorf("unexpected explorer response: %v", err)
+	}
+	if reply.Status != "1" {
+		return "", fmt.Errorf("explorer error: %s", reply.Result)
+	}
+	return reply.Result, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// explorerServer answers balance queries with the balance for each API key
+func explorerServer(t *testing.T, balances map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("module") != "account" || query.Get("action") != "balance" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		balance, ok := balances[query.Get("apikey")]
+		if !ok {
+			io.WriteString(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+			return
+		}
+		io.WriteString(w, `{"status":"1","message":"OK","result":"`+balance+`"}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// useExplorer points the named networks' explorer APIs at url
+func useExplorer(t *testing.T, url string, networks ...string) {
+	t.Helper()
+	saved := knownNetworks
+	knownNetworks = map[string]networkInfo{}
+	for name, info := range saved {
+		knownNetworks[name] = info
+	}
+	for _, name := range networks {
+		info := knownNetworks[name]
+		info.ExplorerAPI = url
+		knownNetworks[name] = info
+	}
+	t.Cleanup(func() { knownNetworks = saved })
+}
+
+func TestWalletBalancePerNetwork(t *testing.T) {
+	isolatePaths(t)
+	for _, env := range apiKeyEnvVars {
+		for _, name := range env.Names {
+			t.Setenv(name, "")
+		}
+	}
+	server := explorerServer(t, map[string]string{"etherscan-test-key-123456": "1500000000000000000"})
+	useExplorer(t, server.URL, "ethereum", "sepolia", "base")
+	config := defaultConfig()
+	config.APIKeys.Etherscan = "etherscan-test-key-123456"
+	config.Wallet.Networks = []string{"ethereum", "sepolia", "base", "arbitrum"}
+	writeTestConfig(t, config)
+
+	var report balanceReport
+	out := captureStdout(t, func() { walletBalance([]string{"--json"}) })
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	want := []networkBalance{
+		{Network: "ethereum", Status: "ok", BalanceWei: "1500000000000000000", BalanceETH: "1.5"},
+		{Network: "sepolia", Status: "ok", BalanceWei: "1500000000000000000", BalanceETH: "1.5"},
+		{Network: "base", Status: "skipped", Reason: "api_keys.basescan not set"},
+		{Network: "arbitrum", Status: "skipped", Reason: "no explorer API key is configurable for this network"},
+	}
+	if report.Address != config.Wallet.Address || len(report.Networks) != len(want) {
+		t.Fatalf("report = %+v", report)
+	}
+	for i, balance := range report.Networks {
+		if balance != want[i] {
+			t.Errorf("network %d = %+v, want %+v", i, balance, want[i])
+		}
+	}
+
+	out = captureStdout(t, func() { walletBalance(nil) })
+	assertContains(t, out, "ethereum       1.5 ETH", "base           ⏭️  skipped: api_keys.basescan not set")
+}
+
+func TestFetchBalanceErrors(t *testing.T) {
+	server := explorerServer(t, map[string]string{"good-key": "42"})
+	client := newHTTPClient(defaultNetworkTimeout)
+	ctx, cancel := networkContext(defaultNetworkTimeout)
+	defer cancel()
+
+	if wei, err := fetchBalance(ctx, client, server.URL, "good-key", testAddrA); err != nil || wei != "42" {
+		t.Errorf("fetchBalance = %s, %v, want 42", wei, err)
+	}
+	if _, err := fetchBalance(ctx, client, server.URL, "bad-key", testAddrA); err == nil || err.Error() != "explorer error: Invalid API Key" {
+		t.Errorf("bad key error = %v", err)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	if _, err := fetchBalance(ctx, client, failing.URL, "good-key", testAddrA); err == nil || err.Error() != "explorer responded 502 Bad Gateway" {
+		t.Errorf("502 error = %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+const (
+	// watchInterval is how often `get --watch` checks the config file
+	watchInterval = 200 * time.Millisecond
+	// watchDebounce is how long the file must stay unchanged before it is
+	// reread, so an editor's save in several writes prints one line
+	watchDebounce = 300 * time.Millisecond
+)
+
+// watchValue handles `acm get --watch <key>`: it prints the value, then a
+// new line each time the config file changes and the value differs, until
+// interrupted. It reloads through the same cache as `acm serve`.
+func watchValue(key string) {
+	cache := &configCache{path: getConfigPath()}
+	value, err := watchedValue(cache, key)
+	if err != nil {
+		exitWith(err, "❌ %v\n", err)
+	}
+	fmt.Println(value)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	modTime, size := cache.modTime, cache.size
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			info, err := os.Stat(cache.path)
+			if err != nil {
+				continue
+			}
+			if !info.ModTime().Equal(modTime) || info.Size() != size {
+				modTime, size, changedAt = info.ModTime(), info.Size(), now
+				continue
+			}
+			if changedAt.IsZero() || now.Sub(changedAt) < watchDebounce {
+				continue
+			}
+			changedAt = time.Time{}
+			next, err := watchedValue(cache, key)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+				continue
+			}
+			if next != value {
+				value = next
+				fmt.Println(value)
+			}
+		}
+	}
+}
+
+// watchedValue is key's effective value as `acm get` prints it, secrets
+// masked
+func watchedValue(cache *configCache, key string) (string, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	config, err := cache.current()
+	if err != nil {
+		return "", err
+	}
+	if err := expandConfigEnvRefs(&config); err != nil {
+		return "", err
+	}
+	resolveSecrets(&config)
+	value, err := valueString(&config, key)
+	if err != nil {
+		return "", fmt.Errorf("Unknown key: %s", key)
+	}
+	return value, nil
+}
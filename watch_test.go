@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// startWatch runs `acm get --watch key` in the background and returns its
+// output lines as they are printed
+func (e *testEnv) startWatch(key string) (*exec.Cmd, <-chan string) {
+	e.t.Helper()
+	data, _ := json.Marshal([]string{"get", "--watch", key})
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(append([]string{}, e.env...), testArgsEnv+"="+string(data))
+	cmd.Dir = e.home
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		e.t.Fatal(err)
+	}
+	e.t.Cleanup(func() { cmd.Process.Kill() })
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	return cmd, lines
+}
+
+func nextLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			t.Fatal("get --watch exited")
+		}
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("get --watch printed nothing")
+	}
+	return ""
+}
+
+func TestGetWatchPrintsChanges(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs SIGINT")
+	}
+	e := newTestEnv(t)
+	e.init()
+	cmd, lines := e.startWatch("wallet.daily_limit")
+	if line := nextLine(t, lines); line != "0.5" {
+		t.Fatalf("first line = %q, want the current value", line)
+	}
+
+	e.mustRun("set", "wallet.daily_limit", "1.25")
+	if line := nextLine(t, lines); line != "1.25" {
+		t.Errorf("after set = %q, want 1.25", line)
+	}
+
+	// a change to another key prints nothing
+	e.mustRun("set", "agent.name", "watched-agent")
+	e.mustRun("set", "wallet.daily_limit", "2")
+	if line := nextLine(t, lines); line != "2" {
+		t.Errorf("after the second set = %q, want 2", line)
+	}
+
+	cmd.Process.Signal(os.Interrupt)
+	done := make(chan error)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("get --watch exited with %v on SIGINT", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("get --watch didn't exit on SIGINT")
+	}
+}
+
+func TestGetWatchMasksSecrets(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", "watched-etherscan-key-123456")
+	_, lines := e.startWatch("api_keys.etherscan")
+	if line := nextLine(t, lines); line == "watched-etherscan-key-123456" || line == "" {
+		t.Errorf("get --watch printed %q, want it masked", line)
+	}
+
+	assertContains(t, e.mustFail("get", "--watch", "no.such.key"), "Unknown key: no.such.key")
+}
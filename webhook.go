@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// webhookCommand handles `acm webhook <subcommand>`
+func webhookCommand(args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: acm webhook test [--format auto|discord|generic] [--payload @file] [--timeout 10s]")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "test":
+		webhookTest(args[1:])
+	default:
+		fmt.Printf("❌ Unknown webhook command: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+func webhookTest(args []string) {
+	fs := flag.NewFlagSet("webhook test", flag.ExitOnError)
+	format := fs.String("format", "auto", "payload shape: auto, discord or generic")
+	payloadArg := fs.String("payload", "", "custom payload, as @file (overrides monitoring.webhook_template)")
+	timeout := timeoutFlag(fs)
+	parseFlags(fs, args)
+
+	config := loadEffectiveConfig()
+	url := config.Monitoring.WebhookURL
+	if url == "" {
+		fmt.Println("❌ No webhook configured")
+		fmt.Println("   Set one with: acm set monitoring.webhook_url <url>")
+		os.Exit(1)
+	}
+
+	shape := *format
+	if shape == "auto" {
+		shape = detectWebhookFormat(url)
+	}
+	if shape != "discord" && shape != "generic" {
+		fmt.Printf("❌ Unknown webhook format: %s (use discord or generic)\n", shape)
+		os.Exit(1)
+	}
+
+	var payload []byte
+	if *payloadArg != "" {
+		if !strings.HasPrefix(*payloadArg, "@") {
+			fmt.Println("❌ --payload expects a file reference like @alert.json")
+			os.Exit(1)
+		}
+		data, err := os.ReadFile(expandPath(strings.TrimPrefix(*payloadArg, "@")))
+		if err != nil {
+			fmt.Printf("❌ Failed to read payload: %v\n", err)
+			os.Exit(1)
+		}
+		if !json.Valid(data) {
+			fmt.Println("❌ Payload file is not valid JSON")
+			os.Exit(1)
+		}
+		payload = data
+	} else if src := config.Monitoring.WebhookTemplate; src != "" {
+		alert, _ := sampleAlertPayload(config, "generic").(map[string]interface{})
+		data, err := renderWebhookTemplate(src, alert)
+		if err != nil {
+			fmt.Printf("❌ monitoring.webhook_template %v\n", err)
+			os.Exit(1)
+		}
+		payload, shape = data, "templated"
+	} else {
+		data, err := json.Marshal(sampleAlertPayload(config, shape))
+		if err != nil {
+			fmt.Printf("❌ Failed to build payload: %v\n", err)
+			os.Exit(1)
+		}
+		payload = data
+	}
+
+	fmt.Printf("📡 Sending %s test alert to configured webhook...\n", shape)
+	secret := config.Monitoring.WebhookSecret
+	if secret != "" {
+		fmt.Printf("🔏 Signing the payload (%s header)\n", signatureHeader)
+	}
+
+	ctx, cancel := networkContext(*timeout)
+	defer cancel()
+
+	status, latency, body, err := postWebhook(ctx, newHTTPClient(*timeout), url, payload, secret)
+	if err != nil {
+		fmt.Printf("❌ Webhook request failed: %s\n", describeNetworkError(err, *timeout))
+		os.Exit(1)
+	}
+
+	if status < 200 || status > 299 {
+		fmt.Printf("❌ Webhook responded %d %s in %s\n", status, http.StatusText(status), latency.Round(time.Millisecond))
+		if body != "" {
+			fmt.Printf("   %s\n", body)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Webhook responded %d %s in %s\n", status, http.StatusText(status), latency.Round(time.Millisecond))
+}
+
+// detectWebhookFormat guesses the payload shape from the webhook URL
+func detectWebhookFormat(url string) string {
+	if strings.Contains(url, "discord.com/api/webhooks") || strings.Contains(url, "discordapp.com/api/webhooks") {
+		return "discord"
+	}
+	return "generic"
+}
+
+// sampleAlertPayload builds a payload resembling a real daily-limit alert
+func sampleAlertPayload(config AgentConfig, shape string) interface{} {
+	spent := config.Wallet.DailyLimit * 1.1
+	message := fmt.Sprintf("Daily limit exceeded: spent %s ETH of %s ETH limit", formatETHAmount(spent), formatETHAmount(config.Wallet.DailyLimit))
+
+	if shape == "discord" {
+		return map[string]interface{}{
+			"username": "Agent Config Manager",
+			"content":  "🧪 Test alert from acm",
+			"embeds": []map[string]interface{}{
+				{
+					"title":       "⚠️ Daily limit alert (test)",
+					"description": message,
+					"color":       0xF1C40F,
+					"fields": []map[string]interface{}{
+						{"name": "Agent", "value": config.Agent.Name, "inline": true},
+						{"name": "Wallet", "value": config.Wallet.Address, "inline": true},
+					},
+					"timestamp": time.Now().UTC().Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"event":           "daily_limit_exceeded",
+		"severity":        "warning",
+		"test":            true,
+		"agent":           config.Agent.ID,
+		"wallet":          config.Wallet.Address,
+		"spent_eth":       spent,
+		"daily_limit_eth": config.Wallet.DailyLimit,
+		"message":         message,
+		"timestamp":       time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// signatureHeader carries the HMAC-SHA256 of the request body, keyed with
+// monitoring.webhook_secret, as "sha256=<hex>"
+const signatureHeader = "X-Signature"
+
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// postWebhook sends the payload, signed when secret is set, and returns the
+// status code, latency and a short excerpt of the response body
+func postWebhook(ctx context.Context, client *http.Client, url string, payload []byte, secret string) (int, time.Duration, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(signatureHeader, signPayload(secret, payload))
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return 0, latency, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return resp.StatusCode, latency, strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// webhookRequest holds what a test webhook server last received. The
+// handler runs on the server's goroutine, so access goes through mu.
+type webhookRequest struct {
+	mu        sync.Mutex
+	body      []byte
+	signature string
+}
+
+func (h *webhookRequest) record(r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.body, h.signature = body, r.Header.Get("X-Signature")
+}
+
+func (h *webhookRequest) Body() []byte {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.body
+}
+
+func (h *webhookRequest) Signature() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.signature
+}
+
+// webhookServer records the last request body and answers with status
+func webhookServer(t *testing.T, status int) (*httptest.Server, *webhookRequest) {
+	t.Helper()
+	last := &webhookRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		last.record(r)
+		w.WriteHeader(status)
+		io.WriteString(w, "nope")
+	}))
+	t.Cleanup(server.Close)
+	return server, last
+}
+
+func TestWebhookTestSendsSampleAlert(t *testing.T) {
+	server, last := webhookServer(t, http.StatusNoContent)
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.webhook_url", server.URL+"/hook")
+
+	out := e.mustRun("webhook", "test")
+	assertContains(t, out, "generic test alert", "204 No Content")
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(last.Body(), &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v\n%s", err, last.Body())
+	}
+	if payload["event"] != "daily_limit_exceeded" || payload["test"] != true {
+		t.Errorf("event = %v, test = %v, want a daily_limit_exceeded test alert", payload["event"], payload["test"])
+	}
+	if payload["wallet"] != "0x120e011fB8a12bfcB61e5c1d751C26A5D33Aae91" {
+		t.Errorf("wallet = %v", payload["wallet"])
+	}
+}
+
+func TestWebhookTestPayloadOverride(t *testing.T) {
+	server, last := webhookServer(t, http.StatusOK)
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.webhook_url", server.URL)
+	payload := filepath.Join(e.home, "alert.json")
+	e.write(payload, `{"custom": true}`)
+
+	e.mustRun("webhook", "test", "--payload", "@"+payload)
+	if string(last.Body()) != `{"custom": true}` {
+		t.Errorf("sent %s, want the payload file", last.Body())
+	}
+
+	os.WriteFile(payload, []byte("not json"), 0600)
+	assertContains(t, e.mustFail("webhook", "test", "--payload", "@"+payload), "not valid JSON")
+}
+
+func TestWebhookTestNon2xx(t *testing.T) {
+	server, _ := webhookServer(t, http.StatusInternalServerError)
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.webhook_url", server.URL)
+
+	out := e.mustFail("webhook", "test")
+	assertContains(t, out, "500 Internal Server Error", "nope")
+}
+
+func TestWebhookTestWithoutURL(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	assertContains(t, e.mustFail("webhook", "test"), "No webhook configured")
+}
+
+func TestDetectWebhookFormat(t *testing.T) {
+	for url, want := range map[string]string{
+		"https://discord.com/api/webhooks/1/abc":    "discord",
+		"https://discordapp.com/api/webhooks/1/abc": "discord",
+		"https://hooks.example.com/alert":           "generic",
+	} {
+		if got := detectWebhookFormat(url); got != want {
+			t.Errorf("detectWebhookFormat(%s) = %s, want %s", url, got, want)
+		}
+	}
+}
+
+func TestWebhookTestSignsPayload(t *testing.T) {
+	server, last := webhookServer(t, http.StatusOK)
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.webhook_url", server.URL)
+
+	e.mustRun("webhook", "test")
+	if last.Signature() != "" {
+		t.Errorf("unsigned alert has X-Signature %q", last.Signature())
+	}
+
+	e.mustRun("set", "monitoring.webhook_secret", "whsec-test-secret-123456")
+	out := e.mustRun("webhook", "test")
+	assertNotContains(t, out, "whsec-test-secret-123456")
+	mac := hmac.New(sha256.New, []byte("whsec-test-secret-123456"))
+	mac.Write(last.Body())
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); last.Signature() != want {
+		t.Errorf("X-Signature = %q, want %q", last.Signature(), want)
+	}
+}
+
+func TestWebhookSecretHandling(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.webhook_url", "https://hooks.example.com/alert")
+	out, _ := e.run("validate")
+	assertContains(t, out, "monitoring.webhook_secret is not set, so alerts to the webhook are unsigned")
+
+	e.mustRun("set", "monitoring.webhook_secret", "whsec-test-secret-123456")
+	out, _ = e.run("validate")
+	assertNotContains(t, out, "alerts to the webhook are unsigned")
+	for _, args := range [][]string{{"get", "monitoring.webhook_secret"}, {"show"}, {"get", "--all"}} {
+		assertNotContains(t, e.mustRun(args...), "whsec-test-secret-123456")
+	}
+
+	// Discord doesn't verify signatures, so there is nothing to warn about
+	e.mustRun("set", "monitoring.webhook_secret", "")
+	e.mustRun("set", "monitoring.webhook_url", "https://discord.com/api/webhooks/1/abc")
+	out, _ = e.run("validate")
+	assertNotContains(t, out, "alerts to the webhook are unsigned")
+
+	config := defaultConfig()
+	config.Monitoring.WebhookSecret = "whsec-test-secret-123456"
+	if got := toolConfigs(t, config)["wallet-monitor.json"]["webhook_secret"]; got != "whsec-test-secret-123456" {
+		t.Errorf("wallet-monitor webhook_secret = %v", got)
+	}
+}
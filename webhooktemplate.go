@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// webhookTemplateName names the template in parse and exec errors
+const webhookTemplateName = "webhook_template"
+
+// webhookTemplateFuncs are the helpers available to
+// monitoring.webhook_template on top of the `acm template` ones; json
+// encodes a value so strings are quoted and escaped
+var webhookTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// renderWebhookTemplate executes a webhook payload template against an
+// alert (the fields of the generic payload: event, severity, message, ...)
+// and returns the payload compacted. Parse and exec errors name the line and
+// quote it.
+func renderWebhookTemplate(src string, alert map[string]interface{}) ([]byte, error) {
+	tmpl, err := template.New(webhookTemplateName).Funcs(templateFuncs).Funcs(webhookTemplateFuncs).Option("missingkey=error").Parse(src)
+	if err != nil {
+		return nil, templateLineError(src, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, alert); err != nil {
+		return nil, templateLineError(src, err)
+	}
+
+	var payload bytes.Buffer
+	if err := json.Compact(&payload, b.Bytes()); err != nil {
+		return nil, fmt.Errorf("renders invalid JSON: %v", err)
+	}
+	return payload.Bytes(), nil
+}
+
+// validateWebhookTemplate checks a template parses and renders valid JSON
+// for a sample alert. An empty template is valid: it sends the default
+// payload.
+func validateWebhookTemplate(src string) error {
+	if src == "" {
+		return nil
+	}
+	alert, _ := sampleAlertPayload(defaultConfig(), "generic").(map[string]interface{})
+	_, err := renderWebhookTemplate(src, alert)
+	return err
+}
+
+// templateErrorPattern matches the "template: name:line[:col]: message"
+// prefix text/template puts on its errors
+var templateErrorPattern = regexp.MustCompile(`^template: ` + webhookTemplateName + `:(\d+)(?::\d+)?: (.*)$`)
+
+// templateLineError rewrites a text/template error as "line N: message"
+// followed by the offending line
+func templateLineError(src string, err error) error {
+	m := templateErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	n, _ := strconv.Atoi(m[1])
+	msg := strings.TrimPrefix(m[2], fmt.Sprintf("executing %q at ", webhookTemplateName))
+	lines := strings.Split(src, "\n")
+	if n < 1 || n > len(lines) {
+		return fmt.Errorf("line %d: %s", n, msg)
+	}
+	return fmt.Errorf("line %d: %s: %s", n, msg, strings.TrimSpace(lines[n-1]))
+}
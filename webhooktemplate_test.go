@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func sampleAlert() map[string]interface{} {
+	alert, _ := sampleAlertPayload(defaultConfig(), "generic").(map[string]interface{})
+	return alert
+}
+
+func TestRenderWebhookTemplate(t *testing.T) {
+	src := "{\n  \"text\": {{json .message}},\n  \"level\": \"{{.severity}}\"\n}"
+	got, err := renderWebhookTemplate(src, sampleAlert())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"text":"Daily limit exceeded: spent 0.55 ETH of 0.5 ETH limit","level":"warning"}`
+	if string(got) != want {
+		t.Errorf("rendered %s, want %s", got, want)
+	}
+}
+
+func TestWebhookTemplateErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, src, want string
+	}{
+		{"parse error", "{\n  \"text\": {{json .message}\n}", `line 2: bad character U+007D '}': "text": {{json .message}`},
+		{"undefined field", "{\n  \"a\": 1,\n  \"text\": {{json .nope}}\n}", `line 3: <.nope>: map has no entry for key "nope": "text": {{json .nope}}`},
+		{"not JSON", "{{.message}}", "renders invalid JSON: "},
+	} {
+		err := validateWebhookTemplate(tc.src)
+		if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %s", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestWebhookTemplateConfig(t *testing.T) {
+	server, last := webhookServer(t, http.StatusOK)
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "monitoring.webhook_url", server.URL)
+	e.mustRun("set", "monitoring.webhook_secret", "whsec-test-secret-123456")
+
+	out := e.mustFail("set", "monitoring.webhook_template", `{"text": {{json .nope}}}`)
+	assertContains(t, out, "invalid value for monitoring.webhook_template: line 1: ")
+	e.mustRun("set", "monitoring.webhook_template", `{"text": {{json .message}}, "agent": {{json .agent}}}`)
+	assertContains(t, e.mustRun("show"), "custom template")
+	e.mustRun("set", "monitoring.webhook_template", "")
+	assertNotContains(t, e.mustRun("show"), "custom template")
+	e.mustRun("set", "monitoring.webhook_template", `{"text": {{json .message}}, "agent": {{json .agent}}}`)
+
+	e.mustRun("webhook", "test")
+	if want := `{"text":"Daily limit exceeded: spent 0.55 ETH of 0.5 ETH limit","agent":"arithmos-quillsworth"}`; string(last.Body()) != want {
+		t.Errorf("sent %s, want %s", last.Body(), want)
+	}
+
+	// a template edited into the file is caught by validate
+	e.editConfig(func(config map[string]interface{}) {
+		section(config, "monitoring")["webhook_template"] = "{{.message"
+	})
+	out, _ = e.run("validate")
+	assertContains(t, out, "monitoring.webhook_template line 1: ")
+	assertContains(t, e.mustFail("webhook", "test"), "monitoring.webhook_template line 1: ")
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// isYAML reports whether path should be read and written as YAML. A YAML
+// config is converted to JSON on load and back on save, so the same JSON
+// tags name every field and everything else treats it like any config.
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// configJSON turns the contents of a JSONC or YAML config into plain JSON
+func configJSON(path string, data []byte) ([]byte, error) {
+	switch {
+	case isYAML(path):
+		return yamlToJSON(data)
+	case isJSONC(path):
+		return stripJSONComments(data), nil
+	}
+	return data, nil
+}
+
+// yamlToJSON converts one YAML document to JSON, keeping keys in the order
+// written. Each key is placed on the line it has in the YAML, so duplicate
+// key warnings point at the right line. Timestamps stay strings, as written,
+// rather than being reparsed as dates.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, errors.New("YAML document is empty")
+	}
+	w := &yamlJSONWriter{line: 1}
+	if err := w.node(doc.Content[0]); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// yamlJSONWriter writes JSON for YAML nodes, tracking the current line
+type yamlJSONWriter struct {
+	buf  bytes.Buffer
+	line int
+}
+
+func (w *yamlJSONWriter) node(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.AliasNode:
+		return w.node(n.Alias)
+	case yaml.MappingNode:
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if key.Kind != yaml.ScalarNode || key.Value == "<<" {
+				return fmt.Errorf("line %d: only plain keys are supported", key.Line)
+			}
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			for ; w.line < key.Line; w.line++ {
+				w.buf.WriteByte('\n')
+			}
+			name, _ := json.Marshal(key.Value)
+			w.buf.Write(name)
+			w.buf.WriteByte(':')
+			if err := w.node(n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		w.buf.WriteByte('}')
+	case yaml.SequenceNode:
+		w.buf.WriteByte('[')
+		for i, item := range n.Content {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			if err := w.node(item); err != nil {
+				return err
+			}
+		}
+		w.buf.WriteByte(']')
+	case yaml.ScalarNode:
+		var value interface{} = n.Value
+		switch n.ShortTag() {
+		case "!!null":
+			value = nil
+		case "!!bool", "!!int", "!!float":
+			if err := n.Decode(&value); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", n.Line, err)
+		}
+		w.buf.Write(data)
+	default:
+		return fmt.Errorf("line %d: unsupported YAML node", n.Line)
+	}
+	return nil
+}
+
+// isYAML11Scalar reports whether a string that YAML 1.2 leaves alone would
+// be read as a hex number or boolean by a YAML 1.1 reader, such as an
+// address or the agent name "yes"
+func isYAML11Scalar(s string) bool {
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "on", "off":
+		return true
+	}
+	return strings.HasPrefix(strings.ToLower(s), "0x")
+}
+
+// splitYAMLHeader returns the comment block at the top of a YAML file, which
+// is kept when the config is saved. Other comments are lost.
+func splitYAMLHeader(data []byte) []byte {
+	end := 0
+	for end < len(data) {
+		next := bytes.IndexByte(data[end:], '\n')
+		if next < 0 {
+			next = len(data) - end - 1
+		}
+		line := bytes.TrimSpace(data[end : end+next+1])
+		if len(line) > 0 && line[0] != '#' {
+			break
+		}
+		end += next + 1
+	}
+	return data[:end]
+}
+
+// printConfigDocument prints config as a whole JSON or YAML document for
+// `acm show --format`, secrets masked
+func printConfigDocument(config AgentConfig, format string) {
+	for _, leaf := range configLeaves(&config) {
+		if isSecretKey(leaf.Key) {
+			leaf.Value.SetString(maskSecret(leaf.Value.String()))
+		}
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	switch {
+	case err != nil:
+	case format == "yaml":
+		data, err = jsonToYAML(data)
+	case format == "json":
+		data = append(data, '\n')
+	default:
+		fmt.Printf("❌ Unknown format: %s (use text, yaml or json)\n", format)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(string(data))
+}
+
+// jsonToYAML renders JSON as block-style YAML with the keys in the same
+// order. JSON is valid YAML, so it is parsed as such and restyled.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	restyleYAML(&doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// restyleYAML clears the flow and quoting styles JSON parses with, so the
+// encoder picks block style and only quotes strings that need it
+func restyleYAML(n *yaml.Node) {
+	n.Style = 0
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!str" && isYAML11Scalar(n.Value) {
+		n.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range n.Content {
+		restyleYAML(child)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlJSON converts a YAML config the way acm loads it, by its JSON tags
+func yamlJSON(t *testing.T, data string) []byte {
+	t.Helper()
+	out, err := yamlToJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, data)
+	}
+	return out
+}
+
+func TestYAMLConfigRoundTrip(t *testing.T) {
+	e := newTestEnv(t)
+	path := filepath.Join(filepath.Dir(e.configPath()), "config.yaml")
+	e.setenv("ACM_CONFIG", path)
+	e.mustRun("init")
+	e.mustRun("set", "api_keys.etherscan", testEtherscanKey)
+	e.mustRun("set", "wallet.address", testAddrA)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("config.yaml mode = %o, want 600", info.Mode().Perm())
+	}
+	data := e.read(path)
+	if strings.HasPrefix(strings.TrimSpace(data), "{") {
+		t.Fatalf("config.yaml saved as JSON:\n%s", data)
+	}
+	// hex strings stay strings rather than being read back as numbers
+	assertContains(t, data, "  address: \""+testAddrA+"\"\n", "  networks:\n    - ethereum\n")
+
+	var config AgentConfig
+	if err := json.Unmarshal(yamlJSON(t, data), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Wallet.Address != testAddrA || config.APIKeys.Etherscan != testEtherscanKey {
+		t.Errorf("config.yaml has wallet.address %q, api_keys.etherscan %q", config.Wallet.Address, config.APIKeys.Etherscan)
+	}
+	assertContains(t, e.mustRun("get", "wallet.address"), testAddrA)
+}
+
+func TestShowFormat(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("set", "api_keys.etherscan", testEtherscanKey)
+
+	out := e.mustRun("show", "--format", "yaml")
+	assertContains(t, out, "agent:\n  name: Arithmos\n")
+	var fromYAML AgentConfig
+	if err := json.Unmarshal(yamlJSON(t, out), &fromYAML); err != nil {
+		t.Fatalf("show --format yaml: %v\n%s", err, out)
+	}
+	out = e.mustRun("show", "--format", "json")
+	var fromJSON AgentConfig
+	if err := json.Unmarshal([]byte(out), &fromJSON); err != nil {
+		t.Fatalf("show --format json: %v\n%s", err, out)
+	}
+	if string(mustJSON(t, fromYAML)) != string(mustJSON(t, fromJSON)) {
+		t.Errorf("yaml and json show different configs")
+	}
+	// secrets stay masked in either format
+	if strings.Contains(out, testEtherscanKey) || fromYAML.APIKeys.Etherscan == testEtherscanKey {
+		t.Error("show --format printed an API key")
+	}
+	assertContains(t, e.mustFail("show", "--format", "toml"), "Unknown format: toml")
+}
+
+func TestExportYAML(t *testing.T) {
+	e := newTestEnv(t)
+	e.init()
+	e.mustRun("export", "--format", "yaml")
+	files, _ := filepath.Glob(filepath.Join(e.exportDir(), "*.yaml"))
+	if len(files) == 0 {
+		t.Fatal("export --format yaml wrote no .yaml files")
+	}
+	for _, file := range files {
+		var tool map[string]interface{}
+		if err := yaml.Unmarshal([]byte(e.read(file)), &tool); err != nil || len(tool) == 0 {
+			t.Errorf("%s: %v", filepath.Base(file), err)
+		}
+	}
+}
+
+func TestYAMLJSONConversion(t *testing.T) {
+	in := `{"b": {"z": "0x10", "a": [1, "yes", true]}, "a": null}`
+	out, err := jsonToYAML([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "b:\n  z: \"0x10\"\n  a:\n    - 1\n    - \"yes\"\n    - true\na: null\n"
+	if string(out) != want {
+		t.Errorf("jsonToYAML:\n%s\nwant\n%s", out, want)
+	}
+	back, err := yamlToJSON(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, orig interface{}
+	json.Unmarshal(back, &got)
+	json.Unmarshal([]byte(in), &orig)
+	if string(mustJSON(t, got)) != string(mustJSON(t, orig)) {
+		t.Errorf("round trip gave %s, want %s", back, in)
+	}
+	// keys keep the order they were written in
+	if !strings.HasPrefix(strings.Join(strings.Fields(string(back)), ""), `{"b":{"z"`) {
+		t.Errorf("key order lost: %s", back)
+	}
+}